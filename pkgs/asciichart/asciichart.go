@@ -48,20 +48,51 @@ func createEmpty(rows, width int) [][]string {
 	return plot
 }
 
-// Plot returns ascii graph for a series.
-func (ac *Chart) Plot(series []float64) string {
-	var logMaximum float64
+// seriesData is a series to plot and its index in the arguments of Plot
+type seriesData struct {
+	index  int
+	values []float64
+}
+
+// Plot returns ascii graph for one or more series. All of the series share
+// the axes, each series is drawn with its color from PlotConfig.SeriesColors
+// and a legend row is added when PlotConfig.Legends is set.
+func (ac *Chart) Plot(series ...[]float64) string {
 
-	if len(series) <= 0 {
-		return ""
-	}
 	config := &ac.config
 
-	if config.Width > 0 {
-		series = interpolateArray(series, config.Width)
+	// A Braille character holds two data points
+	points := config.Width
+	if config.Mode == PlotBraille {
+		points *= 2
+	}
+
+	// The empty series are skipped, the others keep their index for the
+	// color and the legend
+	data := make([]seriesData, 0, len(series))
+	for i, s := range series {
+		if len(s) <= 0 {
+			continue
+		}
+		if points > 0 {
+			s = interpolateArray(s, points)
+		}
+		data = append(data, seriesData{index: i, values: s})
+	}
+	if len(data) == 0 {
+		return ""
 	}
 
-	minimum, maximum := minMaxFloat64Slice(series)
+	minimum, maximum := math.Inf(1), math.Inf(-1)
+	length := 0
+	for _, s := range data {
+		lo, hi := minMaxFloat64Slice(s.values)
+		minimum = math.Min(minimum, lo)
+		maximum = math.Max(maximum, hi)
+		if len(s.values) > length {
+			length = len(s.values)
+		}
+	}
 
 	if config.Min != 0.0 && config.Min < minimum {
 		minimum = config.Min
@@ -84,6 +115,10 @@ func (ac *Chart) Plot(series []float64) string {
 		config.Offset = 3
 	}
 
+	if config.Mode == PlotBraille {
+		return ac.plotBraille(data, minimum, maximum)
+	}
+
 	var ratio float64
 	if interval != 0 {
 		ratio = float64(config.Height) / interval
@@ -97,10 +132,63 @@ func (ac *Chart) Plot(series []float64) string {
 	intmax2 := int(max2)
 
 	rows := int(math.Abs(float64(intmax2 - intmin2)))
-	width := len(series) + config.Offset
+	width := length + config.Offset
 
 	plot := createEmpty(rows, width)
 
+	precision, maxWidth := ac.labelFormat(minimum, maximum)
+
+	// axis and labels
+	for y := intmin2; y < intmax2+1; y++ {
+		var magnitude float64
+		if rows > 0 {
+			magnitude = maximum - (float64(y-intmin2) * interval / float64(rows))
+		} else {
+			magnitude = float64(y)
+		}
+
+		label := fmt.Sprintf("%s%*.*f%s", ac.LabelColor(), maxWidth+1, precision, magnitude, ac.EndColor())
+		w := y - intmin2
+		h := int(math.Max(float64(config.Offset)-float64(len(label)), 0))
+
+		plot[w][h] = label
+		if y == 0 {
+			plot[w][config.Offset-1] = fmt.Sprintf("%s┼%s", ac.TickColor(), ac.EndColor())
+		} else {
+			plot[w][config.Offset-1] = fmt.Sprintf("%s┤%s", ac.TickColor(), ac.EndColor())
+		}
+	}
+
+	y0 := int(round(data[0].values[0]*ratio) - min2)
+
+	plot[rows-y0][config.Offset-1] = fmt.Sprintf("%s┼%s", ac.TickColor(), ac.EndColor()) // first value
+
+	for _, s := range data {
+		ac.plotSeries(plot, s.values, rows, intmin2, ratio, ac.SeriesColor(s.index))
+	}
+
+	// join columns
+	var lines bytes.Buffer
+	for h, horizontal := range plot {
+		if h != 0 {
+			lines.WriteRune('\n')
+		}
+		for _, v := range horizontal {
+			lines.WriteString(v)
+		}
+	}
+
+	ac.writeFooter(&lines, maxWidth)
+
+	return lines.String()
+}
+
+// labelFormat returns the precision and width of the axis labels
+func (ac *Chart) labelFormat(minimum, maximum float64) (int, int) {
+	var logMaximum float64
+
+	config := &ac.config
+
 	precision := config.Precision
 	logMaximum = math.Log10(math.Max(math.Abs(maximum), math.Abs(minimum))) //to find number of zeros after decimal
 	if minimum == float64(0) && maximum == float64(0) {
@@ -126,72 +214,59 @@ func (ac *Chart) Plot(series []float64) string {
 	if config.FieldWidth > 0 && maxWidth != config.FieldWidth {
 		maxWidth = config.FieldWidth
 	}
+	return precision, maxWidth
+}
 
-	// axis and labels
-	for y := intmin2; y < intmax2+1; y++ {
-		var magnitude float64
-		if rows > 0 {
-			magnitude = maximum - (float64(y-intmin2) * interval / float64(rows))
-		} else {
-			magnitude = float64(y)
-		}
+// writeFooter adds the caption and the legend rows below the chart
+func (ac *Chart) writeFooter(lines *bytes.Buffer, maxWidth int) {
 
-		label := fmt.Sprintf("%s%*.*f%s", ac.LabelColor(), maxWidth+1, precision, magnitude, ac.EndColor())
-		w := y - intmin2
-		h := int(math.Max(float64(config.Offset)-float64(len(label)), 0))
+	config := &ac.config
 
-		plot[w][h] = label
-		if y == 0 {
-			plot[w][config.Offset-1] = fmt.Sprintf("%s┼%s", ac.TickColor(), ac.EndColor())
-		} else {
-			plot[w][config.Offset-1] = fmt.Sprintf("%s┤%s", ac.TickColor(), ac.EndColor())
+	// add caption if not empty
+	if config.Caption != "" {
+		lines.WriteRune('\n')
+		lines.WriteString(strings.Repeat(" ", config.Offset+maxWidth+2))
+		lines.WriteString(fmt.Sprintf("%s%s%s", ac.CaptionColor(), config.Caption, ac.EndColor()))
+	}
+
+	// add the legend of the series
+	if len(config.Legends) > 0 {
+		lines.WriteRune('\n')
+		lines.WriteString(strings.Repeat(" ", config.Offset+maxWidth+2))
+		for i, legend := range config.Legends {
+			if i > 0 {
+				lines.WriteString("  ")
+			}
+			lines.WriteString(fmt.Sprintf("%s■%s %s", ac.SeriesColor(i), ac.EndColor(), legend))
 		}
 	}
+}
 
-	y0 := int(round(series[0]*ratio) - min2)
-	var y1 int
+// plotSeries draws the line of one series into the plot
+func (ac *Chart) plotSeries(plot [][]string, series []float64, rows, intmin2 int, ratio float64, color string) {
+	var y0, y1 int
 
-	plot[rows-y0][config.Offset-1] = fmt.Sprintf("%s┼%s", ac.TickColor(), ac.EndColor()) // first value
+	offset := ac.config.Offset
 
 	for x := 0; x < len(series)-1; x++ { // plot the line
 		y0 = int(round(series[x+0]*ratio) - float64(intmin2))
 		y1 = int(round(series[x+1]*ratio) - float64(intmin2))
 		if y0 == y1 {
-			plot[rows-y0][x+config.Offset] = fmt.Sprintf("%s─%s", ac.LineColor(), ac.EndColor())
+			plot[rows-y0][x+offset] = fmt.Sprintf("%s─%s", color, ac.EndColor())
 		} else {
 			if y0 > y1 {
-				plot[rows-y1][x+config.Offset] = fmt.Sprintf("%s╰%s", ac.LineColor(), ac.EndColor())
-				plot[rows-y0][x+config.Offset] = fmt.Sprintf("%s╮%s", ac.LineColor(), ac.EndColor())
+				plot[rows-y1][x+offset] = fmt.Sprintf("%s╰%s", color, ac.EndColor())
+				plot[rows-y0][x+offset] = fmt.Sprintf("%s╮%s", color, ac.EndColor())
 			} else {
-				plot[rows-y1][x+config.Offset] = fmt.Sprintf("%s╭%s", ac.LineColor(), ac.EndColor())
-				plot[rows-y0][x+config.Offset] = fmt.Sprintf("%s╯%s", ac.LineColor(), ac.EndColor())
+				plot[rows-y1][x+offset] = fmt.Sprintf("%s╭%s", color, ac.EndColor())
+				plot[rows-y0][x+offset] = fmt.Sprintf("%s╯%s", color, ac.EndColor())
 			}
 
 			start := int(math.Min(float64(y0), float64(y1))) + 1
 			end := int(math.Max(float64(y0), float64(y1)))
 			for y := start; y < end; y++ {
-				plot[rows-y][x+config.Offset] = fmt.Sprintf("%s│%s", ac.LineColor(), ac.EndColor())
+				plot[rows-y][x+offset] = fmt.Sprintf("%s│%s", color, ac.EndColor())
 			}
 		}
 	}
-
-	// join columns
-	var lines bytes.Buffer
-	for h, horizontal := range plot {
-		if h != 0 {
-			lines.WriteRune('\n')
-		}
-		for _, v := range horizontal {
-			lines.WriteString(v)
-		}
-	}
-
-	// add caption if not empty
-	if config.Caption != "" {
-		lines.WriteRune('\n')
-		lines.WriteString(strings.Repeat(" ", config.Offset+maxWidth+2))
-		lines.WriteString(fmt.Sprintf("%s%s%s", ac.CaptionColor(), config.Caption, ac.EndColor()))
-	}
-
-	return lines.String()
 }
@@ -315,3 +315,90 @@ func TestPlot(t *testing.T) {
 		})
 	}
 }
+
+func TestPlotMultiSeries(t *testing.T) {
+	chart := New().AddColor(false).
+		SetLegends("rx", "tx")
+
+	actual := chart.Plot([]float64{0, 1, 2, 3}, []float64{3, 2, 1, 0})
+	expected := ` 3.00 ┼╮ ╭
+ 2.00 ┤╰╮╯
+ 1.00 ┤╭╰╮
+ 0.00 ┼╯ ╰
+         ■ rx  ■ tx`
+
+	lines := strings.Split(actual, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	if got := strings.Join(lines, "\n"); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	colored := New().SetSeriesColors("red", "green").Plot([]float64{1, 1, 1}, []float64{2, 2, 2})
+	if !strings.Contains(colored, "[red]") || !strings.Contains(colored, "[green]") {
+		t.Errorf("series colors missing:\n%s", colored)
+	}
+
+	// The color of a series after an empty one matches its legend
+	skipped := New().SetSeriesColors("red", "green").SetLegends("rx", "tx").Plot(nil, []float64{2, 2, 2})
+	if chart := skipped[:strings.LastIndex(skipped, "\n")]; strings.Contains(chart, "[red]") ||
+		!strings.Contains(chart, "[green]") {
+		t.Errorf("series after an empty series has the wrong color:\n%s", skipped)
+	}
+
+	if New().Plot() != "" || New().Plot(nil, []float64{}) != "" {
+		t.Errorf("expected empty chart without data")
+	}
+}
+
+func TestPlotBraille(t *testing.T) {
+	cases := []struct {
+		chart    *Chart
+		data     [][]float64
+		expected string
+	}{
+		{
+			New().AddColor(false).SetHeight(1).SetMode(PlotBraille),
+			[][]float64{{0, 1, 2, 3, 4, 5, 6, 7}},
+			` 7.00 ┤  ⡠⠊
+ 0.00 ┼⡠⠊`,
+		},
+		{
+			New().AddColor(false).SetHeight(1).SetMode(PlotBraille).SetFill(true),
+			[][]float64{{0, 1, 2, 3, 4, 5, 6, 7}},
+			` 7.00 ┤  ⣠⣾
+ 0.00 ┼⣠⣾⣿⣿`,
+		},
+		{
+			New().AddColor(false).SetMode(PlotBraille).SetLegends("a"),
+			[][]float64{{1, 1, 1}},
+			` 1.00 ┼⣀⡀
+         ■ a`,
+		},
+	}
+
+	for i, c := range cases {
+		actual := c.chart.Plot(c.data...)
+
+		lines := strings.Split(actual, "\n")
+		for n := range lines {
+			lines[n] = strings.TrimRight(lines[n], " ")
+		}
+		expected := strings.Split(c.expected, "\n")
+		for n := range expected {
+			expected[n] = strings.TrimRight(expected[n], " ")
+		}
+		if !Equal(lines, expected) {
+			t.Errorf("%d: expected:\n%s\ngot:\n%s", i, c.expected, actual)
+		}
+	}
+
+	// The width is in characters, each character holds two data points
+	chart := New().AddColor(false).SetHeight(2).SetWidth(10).SetMode(PlotBraille)
+	for _, line := range strings.Split(chart.Plot([]float64{1, 5, 2, 8, 3}), "\n") {
+		if n := len([]rune(strings.TrimRight(line, " "))); n > 7+10 {
+			t.Errorf("line longer than the width: %q", line)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2019-2022 Intel Corporation
+
+package asciichart
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+// A Braille pattern character is 0x2800 plus a bit for each of its 2x4 dots,
+// brailleDots is the bit of the dot at [row][column] in the character.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// brailleCell is one character of the chart
+type brailleCell struct {
+	dots   rune // Bits of the dots set in the character
+	series int  // Index of the last series drawn in the character
+}
+
+// brailleCanvas is a grid of Braille characters addressed by dots
+type brailleCanvas struct {
+	rows, cols int
+	cells      [][]brailleCell
+}
+
+func newBrailleCanvas(rows, cols int) *brailleCanvas {
+
+	bc := &brailleCanvas{rows: rows, cols: cols}
+
+	bc.cells = make([][]brailleCell, rows)
+	for i := range bc.cells {
+		bc.cells[i] = make([]brailleCell, cols)
+	}
+	return bc
+}
+
+// set the dot at x, y where 0, 0 is the top left dot
+func (bc *brailleCanvas) set(x, y, series int) {
+
+	if x < 0 || y < 0 || x >= bc.cols*2 || y >= bc.rows*4 {
+		return
+	}
+	cell := &bc.cells[y/4][x/2]
+	cell.dots |= brailleDots[y%4][x%2]
+	cell.series = series
+}
+
+// plotBraille returns the chart of the series drawn with Braille patterns
+func (ac *Chart) plotBraille(data []seriesData, minimum, maximum float64) string {
+
+	config := &ac.config
+
+	length := 0
+	for _, s := range data {
+		if len(s.values) > length {
+			length = len(s.values)
+		}
+	}
+
+	interval := math.Abs(maximum - minimum)
+
+	// A flat series has a single row like the line chart
+	rows := config.Height + 1
+	if interval == 0 {
+		rows = 1
+	}
+	cols := (length + 1) / 2
+	dotRows := rows * 4
+
+	// dotY converts a value into the dot row, the top row is zero
+	dotY := func(v float64) int {
+		if interval == 0 {
+			return dotRows - 1
+		}
+		return int(round((maximum - v) / interval * float64(dotRows-1)))
+	}
+
+	bc := newBrailleCanvas(rows, cols)
+
+	for _, s := range data {
+		prev := dotY(s.values[0])
+		for x, v := range s.values {
+			y := dotY(v)
+
+			// Connect to the previous point to keep the line continuous
+			lo, hi := y, y
+			if prev < y-1 {
+				lo = prev + 1
+			} else if prev > y+1 {
+				hi = prev - 1
+			}
+			if config.Fill {
+				hi = dotRows - 1
+			}
+			for dy := lo; dy <= hi; dy++ {
+				bc.set(x, dy, s.index)
+			}
+			prev = y
+		}
+	}
+
+	precision, maxWidth := ac.labelFormat(minimum, maximum)
+
+	var lines bytes.Buffer
+	for r := 0; r < rows; r++ {
+		if r != 0 {
+			lines.WriteRune('\n')
+		}
+
+		magnitude := maximum
+		if rows > 1 {
+			magnitude = maximum - float64(r)*interval/float64(rows-1)
+		}
+		label := fmt.Sprintf("%s%*.*f%s", ac.LabelColor(), maxWidth+1, precision, magnitude, ac.EndColor())
+		lines.WriteString(label)
+
+		// Pad the label to the offset like the line chart
+		for n := len(label); n < config.Offset-1; n++ {
+			lines.WriteRune(' ')
+		}
+
+		tick := "┤"
+		if r == rows-1 {
+			tick = "┼"
+		}
+		lines.WriteString(fmt.Sprintf(" %s%s%s", ac.TickColor(), tick, ac.EndColor()))
+
+		for _, cell := range bc.cells[r] {
+			if cell.dots == 0 {
+				lines.WriteRune(' ')
+				continue
+			}
+			lines.WriteString(fmt.Sprintf("%s%c%s", ac.SeriesColor(cell.series), 0x2800+cell.dots, ac.EndColor()))
+		}
+	}
+
+	ac.writeFooter(&lines, maxWidth)
+
+	return lines.String()
+}
@@ -9,8 +9,23 @@ import (
 	"strings"
 )
 
+// PlotMode selects the renderer of the chart
+type PlotMode int
+
+const (
+	// PlotLine draws the series with box-drawing characters, one data point
+	// per column and one value step per row.
+	PlotLine PlotMode = iota
+	// PlotBraille draws the series with Braille patterns, each character
+	// holds 2x4 dots giving two data points per column and four value steps
+	// per row.
+	PlotBraille
+)
+
 // PlotConfig - information about the chart
 type PlotConfig struct {
+	Mode          PlotMode // Renderer of the chart, PlotLine by default
+	Fill          bool     // Fill the area below the series, PlotBraille only
 	Width, Height int
 	Offset        int
 	FieldWidth    int
@@ -23,6 +38,9 @@ type PlotConfig struct {
 	LineColor    string
 	CaptionColor string
 	TickColor    string
+
+	SeriesColors []string // Line color of each series, LineColor when not set
+	Legends      []string // Legend of each series, no legend row when empty
 }
 
 // SetChartOptions - Set all of the chart options
@@ -255,6 +273,75 @@ func (ac *Chart) SetTickColor(color string) *Chart {
 	return ac
 }
 
+// Mode - Get the renderer of the chart
+func (ac *Chart) Mode() PlotMode {
+
+	return ac.config.Mode
+}
+
+// SetMode sets the renderer of the chart, PlotLine or PlotBraille.
+func (ac *Chart) SetMode(mode PlotMode) *Chart {
+	c := &ac.config
+
+	c.Mode = mode
+
+	return ac
+}
+
+// Fill - Get the filled area style of the chart
+func (ac *Chart) Fill() bool {
+
+	return ac.config.Fill
+}
+
+// SetFill fills the area below the series, only used by PlotBraille.
+func (ac *Chart) SetFill(flag bool) *Chart {
+	c := &ac.config
+
+	c.Fill = flag
+
+	return ac
+}
+
+// SeriesColor returns the line color of the series at the given index
+func (ac *Chart) SeriesColor(index int) string {
+	c := &ac.config
+
+	if index < len(c.SeriesColors) && len(c.SeriesColors[index]) > 0 && c.SeriesColors[index] != "[]" {
+		return c.SeriesColors[index]
+	}
+	return ac.LineColor()
+}
+
+// SetSeriesColors sets the line color of each series, in the order of the
+// series given to Plot.
+func (ac *Chart) SetSeriesColors(colors ...string) *Chart {
+	c := &ac.config
+
+	c.SeriesColors = nil
+	for _, color := range colors {
+		c.SeriesColors = append(c.SeriesColors, setColor(color))
+	}
+
+	return ac
+}
+
+// Legends - Get the legends of the series
+func (ac *Chart) Legends() []string {
+
+	return ac.config.Legends
+}
+
+// SetLegends sets the legend of each series, in the order of the series
+// given to Plot. The legends are displayed in a row below the chart.
+func (ac *Chart) SetLegends(legends ...string) *Chart {
+	c := &ac.config
+
+	c.Legends = legends
+
+	return ac
+}
+
 // EndColor - change color to default value
 func (ac *Chart) EndColor() string {
 	c := &ac.config
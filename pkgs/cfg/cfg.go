@@ -4,39 +4,228 @@
 package cfg
 
 import (
-    "bytes"
-    "fmt"
-    "os"
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
 
-    "github.com/tidwall/jsonc"
+	"github.com/tidwall/jsonc"
 )
+
+// MaxPorts is the number of ports a configuration can describe
+const MaxPorts = 8
+
+// MbufHeadroom is the headroom of the data room of an mbuf
+const MbufHeadroom = 128
+
+// Config is the JSON-C configuration of go-pktgen
 type Config struct {
+	Ports     []*PortInfo            `json:"ports,omitempty"`      // Traffic settings of each port
+	Prefs     *PanelPrefs            `json:"prefs,omitempty"`      // Preferences of the panels
+	Profiles  map[string][]*PortInfo `json:"profiles,omitempty"`   // Named port settings loadable at runtime
+	Headless  *HeadlessInfo          `json:"headless,omitempty"`   // Traffic profile of the headless mode
+	Theme     *ThemeInfo             `json:"theme,omitempty"`      // Colors of the panels
+	Memory    *MemoryInfo            `json:"memory,omitempty"`     // Mempool sizes of the ports
+	Lcores    string                 `json:"lcores,omitempty"`     // Lcores of go-pktgen in the DPDK -l format i.e. "2-5,8"
+	MainLcore *int                   `json:"main_lcore,omitempty"` // Main lcore, not set to use the first of the lcores
+}
+
+// ThemeInfo is the JSON color theme, a built-in theme with the colors of
+// some roles changed
+type ThemeInfo struct {
+	Name   string            `json:"name,omitempty"`   // dark, light or monochrome, default dark
+	Colors map[string]string `json:"colors,omitempty"` // Role to color name i.e. "rate": "green"
 }
 
+// MemoryInfo is the JSON mempool sizes, each queue of a port has a mempool
+type MemoryInfo struct {
+	Mbufs    uint64 `json:"mbufs"`     // Number of mbufs of each queue mempool, 0 for the default
+	MbufSize uint64 `json:"mbuf_size"` // Data room size of an mbuf including the headroom, 0 for the default
+}
+
+// PanelPrefs is the JSON preferences of the panels
+type PanelPrefs struct {
+	Panel string `json:"panel"` // Title of the panel displayed at startup
+	Port  int    `json:"port"`  // Selected port of the Single panel
+}
+
+// PortInfo is the JSON traffic settings of a port, zero values keep the default
+type PortInfo struct {
+	Port        int     `json:"port"`         // Port index
+	TxCount     uint64  `json:"tx_count"`     // Number of packets to send, 0 == Forever
+	PercentRate float64 `json:"percent_rate"` // Percent of the line rate
+	PktSize     uint16  `json:"pkt_size"`     // Packet size including the CRC
+	BurstCount  uint16  `json:"burst_count"`  // Size of the packet burst
+	TimeToLive  uint16  `json:"ttl"`          // Time to live value
+	SrcPort     uint16  `json:"src_port"`     // Source port
+	DstPort     uint16  `json:"dst_port"`     // Destination port
+	PType       string  `json:"ptype"`        // Packet type IPv4, IPv6 or ICMP
+	ProtoType   string  `json:"proto"`        // Protocol type UDP or TCP
+	VlanID      uint16  `json:"vlan_id"`      // Vlan identifier
+	SrcIP       string  `json:"src_ip"`       // Source IP address in CIDR notation
+	DstIP       string  `json:"dst_ip"`       // Destination IP address
+	SrcMAC      string  `json:"src_mac"`      // Source MAC address
+	DstMAC      string  `json:"dst_mac"`      // Destination MAC address
+	RxQueues    uint16  `json:"rx_queues"`    // Number of RX queues, fixed when the port starts
+	TxQueues    uint16  `json:"tx_queues"`    // Number of TX queues, fixed when the port starts
+	PCI         string  `json:"pci"`          // PCI address of the port device, fixed when the port starts
+	RxLcores    string  `json:"rx_lcores"`    // Lcores of the RX queues i.e. "2-3", fixed when the port starts
+	TxLcores    string  `json:"tx_lcores"`    // Lcores of the TX queues i.e. "4-5", fixed when the port starts
+}
+
+// Change is a changed setting of a port between two configurations
+type Change struct {
+	Port    int    // Port index
+	Field   string // JSON name of the setting
+	Old     string // Old value
+	New     string // New value
+	Restart bool   // True when the setting can only change with a restart
+}
+
+// restartFields are the JSON names of the settings fixed when the port starts
+var restartFields = map[string]bool{
+	"rx_queues": true,
+	"tx_queues": true,
+	"pci":       true,
+	"rx_lcores": true,
+	"tx_lcores": true,
+}
+
+// HeadlessInfo is the JSON traffic profile of the headless mode
+type HeadlessInfo struct {
+	Ports      []int    `json:"ports,omitempty"`       // Ports to send traffic on, empty is all ports
+	Duration   string   `json:"duration"`              // Maximum run time i.e. "30s"
+	Count      uint64   `json:"count"`                 // Number of packets to send on each port
+	Interval   string   `json:"interval"`              // Periodic statistics interval, empty for none
+	MaxLoss    *float64 `json:"max_loss,omitempty"`    // Maximum packet loss in percent, not set to ignore
+	MaxLatency *float64 `json:"max_latency,omitempty"` // Maximum average latency in usec, not set to ignore
+}
+
+// pciAddress matches a PCI address with or without the domain
+var pciAddress = regexp.MustCompile(`^([[:xdigit:]]{4}:)?[[:xdigit:]]{2}:[[:xdigit:]]{2}\.[0-7]$`)
+
+// lcoreList matches a DPDK lcore list of lcores and lcore ranges
+var lcoreList = regexp.MustCompile(`^[0-9]+(-[0-9]+)?(,[0-9]+(-[0-9]+)?)*$`)
+
+// System is the loaded and validated configuration
 type System struct {
-    cfg *Config
+	cfg  *Config
+	path string
 }
 
-func (cfg *Config) validateConfig() error {
-    return nil
+func validatePorts(ports []*PortInfo) error {
+
+	seen := make(map[int]bool)
+	devices := make(map[string]int)
+	for _, p := range ports {
+		if p == nil {
+			return fmt.Errorf("empty port entry")
+		}
+		if p.Port < 0 || p.Port >= MaxPorts {
+			return fmt.Errorf("port %d not in range 0-%d", p.Port, MaxPorts-1)
+		}
+		if seen[p.Port] {
+			return fmt.Errorf("port %d configured more than once", p.Port)
+		}
+		seen[p.Port] = true
+
+		for _, l := range []string{p.RxLcores, p.TxLcores} {
+			if len(l) > 0 && !lcoreList.MatchString(l) {
+				return fmt.Errorf("port %d lcores %q is not an lcore list i.e. 2-3", p.Port, l)
+			}
+		}
+
+		if len(p.PCI) == 0 {
+			continue
+		}
+		if !pciAddress.MatchString(p.PCI) {
+			return fmt.Errorf("port %d pci %q is not a PCI address i.e. 0000:18:00.0", p.Port, p.PCI)
+		}
+		if port, ok := devices[p.PCI]; ok {
+			return fmt.Errorf("port %d pci %s is also the device of port %d", p.Port, p.PCI, port)
+		}
+		devices[p.PCI] = p.Port
+	}
+	return nil
+}
+
+func (c *Config) validateConfig() error {
+
+	if err := validatePorts(c.Ports); err != nil {
+		return err
+	}
+
+	for name, ports := range c.Profiles {
+		if len(name) == 0 {
+			return fmt.Errorf("profile without a name")
+		}
+		if err := validatePorts(ports); err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+	}
+
+	// The data room includes the headroom of the mbuf
+	if m := c.Memory; m != nil && m.MbufSize != 0 && m.MbufSize <= MbufHeadroom {
+		return fmt.Errorf("memory mbuf_size %d not larger than the %d byte headroom", m.MbufSize, MbufHeadroom)
+	}
+
+	if len(c.Lcores) > 0 && !lcoreList.MatchString(c.Lcores) {
+		return fmt.Errorf("lcores %q is not an lcore list i.e. 2-5,8", c.Lcores)
+	}
+	if c.MainLcore != nil && *c.MainLcore < 0 {
+		return fmt.Errorf("main_lcore %d is negative", *c.MainLcore)
+	}
+
+	if p := c.Prefs; p != nil && (p.Port < 0 || p.Port >= MaxPorts) {
+		return fmt.Errorf("prefs port %d not in range 0-%d", p.Port, MaxPorts-1)
+	}
+
+	if h := c.Headless; h != nil {
+		for _, port := range h.Ports {
+			if port < 0 || port >= MaxPorts {
+				return fmt.Errorf("headless port %d not in range 0-%d", port, MaxPorts-1)
+			}
+		}
+		for _, d := range []string{h.Duration, h.Interval} {
+			if len(d) == 0 {
+				continue
+			}
+			if _, err := time.ParseDuration(d); err != nil {
+				return fmt.Errorf("headless: %w", err)
+			}
+		}
+		if h.MaxLoss != nil && (*h.MaxLoss < 0 || *h.MaxLoss > 100) {
+			return fmt.Errorf("headless max_loss %v not in range 0-100", *h.MaxLoss)
+		}
+		if h.MaxLatency != nil && *h.MaxLatency < 0 {
+			return fmt.Errorf("headless max_latency %v is negative", *h.MaxLatency)
+		}
+	}
+
+	return nil
 }
 
 // OpenWithConfig by passing in a initialized Config structure
 func OpenWithConfig(c *Config) (*System, error) {
 
-    if err := c.validateConfig(); err != nil {
-        return nil, fmt.Errorf("failed to validate configuration: %w", err)
-    }
+	if err := c.validateConfig(); err != nil {
+		return nil, fmt.Errorf("failed to validate configuration: %w", err)
+	}
 
-    sys := &System{cfg: c}
+	sys := &System{cfg: c}
 
-    return sys, nil
+	return sys, nil
 }
 
+// OpenWithText by passing in a JSON-C or JSON text
 func OpenWithText(b []byte) (*System, error) {
 
-    text := jsonc.ToJSON(bytes.TrimSpace(b))
+	text := jsonc.ToJSON(bytes.TrimSpace(b))
 
 	if len(text) == 0 {
 		return nil, fmt.Errorf("empty json text string")
@@ -53,16 +242,115 @@ func OpenWithText(b []byte) (*System, error) {
 	if err := json.Unmarshal(text, cfg); err != nil {
 		return nil, err
 	}
-    return OpenWithConfig(cfg)
+	return OpenWithConfig(cfg)
 }
 
 // OpenWithFile by passing in a filename or path to a JSON-C or JSON configuration
 func OpenWithFile(path string) (*System, error) {
-    b, err := os.ReadFile(path)
-    if err != nil {
-        return nil, err
-    }
-    return OpenWithText(b)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sys, err := OpenWithText(b)
+	if err != nil {
+		return nil, err
+	}
+	sys.path = path
+
+	return sys, nil
+}
+
+// Config returns the configuration of the system
+func (s *System) Config() *Config {
+	return s.cfg
+}
+
+// Path returns the file the configuration was loaded from, empty if none
+func (s *System) Path() string {
+	return s.path
+}
+
+// Save the configuration as JSON to the given path. The file is replaced
+// with a rename, a reader never sees a partial file. Comments of a JSON-C
+// file are not kept.
+func (c *Config) Save(path string) error {
+
+	if err := c.validateConfig(); err != nil {
+		return fmt.Errorf("failed to validate configuration: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c, "", "    ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0644); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Port returns the settings of the given port or nil if not configured
+func (c *Config) Port(port int) *PortInfo {
+
+	for _, p := range c.Ports {
+		if p.Port == port {
+			return p
+		}
+	}
+	return nil
+}
+
+func (c Change) String() string {
+
+	s := fmt.Sprintf("port %d %s: %s -> %s", c.Port, c.Field, c.Old, c.New)
+	if c.Restart {
+		s += " (needs a restart)"
+	}
+	return s
+}
+
+// DiffPort returns the changed settings between the previous and next settings of a port
+func DiffPort(prev, next *PortInfo) []Change {
+
+	var changes []Change
+
+	ov, nv := reflect.ValueOf(prev).Elem(), reflect.ValueOf(next).Elem()
+	for i := 0; i < ov.NumField(); i++ {
+		field := ov.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "port" {
+			continue
+		}
+
+		o, n := ov.Field(i).Interface(), nv.Field(i).Interface()
+		if o == n {
+			continue
+		}
+		changes = append(changes, Change{
+			Port:    next.Port,
+			Field:   name,
+			Old:     fmt.Sprint(o),
+			New:     fmt.Sprint(n),
+			Restart: restartFields[name],
+		})
+	}
+	return changes
 }
 
 func (c *Config) String() string {
@@ -72,4 +360,4 @@ func (c *Config) String() string {
 	} else {
 		return string(data)
 	}
-}
\ No newline at end of file
+}
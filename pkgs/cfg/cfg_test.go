@@ -5,7 +5,7 @@ package cfg
 
 import (
 	"fmt"
-
+	"path/filepath"
 	"testing"
 )
 
@@ -17,3 +17,112 @@ func TestClose(t *testing.T) {
 	fmt.Printf("Close configurarion\n")
 
 }
+
+func TestOpenWithText(t *testing.T) {
+	text := `{
+		// Port 1 sends 128 byte packets
+		"ports": [
+			{ "port": 1, "pkt_size": 128, "percent_rate": 50, "pci": "0000:18:00.1" },
+		],
+		"headless": { "duration": "10s", "max_loss": 0.5 },
+		"memory": { "mbufs": 4096 },
+		"lcores": "2-5,8",
+	}`
+
+	sys, err := OpenWithText([]byte(text))
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	c := sys.Config()
+
+	if p := c.Port(1); p == nil || p.PktSize != 128 || p.PercentRate != 50 || p.PCI != "0000:18:00.1" {
+		t.Errorf("port 1 not loaded: %+v", p)
+	}
+	if p := c.Port(0); p != nil {
+		t.Errorf("port 0 should not be configured: %+v", p)
+	}
+	if c.Headless == nil || c.Headless.Duration != "10s" ||
+		c.Headless.MaxLoss == nil || *c.Headless.MaxLoss != 0.5 || c.Headless.MaxLatency != nil {
+		t.Errorf("headless not loaded: %+v", c.Headless)
+	}
+	if c.Memory == nil || c.Memory.Mbufs != 4096 || c.Memory.MbufSize != 0 {
+		t.Errorf("memory not loaded: %+v", c.Memory)
+	}
+	if c.Lcores != "2-5,8" {
+		t.Errorf("lcores %q not loaded", c.Lcores)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	bad := []string{
+		`{ "ports": [ { "port": 8 } ] }`,
+		`{ "ports": [ { "port": 1 }, { "port": 1 } ] }`,
+		`{ "headless": { "duration": "ten seconds" } }`,
+		`{ "headless": { "max_loss": 101 } }`,
+		`{ "profiles": { "bad": [ { "port": 9 } ] } }`,
+		`{ "prefs": { "port": -1 } }`,
+		`{ "ports": [ { "port": 0, "pci": "18:00" } ] }`,
+		`{ "ports": [ { "port": 0, "pci": "0000:18:00.0" }, { "port": 1, "pci": "0000:18:00.0" } ] }`,
+		`{ "memory": { "mbuf_size": 128 } }`,
+		`{ "lcores": "2-5,x" }`,
+		`{ "main_lcore": -1 }`,
+		`{ "ports": [ { "port": 0, "rx_lcores": "2:3" } ] }`,
+	}
+
+	for _, text := range bad {
+		if _, err := OpenWithText([]byte(text)); err == nil {
+			t.Errorf("expected error for %s", text)
+		}
+	}
+}
+
+func TestSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pktgen.cfg")
+
+	c := &Config{
+		Ports:    []*PortInfo{{Port: 0, PktSize: 256, SrcIP: "198.18.0.1/24"}},
+		Prefs:    &PanelPrefs{Panel: "Single", Port: 0},
+		Profiles: map[string][]*PortInfo{"jumbo": {{Port: 1, PktSize: 1518}}},
+	}
+	if err := c.Save(path); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	sys, err := OpenWithFile(path)
+	if err != nil {
+		t.Fatalf("open of saved file failed: %v", err)
+	}
+	if sys.Path() != path {
+		t.Errorf("path %q, expected %q", sys.Path(), path)
+	}
+	if got, want := sys.Config().String(), c.String(); got != want {
+		t.Errorf("saved configuration differs:\n%s\nexpected:\n%s", got, want)
+	}
+
+	c.Ports = append(c.Ports, &PortInfo{Port: 0})
+	if err := c.Save(path); err == nil {
+		t.Errorf("expected error saving an invalid configuration")
+	}
+}
+
+func TestDiffPort(t *testing.T) {
+	prev := &PortInfo{Port: 2, PktSize: 64, PercentRate: 100, RxQueues: 1}
+	next := &PortInfo{Port: 2, PktSize: 128, PercentRate: 100, RxQueues: 4}
+
+	changes := DiffPort(prev, next)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %v", changes)
+	}
+	if c := changes[0]; c.Field != "pkt_size" || c.Old != "64" || c.New != "128" || c.Restart {
+		t.Errorf("unexpected change %v", c)
+	}
+	if c := changes[1]; c.Field != "rx_queues" || !c.Restart {
+		t.Errorf("unexpected change %v", c)
+	}
+	if s := changes[0].String(); s != "port 2 pkt_size: 64 -> 128" {
+		t.Errorf("unexpected string %q", s)
+	}
+	if changes := DiffPort(prev, prev); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+}
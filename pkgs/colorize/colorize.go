@@ -132,8 +132,9 @@ func SetDefaultFlags(f string) {
 //   w[2] is the foreground color
 //   w[3] is the background color
 //   w[4] is the attribute of the color
+//   A monochrome theme only keeps the attributes
 func Colorize(color string, v interface{}, w ...interface{}) string {
-	if colorInfo.defForeground == "" {
+	if colorInfo.defForeground == "" && !theme.Monochrome {
 		colorInfo.defForeground = "ivory"
 	}
 
@@ -174,22 +175,40 @@ func Colorize(color string, v interface{}, w ...interface{}) string {
 		}
 	}
 
-	// Build up the color tag strings for begin and end of the field to be printed
-	str := fmt.Sprintf("[%s:%s:%s]", foreground, background, flags)
-	def := fmt.Sprintf("[%s:%s:%s]", colorInfo.defForeground, colorInfo.defBackground, colorInfo.defFlags)
-
+	var val string
 	switch v.(type) {
 	case string:
-		return fmt.Sprintf("%[1]s%[3]*[2]s%[4]s", str, v, width, def)
+		val = fmt.Sprintf("%[2]*[1]s", v, width)
 	case uint64, uint32, uint16, uint8:
-		return fmt.Sprintf("%[1]s%[3]*[2]d%[4]s", str, v, width, def)
+		val = fmt.Sprintf("%[2]*[1]d", v, width)
 	case int, int64, int32, int16, int8:
-		return fmt.Sprintf("%[1]s%[3]*[2]d%[4]s", str, v, width, def)
+		val = fmt.Sprintf("%[2]*[1]d", v, width)
 	case float64, float32:
-		return fmt.Sprintf("%[1]s%[3]*.[4]*[2]f%[5]s", str, v, width, precision, def)
+		val = fmt.Sprintf("%[2]*.[3]*[1]f", v, width, precision)
 	default:
-		return fmt.Sprintf("%[1]s%[2]v%s", str, v, def)
+		val = fmt.Sprintf("%v", v)
 	}
+
+	if theme.Monochrome {
+		if len(flags) == 0 {
+			return val
+		}
+		return fmt.Sprintf("[-:-:%s]%s[-:-:-]", flags, val)
+	}
+
+	// Build up the color tag strings for begin and end of the field to be printed
+	str := fmt.Sprintf("[%s:%s:%s]", themeColor(foreground), themeColor(background), flags)
+	def := fmt.Sprintf("[%s:%s:%s]", colorInfo.defForeground, colorInfo.defBackground, colorInfo.defFlags)
+
+	return str + val + def
+}
+
+// IsColorName returns true if the name is a known color name
+func IsColorName(color string) bool {
+
+	_, ok := tcell.ColorNames[strings.ToLower(color)]
+
+	return ok
 }
 
 // ColorWithName - Find and set the color by name
@@ -17,3 +17,54 @@ func TestClose(t *testing.T) {
 	fmt.Printf("Close Colorize\n")
 
 }
+
+func TestThemes(t *testing.T) {
+	defer SetTheme(themes[DarkTheme])
+
+	SetDefault("ivory", "", 0, 2, "")
+	if got := Header("Port", 5); got != "[yellow::] Port[ivory::]" {
+		t.Errorf("dark header = %q", got)
+	}
+
+	light, err := ThemeByName("Light")
+	if err != nil {
+		t.Fatalf("ThemeByName: %v", err)
+	}
+	SetTheme(light)
+	if got := Yellow("x"); got != "[olive::]x[black::]" {
+		t.Errorf("light yellow = %q", got)
+	}
+	if got := Error("x"); got != "[firebrick::]x[black::]" {
+		t.Errorf("light error = %q", got)
+	}
+
+	mono, _ := ThemeByName(MonochromeTheme)
+	SetTheme(mono)
+	if got := Rate(1.5, 6, 1); got != "   1.5" {
+		t.Errorf("monochrome rate = %q", got)
+	}
+	if got := Colorize(OrangeColor, "F1", 0, 0, "", "", "r"); got != "[-:-:r]F1[-:-:-]" {
+		t.Errorf("monochrome attribute = %q", got)
+	}
+
+	if _, err := ThemeByName("solarized"); err == nil {
+		t.Errorf("unknown theme did not fail")
+	}
+}
+
+func TestSetRole(t *testing.T) {
+	th, _ := ThemeByName(DarkTheme)
+
+	if err := th.SetRole(RoleRate, "Blue"); err != nil || th.Roles[RoleRate] != "blue" {
+		t.Errorf("SetRole rate = %v, %q", err, th.Roles[RoleRate])
+	}
+	if themes[DarkTheme].Roles[RoleRate] == "blue" {
+		t.Errorf("SetRole changed the built-in theme")
+	}
+	if err := th.SetRole("border", "blue"); err == nil {
+		t.Errorf("unknown role did not fail")
+	}
+	if err := th.SetRole(RoleRate, "notacolor"); err == nil {
+		t.Errorf("unknown color did not fail")
+	}
+}
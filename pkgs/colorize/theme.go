@@ -0,0 +1,261 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2019-2022 Intel Corporation
+
+package colorize
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Role is the semantic use of a color, the theme maps a role to a color
+type Role string
+
+// Roles of the colors
+const (
+	RoleTitle   Role = "title"   // Window titles
+	RoleHeader  Role = "header"  // Table and column headers
+	RoleLabel   Role = "label"   // Labels in front of values
+	RoleValue   Role = "value"   // Counters and configuration values
+	RoleRate    Role = "rate"    // Rates like pps and Mbit/s
+	RoleAddress Role = "address" // IP and MAC addresses
+	RoleWarning Role = "warning" // Warnings
+	RoleError   Role = "error"   // Errors
+)
+
+// Roles returns all color roles
+func Roles() []Role {
+	return []Role{RoleTitle, RoleHeader, RoleLabel, RoleValue, RoleRate, RoleAddress, RoleWarning, RoleError}
+}
+
+// Theme of the colors, the roles select a color and the named colors are
+// replaced with other colors to keep the text readable on the background.
+// A monochrome theme writes no colors only the attributes like reverse.
+type Theme struct {
+	Name       string
+	Foreground string            // Default foreground color
+	Background string            // Default background color, empty for the terminal background
+	Monochrome bool              // No colors at all
+	Roles      map[Role]string   // Color of each role
+	Colors     map[string]string // Named colors replaced by other colors
+}
+
+// Names of the built-in themes
+const (
+	DarkTheme       = "dark"
+	LightTheme      = "light"
+	MonochromeTheme = "monochrome"
+)
+
+var themes = map[string]*Theme{
+	DarkTheme: {
+		Name:       DarkTheme,
+		Foreground: "ivory",
+		Roles: map[Role]string{
+			RoleTitle:   OrangeColor,
+			RoleHeader:  YellowColor,
+			RoleLabel:   WheatColor,
+			RoleValue:   CyanColor,
+			RoleRate:    MediumSpringGreenColor,
+			RoleAddress: GreenColor,
+			RoleWarning: OrangeColor,
+			RoleError:   RedColor,
+		},
+	},
+	LightTheme: {
+		Name:       LightTheme,
+		Foreground: "black",
+		Roles: map[Role]string{
+			RoleTitle:   "darkorange",
+			RoleHeader:  "darkblue",
+			RoleLabel:   "saddlebrown",
+			RoleValue:   "teal",
+			RoleRate:    "darkgreen",
+			RoleAddress: "purple",
+			RoleWarning: "chocolate",
+			RoleError:   "firebrick",
+		},
+		// The light colors of the panels disappear on a light background
+		Colors: map[string]string{
+			"ivory":                "black",
+			"white":                "black",
+			YellowColor:            "olive",
+			LightYellowColor:       "olive",
+			CornSilkColor:          "saddlebrown",
+			WheatColor:             "sienna",
+			GoldenRodColor:         "darkgoldenrod",
+			LightBlueColor:         "blue",
+			SkyBlueColor:           "steelblue",
+			LightSkyBlueColor:      "navy",
+			LightCyanColor:         "teal",
+			CyanColor:              "teal",
+			LavenderColor:          "slateblue",
+			LightSalmonColor:       "orangered",
+			LightCoralColor:        "brown",
+			MistyRoseColor:         "indianred",
+			MediumSpringGreenColor: "darkgreen",
+			LightGreenColor:        GreenColor,
+			YellowGreenColor:       "olivedrab",
+		},
+	},
+	MonochromeTheme: {
+		Name:       MonochromeTheme,
+		Monochrome: true,
+	},
+}
+
+var theme = themes[DarkTheme]
+
+// ThemeNames returns the names of the built-in themes
+func ThemeNames() []string {
+
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ThemeByName returns a copy of a built-in theme, the copy can be changed
+// before it is set.
+func ThemeByName(name string) (*Theme, error) {
+
+	t, ok := themes[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown theme %s, themes are %s", name, strings.Join(ThemeNames(), ", "))
+	}
+	return t.Copy(), nil
+}
+
+// Copy returns a copy of the theme
+func (t *Theme) Copy() *Theme {
+
+	c := *t
+
+	c.Roles = make(map[Role]string, len(t.Roles))
+	for r, color := range t.Roles {
+		c.Roles[r] = color
+	}
+	c.Colors = make(map[string]string, len(t.Colors))
+	for name, color := range t.Colors {
+		c.Colors[name] = color
+	}
+	return &c
+}
+
+// SetRole changes the color of a role, the role and color names must be valid
+func (t *Theme) SetRole(role Role, color string) error {
+
+	valid := false
+	for _, r := range Roles() {
+		if r == role {
+			valid = true
+		}
+	}
+	if !valid {
+		return fmt.Errorf("unknown color role %s", role)
+	}
+	if !IsColorName(color) {
+		return fmt.Errorf("unknown color %s for role %s", color, role)
+	}
+
+	t.Roles[role] = strings.ToLower(color)
+
+	return nil
+}
+
+// SetTheme makes the theme the current theme and sets the default colors
+func SetTheme(t *Theme) {
+
+	theme = t
+
+	colorInfo.defForeground = t.Foreground
+	colorInfo.defBackground = t.Background
+}
+
+// CurrentTheme returns the theme in use
+func CurrentTheme() *Theme {
+	return theme
+}
+
+// NoColorEnv returns true if the NO_COLOR environment variable is set to a
+// non empty value, see https://no-color.org
+func NoColorEnv() bool {
+	return len(os.Getenv("NO_COLOR")) > 0
+}
+
+// themeColor returns the color of the theme replacing the named color
+func themeColor(color string) string {
+
+	if c, ok := theme.Colors[color]; ok {
+		return c
+	}
+	return color
+}
+
+// RoleColor returns the color of a role in the current theme
+func RoleColor(role Role) string {
+
+	if c, ok := theme.Roles[role]; ok {
+		return c
+	}
+	return DefaultForegroundColor()
+}
+
+// ColorWithRole - Color the value with the color of the role
+func ColorWithRole(role Role, a interface{}, w ...interface{}) string {
+
+	return ColorWithName(RoleColor(role), a, w...)
+}
+
+// Title - return string colored for a window title
+func Title(a interface{}, w ...interface{}) string {
+
+	return ColorWithRole(RoleTitle, a, w...)
+}
+
+// Header - return string colored for a table header
+func Header(a interface{}, w ...interface{}) string {
+
+	return ColorWithRole(RoleHeader, a, w...)
+}
+
+// Label - return string colored for a label
+func Label(a interface{}, w ...interface{}) string {
+
+	return ColorWithRole(RoleLabel, a, w...)
+}
+
+// Value - return string colored for a value
+func Value(a interface{}, w ...interface{}) string {
+
+	return ColorWithRole(RoleValue, a, w...)
+}
+
+// Rate - return string colored for a rate
+func Rate(a interface{}, w ...interface{}) string {
+
+	return ColorWithRole(RoleRate, a, w...)
+}
+
+// Address - return string colored for an address
+func Address(a interface{}, w ...interface{}) string {
+
+	return ColorWithRole(RoleAddress, a, w...)
+}
+
+// Warning - return string colored for a warning
+func Warning(a interface{}, w ...interface{}) string {
+
+	return ColorWithRole(RoleWarning, a, w...)
+}
+
+// Error - return string colored for an error
+func Error(a interface{}, w ...interface{}) string {
+
+	return ColorWithRole(RoleError, a, w...)
+}
@@ -5,6 +5,10 @@ package cpudata
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"testing"
 )
@@ -17,3 +21,180 @@ func TestClose(t *testing.T) {
 	fmt.Printf("Close cpuinfo\n")
 
 }
+
+func TestParseList(t *testing.T) {
+
+	lcores, err := ParseList("8, 2-5,3,10-11")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(lcores); s != "[2 3 4 5 8 10 11]" {
+		t.Errorf("got %s", s)
+	}
+	if s := FormatList(lcores); s != "2-5,8,10-11" {
+		t.Errorf("formatted as %q", s)
+	}
+
+	for _, bad := range []string{"a", "5-2", "1-x", "70000"} {
+		if _, err := ParseList(bad); err == nil {
+			t.Errorf("no error for %q", bad)
+		}
+	}
+	if l, err := ParseList(""); err != nil || len(l) != 0 {
+		t.Errorf("empty list returned %v, %v", l, err)
+	}
+}
+
+func TestSiblings(t *testing.T) {
+
+	cd := &CPUData{
+		cores:   []uint16{0, 1},
+		sockets: []uint16{0},
+		coreMap: map[uint16][]uint16{0: {0, 2}, 1: {1, 3}},
+	}
+	if s := fmt.Sprint(cd.Siblings(3)); s != "[1 3]" {
+		t.Errorf("siblings of 3 are %s", s)
+	}
+	if s := cd.Siblings(4); s != nil {
+		t.Errorf("siblings of 4 are %v", s)
+	}
+}
+
+// twoSockets has 4 cores on each socket, lcore N and N+8 are siblings
+func twoSockets() *CPUData {
+
+	cd := &CPUData{
+		cores:   []uint16{0, 1, 2, 3},
+		sockets: []uint16{0, 1},
+		coreMap: make(map[uint16][]uint16),
+	}
+	for lcore := uint16(0); lcore < 8; lcore++ {
+		socket, core := lcore/4, lcore%4
+		cd.coreMap[socket<<8|core] = []uint16{lcore, lcore + 8}
+	}
+	return cd
+}
+
+func TestPlan(t *testing.T) {
+
+	cd := twoSockets()
+
+	plan, err := cd.Plan([]PortLcores{
+		{Port: 0, Socket: 1, RxQueues: 1, TxQueues: 1},
+		{Port: 1, Socket: 1, RxQueues: 1, TxQueues: 1},
+		{Port: 2, Socket: 0, RxQueues: 1, TxQueues: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Main != 4 {
+		t.Errorf("main lcore %d, want 4", plan.Main)
+	}
+	if s := plan.String(); s != "[5:6].0,[7:1].1,[2:3].2" {
+		t.Errorf("got mapping %s", s)
+	}
+	if s := FormatList(plan.Lcores()); s != "1-7" {
+		t.Errorf("got lcores %s", s)
+	}
+	if len(plan.Warnings) != 1 || !strings.Contains(plan.Warnings[0], "port 1") {
+		t.Errorf("got warnings %q", plan.Warnings)
+	}
+	if a, ok := plan.Lookup(7); !ok || a.Label() != "RX 1.0" {
+		t.Errorf("lcore 7 is %+v", a)
+	}
+	if _, ok := plan.Lookup(12); ok {
+		t.Errorf("sibling 12 of lcore 4 is assigned")
+	}
+
+	// All cores in use including the core of lcore 0
+	plan, err = cd.Plan([]PortLcores{{Port: 0, Socket: -1, RxQueues: 4, TxQueues: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Warnings) != 1 || FormatList(plan.Lcores()) != "0-7" {
+		t.Errorf("got lcores %v, warnings %q", plan.Lcores(), plan.Warnings)
+	}
+
+	if _, err := cd.Plan([]PortLcores{{Port: 0, RxQueues: 4, TxQueues: 4}}); err == nil {
+		t.Errorf("expected an error for 9 lcores on 8 cores")
+	}
+}
+
+func TestReadPowerState(t *testing.T) {
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "devices", "system", "cpu", "cpu3")
+	for name, value := range map[string]string{
+		"cpufreq/scaling_cur_freq":                "1200000\n",
+		"cpufreq/cpuinfo_max_freq":                "3400000\n",
+		"cpufreq/scaling_governor":                "powersave\n",
+		"cpuidle/state0/name":                     "POLL\n",
+		"cpuidle/state0/time":                     "1000\n",
+		"cpuidle/state10/name":                    "C6\n",
+		"cpuidle/state10/time":                    "500000\n",
+		"cpuidle/state10/usage":                   "42\n",
+		"cpuidle/state2/name":                     "C1E\n",
+		"cpuidle/state2/time":                     "2000\n",
+		"thermal_throttle/core_throttle_count":    "3\n",
+		"thermal_throttle/package_throttle_count": "1\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(value), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ps := ReadPowerState(root, 3)
+	if ps.FreqKHz != 1200000 || ps.MaxFreqKHz != 3400000 || ps.Governor != "powersave" || ps.Throttles() != 4 {
+		t.Errorf("got %+v", ps)
+	}
+	if len(ps.Idle) != 3 || ps.Idle[1].Name != "C1E" || ps.Idle[2].Name != "C6" ||
+		ps.Idle[2].Time != 500*time.Millisecond || ps.Idle[2].Usage != 42 {
+		t.Fatalf("got idle states %+v", ps.Idle)
+	}
+
+	prev := ps
+	prev.Idle = append([]IdleState{}, ps.Idle...)
+	prev.Idle[2].Time -= 250 * time.Millisecond
+	if r := ps.Residency(prev, time.Second); r[0] != 0 || r[2] != 25.0 {
+		t.Errorf("got residency %v", r)
+	}
+
+	if ps := ReadPowerState(root, 4); ps.FreqKHz != 0 || len(ps.Idle) != 0 {
+		t.Errorf("missing lcore got %+v", ps)
+	}
+}
+
+func TestReadNodes(t *testing.T) {
+
+	// Two nodes on each socket like on a sub-NUMA clustering system
+	root := t.TempDir()
+	for name, value := range map[string]string{
+		"node0/cpulist": "0-1,8-9\n",
+		"node1/cpulist": "2-3,10-11\n",
+		"node2/cpulist": "4-5,12-13\n",
+		"node3/cpulist": "6-7,14-15\n",
+	} {
+		path := filepath.Join(root, "devices", "system", "node", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(value), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	nodes := ReadNodes(root)
+	if len(nodes) != 4 || FormatList(nodes[1]) != "2-3,10-11" {
+		t.Fatalf("got nodes %v", nodes)
+	}
+	if node, ok := nodes.Node(10); !ok || node != 1 {
+		t.Errorf("node of lcore 10 got %d, %v", node, ok)
+	}
+	if _, ok := nodes.Node(16); ok {
+		t.Errorf("lcore 16 has a node")
+	}
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022 Intel Corporation
+
+package cpudata
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseList returns the lcores of a CPU list like the kernel and DPDK use
+// them i.e. "2-5,8,10-11", the lcores are sorted without duplicates.
+func ParseList(list string) ([]uint16, error) {
+
+	seen := make(map[uint16]bool)
+	lcores := []uint16{}
+
+	for _, item := range strings.Split(strings.TrimSpace(list), ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+
+		first, last, isRange := strings.Cut(item, "-")
+		start, err := strconv.ParseUint(first, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lcore %q in %q", first, list)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.ParseUint(last, 10, 16); err != nil {
+				return nil, fmt.Errorf("invalid lcore %q in %q", last, list)
+			}
+			if end < start {
+				return nil, fmt.Errorf("invalid lcore range %q in %q", item, list)
+			}
+		}
+
+		for v := start; v <= end; v++ {
+			if !seen[uint16(v)] {
+				seen[uint16(v)] = true
+				lcores = append(lcores, uint16(v))
+			}
+		}
+	}
+	sort.Slice(lcores, func(i, j int) bool { return lcores[i] < lcores[j] })
+
+	return lcores, nil
+}
+
+// FormatList returns the CPU list of the lcores with the consecutive lcores
+// as ranges, the opposite of ParseList.
+func FormatList(lcores []uint16) string {
+
+	l := append([]uint16{}, lcores...)
+	sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+
+	items := []string{}
+	for i := 0; i < len(l); {
+		j := i
+		for j+1 < len(l) && l[j+1] <= l[j]+1 {
+			j++
+		}
+		if l[i] == l[j] {
+			items = append(items, strconv.Itoa(int(l[i])))
+		} else {
+			items = append(items, fmt.Sprintf("%d-%d", l[i], l[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(items, ",")
+}
+
+// Siblings returns the lcores of the physical core of the lcore including
+// the lcore, the hyperthread siblings are found in the core map.
+func (cd *CPUData) Siblings(lcore uint16) []uint16 {
+
+	for _, socket := range cd.sockets {
+		for _, core := range cd.cores {
+			v, ok := cd.CoreMapItem(socket<<8 | core)
+			if !ok {
+				continue
+			}
+			for _, l := range v {
+				if l == lcore {
+					return v
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Socket returns the socket of the lcore
+func (cd *CPUData) Socket(lcore uint16) (uint16, bool) {
+
+	if int(lcore) >= len(cd.cpuInfo) {
+		return 0, false
+	}
+	socket, err := strconv.Atoi(cd.cpuInfo[lcore].PhysicalID)
+	if err != nil {
+		return 0, false
+	}
+	return uint16(socket), true
+}
+
+// NodeMap is the lcores of each NUMA node. A socket has several nodes on
+// SNC and NPS systems, so the node of an lcore is not always its socket.
+type NodeMap map[int][]uint16
+
+// ReadNodes returns the lcores of each NUMA node from the cpulist files of
+// the nodes below the sysfs root, an empty root is DefaultSysfsRoot
+func ReadNodes(sysfsRoot string) NodeMap {
+
+	if len(sysfsRoot) == 0 {
+		sysfsRoot = DefaultSysfsRoot
+	}
+
+	nodes := make(NodeMap)
+
+	dirs, _ := filepath.Glob(filepath.Join(sysfsRoot, "devices", "system", "node", "node*"))
+	for _, d := range dirs {
+		node, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(d), "node"))
+		if err != nil {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(d, "cpulist"))
+		if err != nil {
+			continue
+		}
+		if l, err := ParseList(string(b)); err == nil {
+			nodes[node] = l
+		}
+	}
+	return nodes
+}
+
+// Node returns the NUMA node of the lcore
+func (nm NodeMap) Node(lcore uint16) (int, bool) {
+
+	for node, lcores := range nm {
+		for _, l := range lcores {
+			if l == lcore {
+				return node, true
+			}
+		}
+	}
+	return 0, false
+}
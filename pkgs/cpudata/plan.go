@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022 Intel Corporation
+
+package cpudata
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The planner gives each RX and TX queue of a port a physical core of its
+// own on the socket of the NIC, only the first lcore of a core is used so
+// no hyperthread siblings share a core. The main lcore also gets a core of
+// its own, and the core of lcore 0 is left to the OS unless the system has
+// too few cores.
+
+// Role of an lcore in a plan
+type Role int
+
+// Roles of the lcores
+const (
+	RoleMain Role = iota // Main lcore running the display and statistics
+	RoleRx               // Receives the packets of a port queue
+	RoleTx               // Sends the packets of a port queue
+)
+
+func (r Role) String() string {
+
+	switch r {
+	case RoleMain:
+		return "main"
+	case RoleRx:
+		return "RX"
+	case RoleTx:
+		return "TX"
+	}
+	return fmt.Sprintf("Role(%d)", int(r))
+}
+
+// PortLcores is a port to plan the lcores of
+type PortLcores struct {
+	Port     int // Port index
+	Socket   int // Socket of the NIC, -1 if unknown
+	RxQueues int // Number of RX queues, each gets an lcore
+	TxQueues int // Number of TX queues, each gets an lcore
+}
+
+// Assignment of an lcore in a plan
+type Assignment struct {
+	Lcore uint16
+	Role  Role
+	Port  int // Port index, -1 for the main lcore
+	Queue int // Queue of the port
+}
+
+// Label returns the short name of the assignment i.e. "RX 0.1" for queue
+// 1 of port 0
+func (a Assignment) Label() string {
+
+	if a.Role == RoleMain {
+		return a.Role.String()
+	}
+	return fmt.Sprintf("%s %d.%d", a.Role, a.Port, a.Queue)
+}
+
+// Plan is the lcores of the main lcore and the port queues
+type Plan struct {
+	Main        uint16       // Main lcore
+	Assignments []Assignment // Main lcore first, then the ports in order
+	Warnings    []string     // Compromises made by the planner
+}
+
+// Lcores returns all lcores of the plan
+func (p *Plan) Lcores() []uint16 {
+
+	l := []uint16{}
+	for _, a := range p.Assignments {
+		l = append(l, a.Lcore)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+
+	return l
+}
+
+// Lookup returns the assignment of the lcore
+func (p *Plan) Lookup(lcore uint16) (Assignment, bool) {
+
+	for _, a := range p.Assignments {
+		if a.Lcore == lcore {
+			return a, true
+		}
+	}
+	return Assignment{}, false
+}
+
+// Port returns the RX and TX lcores of the port
+func (p *Plan) Port(port int) (rx, tx []uint16) {
+
+	for _, a := range p.Assignments {
+		switch {
+		case a.Port != port:
+		case a.Role == RoleRx:
+			rx = append(rx, a.Lcore)
+		case a.Role == RoleTx:
+			tx = append(tx, a.Lcore)
+		}
+	}
+	return rx, tx
+}
+
+// String returns the port mapping of the plan in the pktgen -m format i.e.
+// "[2:3].0,[4:5].1"
+func (p *Plan) String() string {
+
+	ports := []int{}
+	seen := make(map[int]bool)
+	for _, a := range p.Assignments {
+		if a.Role != RoleMain && !seen[a.Port] {
+			seen[a.Port] = true
+			ports = append(ports, a.Port)
+		}
+	}
+
+	maps := []string{}
+	for _, port := range ports {
+		rx, tx := p.Port(port)
+		maps = append(maps, fmt.Sprintf("[%s:%s].%d", FormatList(rx), FormatList(tx), port))
+	}
+	return strings.Join(maps, ",")
+}
+
+// socketCores returns the lcores of each physical core of each socket, the
+// cores are in the order of their first lcore
+func (cd *CPUData) socketCores() map[uint16][][]uint16 {
+
+	cores := make(map[uint16][][]uint16)
+	for _, socket := range cd.sockets {
+		for _, core := range cd.cores {
+			if v, ok := cd.CoreMapItem(socket<<8 | core); ok && len(v) > 0 {
+				cores[socket] = append(cores[socket], v)
+			}
+		}
+		sort.Slice(cores[socket], func(i, j int) bool {
+			return cores[socket][i][0] < cores[socket][j][0]
+		})
+	}
+	return cores
+}
+
+// Plan returns the lcores of the main lcore and the queues of the ports
+func (cd *CPUData) Plan(ports []PortLcores) (*Plan, error) {
+
+	cores := cd.socketCores()
+
+	need := 1
+	total := 0
+	for _, p := range ports {
+		need += p.RxQueues + p.TxQueues
+	}
+	for _, c := range cores {
+		total += len(c)
+	}
+	if need > total {
+		return nil, fmt.Errorf("%d lcores on separate cores needed, the system has %d cores", need, total)
+	}
+
+	plan := &Plan{}
+
+	// Leave the core of lcore 0 to the OS if there are enough cores
+	used := make(map[uint16]bool)
+	if need < total {
+		used[0] = true
+	} else {
+		plan.Warnings = append(plan.Warnings, "the core of lcore 0 is shared with the OS")
+	}
+
+	// take returns the first lcore of a free core, on the socket if possible
+	take := func(socket int) (uint16, bool) {
+		order := append([]uint16{}, cd.sockets...)
+		sort.SliceStable(order, func(i, j int) bool {
+			return int(order[i]) == socket && int(order[j]) != socket
+		})
+		for _, s := range order {
+			for _, c := range cores[s] {
+				if !used[c[0]] {
+					used[c[0]] = true
+					return c[0], socket < 0 || int(s) == socket
+				}
+			}
+		}
+		return 0, false
+	}
+
+	// The main lcore is on the socket of the first port
+	socket := -1
+	if len(ports) > 0 {
+		socket = ports[0].Socket
+	}
+	plan.Main, _ = take(socket)
+	plan.Assignments = append(plan.Assignments, Assignment{Lcore: plan.Main, Role: RoleMain, Port: -1})
+
+	for _, p := range ports {
+		remote := []uint16{}
+		for _, role := range []Role{RoleRx, RoleTx} {
+			n := p.RxQueues
+			if role == RoleTx {
+				n = p.TxQueues
+			}
+			for q := 0; q < n; q++ {
+				lcore, local := take(p.Socket)
+				if !local {
+					remote = append(remote, lcore)
+				}
+				plan.Assignments = append(plan.Assignments,
+					Assignment{Lcore: lcore, Role: role, Port: p.Port, Queue: q})
+			}
+		}
+		if len(remote) > 0 {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("port %d on socket %d uses lcores %s of another socket",
+				p.Port, p.Socket, FormatList(remote)))
+		}
+	}
+	return plan, nil
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022 Intel Corporation
+
+package cpudata
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSysfsRoot is the root of the sysfs tree
+const DefaultSysfsRoot = "/sys"
+
+// IdleState is the time an lcore spent in a cpuidle state
+type IdleState struct {
+	Name  string        // Name of the state i.e. POLL, C1 or C6
+	Time  time.Duration // Total time spent in the state
+	Usage uint64        // Number of times the state was entered
+}
+
+// PowerState is the frequency, idle and thermal state of an lcore read from
+// the cpufreq, cpuidle and thermal_throttle directories of sysfs. The values
+// of a missing directory are zero.
+type PowerState struct {
+	Lcore            uint16
+	FreqKHz          uint64      // Current frequency
+	MaxFreqKHz       uint64      // Maximum frequency of the lcore
+	Governor         string      // Frequency governor
+	Idle             []IdleState // Idle states, lowest first
+	CoreThrottles    uint64      // Thermal throttle events of the core
+	PackageThrottles uint64      // Thermal throttle events of the package
+}
+
+// readUint returns the number in the file, 0 if it can not be read
+func readUint(path string) uint64 {
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	v, _ := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+
+	return v
+}
+
+// ReadPowerState reads the power state of the lcore below the sysfs root,
+// an empty root is DefaultSysfsRoot
+func ReadPowerState(sysfsRoot string, lcore uint16) PowerState {
+
+	if len(sysfsRoot) == 0 {
+		sysfsRoot = DefaultSysfsRoot
+	}
+	dir := filepath.Join(sysfsRoot, "devices", "system", "cpu", fmt.Sprintf("cpu%d", lcore))
+
+	ps := PowerState{
+		Lcore:            lcore,
+		FreqKHz:          readUint(filepath.Join(dir, "cpufreq", "scaling_cur_freq")),
+		MaxFreqKHz:       readUint(filepath.Join(dir, "cpufreq", "cpuinfo_max_freq")),
+		CoreThrottles:    readUint(filepath.Join(dir, "thermal_throttle", "core_throttle_count")),
+		PackageThrottles: readUint(filepath.Join(dir, "thermal_throttle", "package_throttle_count")),
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "cpufreq", "scaling_governor")); err == nil {
+		ps.Governor = strings.TrimSpace(string(b))
+	}
+
+	states, _ := filepath.Glob(filepath.Join(dir, "cpuidle", "state*"))
+	sort.Slice(states, func(i, j int) bool {
+		a, _ := strconv.Atoi(strings.TrimPrefix(filepath.Base(states[i]), "state"))
+		b, _ := strconv.Atoi(strings.TrimPrefix(filepath.Base(states[j]), "state"))
+		return a < b
+	})
+	for _, s := range states {
+		name, err := os.ReadFile(filepath.Join(s, "name"))
+		if err != nil {
+			continue
+		}
+		ps.Idle = append(ps.Idle, IdleState{
+			Name:  strings.TrimSpace(string(name)),
+			Time:  time.Duration(readUint(filepath.Join(s, "time"))) * time.Microsecond,
+			Usage: readUint(filepath.Join(s, "usage")),
+		})
+	}
+	return ps
+}
+
+// Throttles returns the thermal throttle events of the core and package
+func (ps PowerState) Throttles() uint64 {
+	return ps.CoreThrottles + ps.PackageThrottles
+}
+
+// Residency returns the percent of the elapsed time since the previous state
+// the lcore spent in each idle state, in the order of the idle states.
+func (ps PowerState) Residency(prev PowerState, elapsed time.Duration) []float64 {
+
+	r := make([]float64, len(ps.Idle))
+	if elapsed <= 0 || len(prev.Idle) != len(ps.Idle) {
+		return r
+	}
+	for i, s := range ps.Idle {
+		if d := s.Time - prev.Idle[i].Time; d > 0 {
+			r[i] = float64(d) * 100.0 / float64(elapsed)
+		}
+		if r[i] > 100.0 {
+			r[i] = 100.0
+		}
+	}
+	return r
+}
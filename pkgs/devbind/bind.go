@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2019-2020 Intel Corporation
+
+package devbind
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
+)
+
+// The devices are bound and unbound the same way dpdk-devbind.py does it,
+// by writing the PCI slot and driver names into the sysfs files of the PCI
+// bus. The sysfs root can be changed to run against a fake tree.
+
+// DefaultSysfsRoot is the root of the sysfs tree
+const DefaultSysfsRoot = "/sys"
+
+var sysfsRoot = DefaultSysfsRoot
+
+// SetSysfsRoot sets the sysfs root of the BindInfo structures created by New
+func SetSysfsRoot(root string) {
+	sysfsRoot = root
+}
+
+// pciDevicePath returns the sysfs path of a file of the PCI device
+func (db *BindInfo) pciDevicePath(slot string, file ...string) string {
+	return filepath.Join(append([]string{db.SysfsRoot, "bus", "pci", "devices", slot}, file...)...)
+}
+
+// pciDriverPath returns the sysfs path of a file of the PCI driver
+func (db *BindInfo) pciDriverPath(driver string, file ...string) string {
+	return filepath.Join(append([]string{db.SysfsRoot, "bus", "pci", "drivers", driver}, file...)...)
+}
+
+// writeSysfs writes the value to an existing sysfs file
+func writeSysfs(path, value string) error {
+
+	fd, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return err
+	}
+
+	if _, err = fd.Write([]byte(value)); err != nil {
+		fd.Close()
+		return fmt.Errorf("write %s to %s: %w", strings.TrimSpace(value), path, err)
+	}
+	return fd.Close()
+}
+
+// checkDevice returns the device of the slot, it fails for unknown devices,
+// for devices with an interface carrying the active route and for all
+// devices when the routes could not be read.
+func (db *BindInfo) checkDevice(slot string) (*DeviceClass, error) {
+
+	dev, ok := db.Devices[slot]
+	if !ok {
+		return nil, fmt.Errorf("unknown device %s", slot)
+	}
+	if db.routesErr != nil {
+		return nil, fmt.Errorf("routes unknown, not modifying device %s: %w", slot, db.routesErr)
+	}
+	if dev.Active || len(dev.SSHIf) > 0 {
+		return nil, fmt.Errorf("device %s interface %s carries the active route, not modifying it",
+			slot, dev.Interface)
+	}
+	return dev, nil
+}
+
+// CurrentDriver returns the driver the device is bound to from sysfs, the
+// name is empty if the device is not bound.
+func (db *BindInfo) CurrentDriver(slot string) (string, error) {
+
+	link, err := os.Readlink(db.pciDevicePath(slot, "driver"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return filepath.Base(link), nil
+}
+
+// IsDriverLoaded returns true if the driver is registered on the PCI bus
+func (db *BindInfo) IsDriverLoaded(driver string) bool {
+
+	_, err := os.Stat(db.pciDriverPath(driver))
+
+	return err == nil
+}
+
+// Unbind the device from its driver
+func (db *BindInfo) Unbind(slot string) error {
+
+	dev, err := db.checkDevice(slot)
+	if err != nil {
+		return err
+	}
+
+	driver, err := db.CurrentDriver(slot)
+	if err != nil {
+		return err
+	}
+	if len(driver) == 0 {
+		return fmt.Errorf("device %s is not bound to a driver", slot)
+	}
+
+	tlog.InfoPrintf("Unbind %s from %s\n", slot, driver)
+
+	if err := writeSysfs(db.pciDevicePath(slot, "driver", "unbind"), slot); err != nil {
+		return fmt.Errorf("unbind %s from %s: %w", slot, driver, err)
+	}
+
+	dev.Driver = ""
+	dev.Interface = ""
+
+	return nil
+}
+
+// SetDriverOverride sets the driver the device binds to on the next probe,
+// an empty driver name clears the override.
+func (db *BindInfo) SetDriverOverride(slot, driver string) error {
+
+	if _, err := db.checkDevice(slot); err != nil {
+		return err
+	}
+
+	// A single newline clears the override
+	if err := writeSysfs(db.pciDevicePath(slot, "driver_override"), driver+"\n"); err != nil {
+		return fmt.Errorf("driver_override of %s: %w", slot, err)
+	}
+	return nil
+}
+
+// Bind the device to the driver, the device is unbound from its current
+// driver first and the driver must be loaded.
+func (db *BindInfo) Bind(slot, driver string) error {
+
+	dev, err := db.checkDevice(slot)
+	if err != nil {
+		return err
+	}
+
+	if !db.IsDriverLoaded(driver) {
+		return fmt.Errorf("driver %s is not loaded", driver)
+	}
+
+	current, err := db.CurrentDriver(slot)
+	if err != nil {
+		return err
+	}
+	if current == driver {
+		return nil
+	}
+	if len(current) > 0 {
+		if err := db.Unbind(slot); err != nil {
+			return err
+		}
+	}
+
+	if err := db.SetDriverOverride(slot, driver); err != nil {
+		return err
+	}
+
+	tlog.InfoPrintf("Bind %s to %s\n", slot, driver)
+
+	err = writeSysfs(db.pciDriverPath(driver, "bind"), slot)
+
+	// Clear the override so the device can be bound to any other driver
+	if e := db.SetDriverOverride(slot, ""); e != nil && err == nil {
+		err = e
+	}
+	if err != nil {
+		return fmt.Errorf("bind %s to %s: %w", slot, driver, err)
+	}
+
+	dev.Driver = driver
+	dev.Interface = db.deviceInterfaces(slot)
+
+	return nil
+}
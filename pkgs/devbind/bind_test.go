@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2019-2020 Intel Corporation
+
+package devbind
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeSysfs creates a sysfs tree with the device bound to the driver and the
+// given drivers loaded.
+func fakeSysfs(t *testing.T, slot, driver string, drivers ...string) *BindInfo {
+	t.Helper()
+
+	root := t.TempDir()
+	db := &BindInfo{
+		Devices:   DeviceList{slot: {Slot: slot, Driver: driver}},
+		SysfsRoot: root,
+	}
+
+	mkfile := func(path string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, d := range append(drivers, driver) {
+		mkfile(db.pciDriverPath(d, "bind"))
+		mkfile(db.pciDriverPath(d, "unbind"))
+	}
+	mkfile(db.pciDevicePath(slot, "driver_override"))
+	if err := os.Symlink(db.pciDriverPath(driver), db.pciDevicePath(slot, "driver")); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestBind(t *testing.T) {
+	const slot = "0000:18:00.0"
+
+	db := fakeSysfs(t, slot, "i40e", "vfio-pci")
+
+	if d, err := db.CurrentDriver(slot); err != nil || d != "i40e" {
+		t.Fatalf("CurrentDriver returned %q, %v", d, err)
+	}
+
+	if err := db.Bind(slot, "vfio-pci"); err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	if s := readFile(t, db.pciDriverPath("i40e", "unbind")); s != slot {
+		t.Errorf("unbind file has %q, want %q", s, slot)
+	}
+	if s := readFile(t, db.pciDriverPath("vfio-pci", "bind")); s != slot {
+		t.Errorf("bind file has %q, want %q", s, slot)
+	}
+	// The override is cleared after the bind
+	if s := readFile(t, db.pciDevicePath(slot, "driver_override")); s != "\n" {
+		t.Errorf("driver_override has %q, want a newline", s)
+	}
+	if db.Devices[slot].Driver != "vfio-pci" {
+		t.Errorf("device driver is %q", db.Devices[slot].Driver)
+	}
+
+	if err := db.Bind(slot, "igb_uio"); err == nil || !strings.Contains(err.Error(), "not loaded") {
+		t.Errorf("Bind to a driver not loaded returned %v", err)
+	}
+	if err := db.Unbind("0000:99:00.0"); err == nil {
+		t.Errorf("Unbind of an unknown device did not fail")
+	}
+}
+
+func TestActiveDevice(t *testing.T) {
+	const slot = "0000:18:00.1"
+
+	db := fakeSysfs(t, slot, "ixgbe", "vfio-pci")
+	db.Devices[slot].Interface = "eth0"
+	db.Devices[slot].Active = true
+
+	if err := db.Unbind(slot); err == nil {
+		t.Errorf("Unbind of the device with the active route did not fail")
+	}
+	if err := db.Bind(slot, "vfio-pci"); err == nil {
+		t.Errorf("Bind of the device with the active route did not fail")
+	}
+	if err := db.SetDriverOverride(slot, "vfio-pci"); err == nil {
+		t.Errorf("SetDriverOverride of the device with the active route did not fail")
+	}
+	if s := readFile(t, db.pciDriverPath("ixgbe", "unbind")); s != "" {
+		t.Errorf("device with the active route was unbound")
+	}
+
+	db.Devices[slot].Active = false
+	db.Devices[slot].SSHIf = "eth0"
+	if err := db.Unbind(slot); err == nil {
+		t.Errorf("Unbind of the ssh interface did not fail")
+	}
+
+	db.Devices[slot].SSHIf = ""
+	db.routesErr = fmt.Errorf("netlink route dump: permission denied")
+	if err := db.Unbind(slot); err == nil {
+		t.Errorf("Unbind with unknown routes did not fail")
+	}
+
+	db.routesErr = nil
+	if err := db.Unbind(slot); err != nil {
+		t.Errorf("Unbind: %v", err)
+	}
+}
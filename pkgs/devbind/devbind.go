@@ -5,8 +5,8 @@ package devbind
 
 import (
 	"fmt"
-	"log"
-	"os/exec"
+	"net"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -19,6 +19,8 @@ type BindInfo struct {
 	Devices    DeviceList
 	CfgDevices DevConfigs
 	Groups     DevGroups
+	SysfsRoot  string // Root of the sysfs tree, see SetSysfsRoot
+	routesErr  error  // Error reading the routes, no device is modified
 }
 
 // UioModules supported
@@ -28,25 +30,22 @@ func init() {
 	tlog.Register("devBindLog")
 }
 
-// New - create a new DevBindInfo structure
-func New(devFile ...string) *BindInfo {
+// New - create a new DevBindInfo structure with the default devices and
+// the devices of the device files layered on top in the given order.
+func New(devFiles ...string) (*BindInfo, error) {
 
-	db := &BindInfo{}
-	file := ""
-	if len(devFile) == 0 {
-		file = "devices.toml"
-	} else {
-		if len(devFile) > 1 {
-			return nil
-		}
-		file = devFile[0]
-	}
-
-	tlog.InfoPrintf("Process TOML device file (%s)\n", file)
+	db := &BindInfo{SysfsRoot: sysfsRoot}
 
+	cfgs, grps, err := LoadDeviceFiles(devFiles...)
+	if err != nil {
+		return nil, err
+	}
 	db.Devices = make(DeviceList)
-	db.CfgDevices = make(DevConfigs)
-	db.Groups = make(DevGroups)
+	db.CfgDevices = cfgs
+	db.Groups = grps
+
+	tlog.DebugPrintf("===== CfgDevices:\n%s\n", spew.Sdump(db.CfgDevices))
+	tlog.DebugPrintf("===== Groups:\n%s\n", spew.Sdump(db.Groups))
 
 	db.getDetails(db.CfgDevices)
 
@@ -54,45 +53,75 @@ func New(devFile ...string) *BindInfo {
 		tlog.DebugPrintf("Slot: %s = %+v\n", k, d)
 	}
 
-	if cfgs, grps, err := LoadDeviceFile(file); err != nil {
-		log.Fatal(err)
-	} else {
-		db.CfgDevices = cfgs
-		db.Groups = grps
-	}
-	tlog.DebugPrintf("===== CfgDevices:\n%s\n", spew.Sdump(db.CfgDevices))
-	tlog.DebugPrintf("===== Groups:\n%s\n", spew.Sdump(db.Groups))
-
-	db.getDetails(db.CfgDevices)
-
-	return db
+	return db, nil
 }
 
-func checkOutput(args ...string) (string, error) {
+// readSysfs returns the trimmed content of a sysfs file of the device
+func (db *BindInfo) readSysfs(slot, file string) string {
 
-	baseCmd := args[0]
-	cmdArgs := args[1:]
-
-	cmd := exec.Command(baseCmd, cmdArgs...)
-	out, err := cmd.Output()
+	b, err := os.ReadFile(db.pciDevicePath(slot, file))
 	if err != nil {
-		return "", err
+		return ""
 	}
-	return string(out), nil
+	return strings.TrimSpace(string(b))
 }
 
-func (db *BindInfo) getDeviceDetails(dev *DeviceClass) {
+// readID returns the hex ID of a sysfs file without the 0x prefix
+func (db *BindInfo) readID(slot, file string) string {
+	return strings.TrimPrefix(db.readSysfs(slot, file), "0x")
+}
 
-	paths, err := filepath.Glob(fmt.Sprintf("/sys/bus/pci/devices/%s/net", dev.Slot))
+// readDevice returns the device of the PCI slot from sysfs
+func (db *BindInfo) readDevice(slot string) (*DeviceClass, error) {
+
+	dev := &DeviceClass{Slot: slot}
+
+	class := db.readID(slot, "class")
+	if len(class) != 6 {
+		return nil, fmt.Errorf("invalid class %q of %s", class, slot)
+	}
+	dev.Class.ID = DevClassID(class[0:2])
+	dev.Class.Sub = DevSubClassID(class[2:4])
+	dev.Vendor.ID = VendorID(db.readID(slot, "vendor"))
+	dev.Device.ID = DeviceID(db.readID(slot, "device"))
+	dev.SVendor.ID = SVendorID(db.readID(slot, "subsystem_vendor"))
+	dev.SDevice.ID = SDeviceID(db.readID(slot, "subsystem_device"))
+	dev.Rev = db.readID(slot, "revision")
+
+	// The node is -1 on systems without NUMA
+	if node := db.readSysfs(slot, "numa_node"); node != "-1" {
+		dev.NumaNode = node
+	}
+
+	driver, err := db.CurrentDriver(slot)
 	if err != nil {
-		return
+		return nil, err
+	}
+	dev.Driver = driver
+	if len(driver) > 0 {
+		if link, err := os.Readlink(db.pciDriverPath(driver, "module")); err == nil {
+			dev.Module = filepath.Base(link)
+		}
 	}
 
-	for _, path := range paths {
-		files := []string{}
+	ids := PCIIDDatabase()
+	dev.Class.Str = ids.Class(string(dev.Class.ID), string(dev.Class.Sub))
+	dev.Vendor.Str = ids.Vendor(string(dev.Vendor.ID))
+	dev.Device.Str = ids.Device(string(dev.Vendor.ID), string(dev.Device.ID))
+	dev.SVendor.Str = ids.Vendor(string(dev.SVendor.ID))
+	dev.SDevice.Str = ids.Subsystem(string(dev.Vendor.ID), string(dev.Device.ID),
+		string(dev.SVendor.ID), string(dev.SDevice.ID))
 
-		ifaces, err := filepath.Glob(path + "/*")
+	return dev, nil
+}
+
+// deviceInterfaces returns the comma separated network interfaces of the
+// device, the interfaces of virtio devices are below the virtio device.
+func (db *BindInfo) deviceInterfaces(slot string) string {
 
+	files := []string{}
+	for _, pattern := range []string{"net/*", "virtio*/net/*"} {
+		ifaces, err := filepath.Glob(db.pciDevicePath(slot, pattern))
 		if err != nil {
 			continue
 		}
@@ -102,132 +131,94 @@ func (db *BindInfo) getDeviceDetails(dev *DeviceClass) {
 		for _, iface := range ifaces {
 			files = append(files, filepath.Base(iface))
 		}
-		dev.Interface = strings.Join(files, ",")
 	}
-	dev.SSHIf = ""
-	dev.Active = false
+	return strings.Join(files, ",")
+}
+
+// sshInterface returns the interface of the address of the ssh session
+// running the application, empty if it does not run in a ssh session.
+func sshInterface() string {
 
-	out, err := checkOutput("ip", "-o", "route")
+	// SSH_CONNECTION is "client_ip client_port server_ip server_port"
+	f := strings.Fields(os.Getenv("SSH_CONNECTION"))
+	if len(f) != 4 {
+		return ""
+	}
+	ip := net.ParseIP(f[2])
+
+	ifaces, err := net.Interfaces()
 	if err != nil {
-		return
+		return ""
+	}
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+				return iface.Name
+			}
+		}
 	}
-	devLines := strings.Split(out, "\n")
+	return ""
+}
+
+// getDeviceDetails sets the interfaces of the device and flags the device
+// carrying a route or the ssh session.
+func (db *BindInfo) getDeviceDetails(dev *DeviceClass, active map[string]bool, sshIf string) {
 
-	for _, route := range devLines {
-		words := strings.Split(route, " ")
+	dev.Interface = db.deviceInterfaces(dev.Slot)
+	dev.SSHIf = ""
+	dev.Active = false
 
-		if strings.Contains(words[0], "169.254.") {
+	for _, iface := range strings.Split(dev.Interface, ",") {
+		if len(iface) == 0 {
 			continue
 		}
-		for i, word := range words {
-			if word == "dev" {
-				iface := words[i+1]
-				for _, dev := range db.Devices {
-					if dev.Interface == iface {
-						dev.Active = true
-					}
-				}
-			}
+		if active[iface] {
+			dev.Active = true
+		}
+		if iface == sshIf {
+			dev.SSHIf = iface
 		}
 	}
 }
 
+// getDetails adds the PCI devices of sysfs matching one of the device types
 func (db *BindInfo) getDetails(devicesType DevConfigs) {
 
-	out, err := checkOutput("lspci", "-Dvmmnnk")
+	slots, err := filepath.Glob(db.pciDevicePath("*"))
 	if err != nil {
 		return
 	}
-	devLines := strings.Split(out, "\n")
-
-	dev := &DeviceClass{}
-	for _, devLine := range devLines {
-		if len(devLine) == 0 {
-			for _, d := range devicesType {
-				if len(dev.Slot) == 0 {
-					break
-				}
-				if compareDevices(d, dev) {
-					db.Devices[dev.Slot] = dev
-					tlog.DebugPrintf("Add: Slot %s, Class: %v, Vendor %s, Device %s, SVendor %s, SDevice %s\n",
-						dev.Slot, dev.Class.ID, dev.Vendor.ID, dev.Device.ID, dev.SVendor.ID, dev.SDevice.ID)
-					break
-				}
-			}
-			dev = &DeviceClass{}
-		} else {
-			vals := strings.Split(devLine, "\t")
-			name := strings.TrimRight(vals[0], ":")
-			value := vals[1]
-
-			switch name {
-			case "Slot":
-				dev.Slot = value
-				tlog.DebugPrintf("\nSlot   : %s\n", dev.Slot)
-			case "Class":
-				id, _, desc := devClassID(value)
-				dev.Class.ID = id
-				dev.Class.Str = desc
-				tlog.DebugPrintf("Class  : [%02x] %s\n", dev.Class.ID, dev.Class.Str)
-			case "Vendor":
-				id, desc := getDeviceID(value)
-				dev.Vendor.ID = VendorID(id)
-				dev.Vendor.Str = desc
-				tlog.DebugPrintf("Vendor : [%s] %s\n", dev.Vendor.ID, dev.Vendor.Str)
-			case "Device":
-				id, desc := getDeviceID(value)
-				dev.Device.ID = DeviceID(id)
-				dev.Device.Str = desc
-				tlog.DebugPrintf("Device : [%s] %s\n", dev.Device.ID, dev.Device.Str)
-			case "SVendor":
-				id, desc := getDeviceID(value)
-				dev.SVendor.ID = SVendorID(id)
-				dev.SVendor.Str = desc
-				tlog.DebugPrintf("SVendor: [%s] %s\n", dev.SVendor.ID, dev.SVendor.Str)
-			case "SDevice":
-				id, desc := getDeviceID(value)
-				dev.SDevice.ID = SDeviceID(id)
-				dev.SDevice.Str = desc
-				tlog.DebugPrintf("SDevice: [%s] %s\n", dev.SDevice.ID, dev.SDevice.Str)
-			case "Rev":
-				dev.Rev = value
-			case "Driver":
-				dev.Driver = value
-			case "Module":
-				dev.Module = value
-			case "NUMANode":
-				dev.NumaNode = value
+
+	for _, path := range slots {
+		dev, err := db.readDevice(filepath.Base(path))
+		if err != nil {
+			tlog.WarnPrintf("PCI device: %s\n", err)
+			continue
+		}
+		for _, d := range devicesType {
+			if compareDevices(d, dev) {
+				db.Devices[dev.Slot] = dev
+				tlog.DebugPrintf("Add: Slot %s, Class: %v, Vendor %s, Device %s, SVendor %s, SDevice %s\n",
+					dev.Slot, dev.Class.ID, dev.Vendor.ID, dev.Device.ID, dev.SVendor.ID, dev.SDevice.ID)
+				break
 			}
 		}
 	}
-	delete(db.Devices, "")
-
-	for _, dev := range db.Devices {
-		db.getDeviceDetails(dev)
-	}
-}
 
-func devClassID(str string) (DevClassID, DevSubClassID, string) {
-
-	start := strings.Index(str, "[")
-
-	if start == -1 {
-		return "FF", "FF", ""
+	active, err := routeInterfaces()
+	if err != nil {
+		tlog.WarnPrintf("Routes: %s\n", err)
 	}
-	devClass := str[start+1 : start+3]
-	subClass := str[start+2 : start+5]
+	db.routesErr = err
+	sshIf := sshInterface()
 
-	return DevClassID(devClass), DevSubClassID(subClass), str[:start-1]
-}
-
-func getDeviceID(str string) (string, string) {
-	start := strings.Index(str, "[")
-	end := strings.Index(str, "]")
-
-	if start == -1 || end == -1 {
-		return "", ""
+	for _, dev := range db.Devices {
+		db.getDeviceDetails(dev, active, sshIf)
 	}
-	return str[start+1 : end], str[:start-1]
 }
 
 // FindDevicesByDeviceClass all devices matching the given device class
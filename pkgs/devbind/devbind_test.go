@@ -5,6 +5,8 @@ package devbind
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"testing"
 )
@@ -17,3 +19,104 @@ func TestClose(t *testing.T) {
 	fmt.Printf("Close Devbind\n")
 
 }
+
+// addFakeDevice adds a PCI device to the sysfs tree of db
+func addFakeDevice(t *testing.T, db *BindInfo, slot string, files map[string]string) {
+	t.Helper()
+
+	if err := os.MkdirAll(db.pciDevicePath(slot), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range files {
+		path := db.pciDevicePath(slot, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(value+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGetDetails(t *testing.T) {
+
+	db := &BindInfo{Devices: make(DeviceList), SysfsRoot: t.TempDir()}
+
+	addFakeDevice(t, db, "0000:18:00.0", map[string]string{
+		"class":            "0x020000",
+		"vendor":           "0x8086",
+		"device":           "0x1572",
+		"subsystem_vendor": "0x8086",
+		"subsystem_device": "0x0001",
+		"revision":         "0x01",
+		"numa_node":        "1",
+		"net/eth1/ifindex": "3",
+	})
+	addFakeDevice(t, db, "0000:00:03.0", map[string]string{
+		"class":                    "0x020000",
+		"vendor":                   "0x1af4",
+		"device":                   "0x1000",
+		"subsystem_vendor":         "0x1af4",
+		"subsystem_device":         "0x0001",
+		"numa_node":                "-1",
+		"virtio0/net/eth0/ifindex": "2",
+	})
+	// Not a network device
+	addFakeDevice(t, db, "0000:00:1f.0", map[string]string{
+		"class":            "0x060100",
+		"vendor":           "0x8086",
+		"device":           "0x2918",
+		"subsystem_vendor": "0x8086",
+		"subsystem_device": "0x0000",
+	})
+
+	drv := filepath.Join(db.SysfsRoot, "bus", "pci", "drivers", "i40e")
+	mod := filepath.Join(db.SysfsRoot, "module", "i40e")
+	for _, dir := range []string{drv, mod} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(mod, filepath.Join(drv, "module")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(drv, db.pciDevicePath("0000:18:00.0", "driver")); err != nil {
+		t.Fatal(err)
+	}
+
+	saved := routeInterfaces
+	defer func() { routeInterfaces = saved }()
+	routeInterfaces = func() (map[string]bool, error) {
+		return map[string]bool{"eth0": true}, nil
+	}
+
+	network := &DeviceConfig{}
+	network.Class.ID = "02"
+	db.getDetails(DevConfigs{"Network": network})
+
+	if len(db.Devices) != 2 {
+		t.Fatalf("found %d devices, want 2", len(db.Devices))
+	}
+
+	dev := db.Devices["0000:18:00.0"]
+	if dev.Vendor.ID != "8086" || dev.Device.ID != "1572" || dev.Class.Sub != "00" {
+		t.Errorf("bad IDs %+v", dev)
+	}
+	if dev.Driver != "i40e" || dev.Module != "i40e" || dev.NumaNode != "1" {
+		t.Errorf("driver %q, module %q, numa node %q", dev.Driver, dev.Module, dev.NumaNode)
+	}
+	if dev.Interface != "eth1" || dev.Active {
+		t.Errorf("interface %q, active %v", dev.Interface, dev.Active)
+	}
+	if len(dev.Vendor.Str) == 0 || len(dev.Class.Str) == 0 {
+		t.Errorf("missing names %+v", dev)
+	}
+
+	dev = db.Devices["0000:00:03.0"]
+	if dev.Interface != "eth0" || !dev.Active {
+		t.Errorf("virtio interface %q, active %v", dev.Interface, dev.Active)
+	}
+	if dev.NumaNode != "" || dev.Driver != "" {
+		t.Errorf("virtio numa node %q, driver %q", dev.NumaNode, dev.Driver)
+	}
+}
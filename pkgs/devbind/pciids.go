@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2019-2020 Intel Corporation
+
+package devbind
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
+)
+
+// bundledPCIIDs is the subset of pci.ids used when the system has none
+//
+//go:embed pci.ids
+var bundledPCIIDs []byte
+
+// PCIIDFiles are the locations of the system pci.ids file
+var PCIIDFiles = []string{
+	"/usr/share/hwdata/pci.ids",
+	"/usr/share/misc/pci.ids",
+	"/usr/share/pci.ids",
+}
+
+// PCIIDs is the database of the vendor, device and class names of the PCI
+// ID Repository, the IDs are lower case hex strings without 0x.
+type PCIIDs struct {
+	vendors    map[string]string // vendor
+	devices    map[string]string // vendor:device
+	subsystems map[string]string // vendor:device:subvendor:subdevice
+	classes    map[string]string // class
+	subclasses map[string]string // class:subclass
+}
+
+var (
+	pciIDsOnce sync.Once
+	pciIDs     *PCIIDs
+)
+
+// ParsePCIIDs reads a database in the pci.ids format
+func ParsePCIIDs(r io.Reader) (*PCIIDs, error) {
+
+	ids := &PCIIDs{
+		vendors:    make(map[string]string),
+		devices:    make(map[string]string),
+		subsystems: make(map[string]string),
+		classes:    make(map[string]string),
+		subclasses: make(map[string]string),
+	}
+
+	// split returns the ID and the name of a line
+	split := func(line string) (string, string) {
+		f := strings.SplitN(line, "  ", 2)
+		if len(f) != 2 {
+			return "", ""
+		}
+		return strings.ToLower(strings.TrimSpace(f[0])), strings.TrimSpace(f[1])
+	}
+
+	var vendor, device, class string
+	inClasses := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(strings.TrimSpace(line)) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "C "):
+			inClasses = true
+			id, name := split(line[2:])
+			class = id
+			ids.classes[id] = name
+		case line[0] != '\t':
+			// The device class list is followed by other lists
+			inClasses = false
+			id, name := split(line)
+			vendor, device = id, ""
+			if len(id) > 0 {
+				ids.vendors[id] = name
+			}
+		case inClasses:
+			if !strings.HasPrefix(line, "\t\t") {
+				id, name := split(line[1:])
+				ids.subclasses[class+":"+id] = name
+			}
+		case strings.HasPrefix(line, "\t\t"):
+			id, name := split(line[2:])
+			if f := strings.Fields(id); len(f) == 2 && len(device) > 0 {
+				ids.subsystems[vendor+":"+device+":"+f[0]+":"+f[1]] = name
+			}
+		default:
+			id, name := split(line[1:])
+			device = id
+			ids.devices[vendor+":"+id] = name
+		}
+	}
+	return ids, scanner.Err()
+}
+
+// loadPCIIDs returns the system pci.ids database or the bundled subset
+func loadPCIIDs() *PCIIDs {
+
+	for _, file := range PCIIDFiles {
+		fd, err := os.Open(file)
+		if err != nil {
+			continue
+		}
+		ids, err := ParsePCIIDs(fd)
+		fd.Close()
+		if err == nil {
+			tlog.DebugPrintf("PCI IDs from %s\n", file)
+			return ids
+		}
+	}
+
+	ids, _ := ParsePCIIDs(bytes.NewReader(bundledPCIIDs))
+
+	return ids
+}
+
+// PCIIDDatabase returns the PCI ID database, loaded on first use
+func PCIIDDatabase() *PCIIDs {
+
+	pciIDsOnce.Do(func() {
+		pciIDs = loadPCIIDs()
+	})
+	return pciIDs
+}
+
+// Vendor returns the name of the vendor
+func (ids *PCIIDs) Vendor(vendor string) string {
+	return ids.vendors[vendor]
+}
+
+// Device returns the name of the device
+func (ids *PCIIDs) Device(vendor, device string) string {
+	return ids.devices[vendor+":"+device]
+}
+
+// Subsystem returns the name of the subsystem of a device
+func (ids *PCIIDs) Subsystem(vendor, device, subVendor, subDevice string) string {
+	return ids.subsystems[vendor+":"+device+":"+subVendor+":"+subDevice]
+}
+
+// Class returns the name of the subclass of a device class, or the name of
+// the class if the subclass is unknown.
+func (ids *PCIIDs) Class(class, subClass string) string {
+
+	if name, ok := ids.subclasses[class+":"+subClass]; ok {
+		return name
+	}
+	return ids.classes[class]
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2019-2020 Intel Corporation
+
+package devbind
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testPCIIDs = `# Comment
+8086  Intel Corporation
+	1572  Ethernet Controller X710 for 10GbE SFP+
+		8086 0001  Ethernet Converged Network Adapter X710-4
+	37c8  C62x Chipset QuickAssist Technology
+1af4  Red Hat, Inc.
+	1000  Virtio network device
+
+C 02  Network controller
+	00  Ethernet controller
+		00  Unused
+C 0b  Processor
+`
+
+func TestParsePCIIDs(t *testing.T) {
+
+	ids, err := ParsePCIIDs(strings.NewReader(testPCIIDs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		got, want string
+	}{
+		{ids.Vendor("8086"), "Intel Corporation"},
+		{ids.Device("8086", "1572"), "Ethernet Controller X710 for 10GbE SFP+"},
+		{ids.Device("8086", "37c8"), "C62x Chipset QuickAssist Technology"},
+		{ids.Subsystem("8086", "1572", "8086", "0001"), "Ethernet Converged Network Adapter X710-4"},
+		{ids.Device("1af4", "1000"), "Virtio network device"},
+		{ids.Class("02", "00"), "Ethernet controller"},
+		{ids.Class("02", "80"), "Network controller"},
+		{ids.Class("0b", "40"), "Processor"},
+		{ids.Vendor("1234"), ""},
+	}
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%d: got %q, want %q", i, tt.got, tt.want)
+		}
+	}
+}
+
+func TestBundledPCIIDs(t *testing.T) {
+
+	ids, err := ParsePCIIDs(bytes.NewReader(bundledPCIIDs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := ids.Device("8086", "1572"); len(s) == 0 {
+		t.Error("X710 missing from the bundled PCI IDs")
+	}
+	if s := ids.Class("02", "00"); s != "Ethernet controller" {
+		t.Errorf("class 0200 is %q", s)
+	}
+}
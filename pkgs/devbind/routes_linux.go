@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2019-2020 Intel Corporation
+
+package devbind
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+	"unsafe"
+)
+
+// routeInterfaces returns the interfaces of the IPv4 and IPv6 routes of the
+// main routing table read over netlink, including the interfaces of every
+// next-hop of a multipath route. The link local routes are skipped.
+var routeInterfaces = func() (map[string]bool, error) {
+
+	ifaces := make(map[string]bool)
+	for _, family := range []int{syscall.AF_INET, syscall.AF_INET6} {
+		b, err := syscall.NetlinkRIB(syscall.RTM_GETROUTE, family)
+		if err != nil {
+			return nil, fmt.Errorf("netlink route dump: %w", err)
+		}
+		msgs, err := syscall.ParseNetlinkMessage(b)
+		if err != nil {
+			return nil, fmt.Errorf("netlink route messages: %w", err)
+		}
+
+		for _, index := range routeIndexes(msgs) {
+			if iface, err := net.InterfaceByIndex(index); err == nil {
+				ifaces[iface.Name] = true
+			}
+		}
+	}
+	return ifaces, nil
+}
+
+// linkLocal are the link local networks of IPv4 and IPv6
+var linkLocal = []*net.IPNet{
+	{IP: net.IPv4(169, 254, 0, 0), Mask: net.CIDRMask(16, 32)},
+	{IP: net.ParseIP("fe80::"), Mask: net.CIDRMask(10, 128)},
+}
+
+// routeIndexes returns the output interface indexes of the routes of the
+// main table in the netlink messages
+func routeIndexes(msgs []syscall.NetlinkMessage) []int {
+
+	indexes := []int{}
+	for i := range msgs {
+		m := &msgs[i]
+		if m.Header.Type != syscall.RTM_NEWROUTE || len(m.Data) < syscall.SizeofRtMsg {
+			continue
+		}
+		rt := (*syscall.RtMsg)(unsafe.Pointer(&m.Data[0]))
+		if rt.Table != syscall.RT_TABLE_MAIN {
+			continue
+		}
+
+		attrs, err := syscall.ParseNetlinkRouteAttr(m)
+		if err != nil {
+			continue
+		}
+
+		route := []int{}
+		skip := false
+		for _, a := range attrs {
+			switch a.Attr.Type {
+			case syscall.RTA_DST:
+				for _, n := range linkLocal {
+					if n.Contains(net.IP(a.Value)) {
+						skip = true
+					}
+				}
+			case syscall.RTA_OIF:
+				if len(a.Value) >= 4 {
+					route = append(route, int(*(*uint32)(unsafe.Pointer(&a.Value[0]))))
+				}
+			case syscall.RTA_MULTIPATH:
+				route = append(route, nexthopIndexes(a.Value)...)
+			}
+		}
+		if skip {
+			continue
+		}
+		for _, index := range route {
+			if index != 0 {
+				indexes = append(indexes, index)
+			}
+		}
+	}
+	return indexes
+}
+
+// nexthopIndexes returns the interface indexes of the next-hops of a
+// multipath route attribute
+func nexthopIndexes(b []byte) []int {
+
+	indexes := []int{}
+	for len(b) >= syscall.SizeofRtNexthop {
+		nh := (*syscall.RtNexthop)(unsafe.Pointer(&b[0]))
+		if int(nh.Len) < syscall.SizeofRtNexthop || int(nh.Len) > len(b) {
+			break
+		}
+		indexes = append(indexes, int(nh.Ifindex))
+
+		// Each next-hop is aligned to 4 bytes and followed by its attributes
+		next := (int(nh.Len) + syscall.NLMSG_ALIGNTO - 1) &^ (syscall.NLMSG_ALIGNTO - 1)
+		if next > len(b) {
+			break
+		}
+		b = b[next:]
+	}
+	return indexes
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2019-2020 Intel Corporation
+
+package devbind
+
+import (
+	"encoding/binary"
+	"net"
+	"reflect"
+	"syscall"
+	"testing"
+)
+
+// routeAttr returns the netlink route attribute padded to 4 bytes
+func routeAttr(typ uint16, value []byte) []byte {
+
+	b := make([]byte, syscall.SizeofRtAttr, syscall.SizeofRtAttr+len(value)+3)
+	binary.LittleEndian.PutUint16(b[0:], uint16(syscall.SizeofRtAttr+len(value)))
+	binary.LittleEndian.PutUint16(b[2:], typ)
+	b = append(b, value...)
+	for len(b)%4 != 0 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+// nexthop returns a multipath next-hop of the interface index with a
+// gateway attribute
+func nexthop(index int, gw net.IP) []byte {
+
+	attr := routeAttr(syscall.RTA_GATEWAY, gw)
+
+	b := make([]byte, syscall.SizeofRtNexthop)
+	binary.LittleEndian.PutUint16(b[0:], uint16(syscall.SizeofRtNexthop+len(attr)))
+	binary.LittleEndian.PutUint32(b[4:], uint32(index))
+	return append(b, attr...)
+}
+
+// routeMessage returns a route message of the main table with the attributes
+func routeMessage(family uint8, attrs ...[]byte) syscall.NetlinkMessage {
+
+	data := make([]byte, syscall.SizeofRtMsg)
+	data[0] = family
+	data[4] = syscall.RT_TABLE_MAIN
+	for _, a := range attrs {
+		data = append(data, a...)
+	}
+	return syscall.NetlinkMessage{
+		Header: syscall.NlMsghdr{Type: syscall.RTM_NEWROUTE},
+		Data:   data,
+	}
+}
+
+func TestRouteIndexes(t *testing.T) {
+
+	oif := func(index int) []byte {
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, uint32(index))
+		return routeAttr(syscall.RTA_OIF, b)
+	}
+	multipath := append(nexthop(3, net.ParseIP("2001:db8::1")), nexthop(4, net.ParseIP("2001:db8::2"))...)
+
+	msgs := []syscall.NetlinkMessage{
+		routeMessage(syscall.AF_INET, routeAttr(syscall.RTA_DST, net.IPv4(10, 0, 0, 0).To4()), oif(2)),
+		routeMessage(syscall.AF_INET, routeAttr(syscall.RTA_DST, net.IPv4(169, 254, 0, 0).To4()), oif(5)),
+		routeMessage(syscall.AF_INET6, routeAttr(syscall.RTA_DST, net.ParseIP("fe80::")), oif(6)),
+		routeMessage(syscall.AF_INET6, routeAttr(syscall.RTA_MULTIPATH, multipath)),
+	}
+
+	if got := routeIndexes(msgs); !reflect.DeepEqual(got, []int{2, 3, 4}) {
+		t.Errorf("route indexes got %v", got)
+	}
+
+	// A truncated next-hop is ignored
+	if got := nexthopIndexes(multipath[:len(multipath)-4]); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("truncated next-hops got %v", got)
+	}
+}
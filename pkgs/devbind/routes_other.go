@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2019-2020 Intel Corporation
+
+//go:build !linux
+
+package devbind
+
+import "fmt"
+
+// routeInterfaces is only supported on Linux
+var routeInterfaces = func() (map[string]bool, error) {
+	return nil, fmt.Errorf("routes are only read on linux")
+}
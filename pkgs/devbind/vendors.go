@@ -4,13 +4,21 @@
 package devbind
 
 import (
+	_ "embed"
 	"fmt"
+	"sort"
+
 	"github.com/BurntSushi/toml"
 
 	"github.com/davecgh/go-spew/spew"
 	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
 )
 
+// defaultDeviceFile is the default device database
+//
+//go:embed devices.toml
+var defaultDeviceFile string
+
 // DeviceID type
 type DeviceID string
 
@@ -157,7 +165,7 @@ type DeviceConfig struct {
 // DeviceList is a list of all devices found in system
 type DeviceList map[string]*DeviceClass
 
-// DevConfigs is list of devices in devices.toml
+// DevConfigs is list of devices of the device files
 type DevConfigs map[string]*DeviceConfig
 
 // DevGroups is the map of devices to a group name
@@ -173,31 +181,103 @@ func isGroup(group string) bool {
 	return false
 }
 
-// ValidateDeviceFile is a valid TOML file for devbind
+// decodeDevices decodes the devices of a TOML device file into cfgs, the
+// devices replace the devices with the same name already in cfgs.
+func decodeDevices(name string, decode func(v interface{}) error, cfgs DevConfigs) error {
+
+	devs := make(DevConfigs)
+	if err := decode(&devs); err != nil {
+		return fmt.Errorf("device file %s: %w", name, err)
+	}
+
+	for k, v := range devs {
+		if !isGroup(v.Group) {
+			return fmt.Errorf("device file %s: device %s group (%s) is not valid %v",
+				name, k, v.Group, ValidGroups)
+		}
+		cfgs[k] = v
+	}
+	return nil
+}
+
+// decodeDeviceFile decodes the devices of the TOML device file into cfgs
+func decodeDeviceFile(file string, cfgs DevConfigs) error {
+
+	return decodeDevices(file, func(v interface{}) error {
+		_, err := toml.DecodeFile(file, v)
+		return err
+	}, cfgs)
+}
+
+// deviceGroups creates the device class grouping from the device list, the
+// devices of a group are in device name order.
+func deviceGroups(cfgs DevConfigs) DevGroups {
+
+	names := make([]string, 0, len(cfgs))
+	for k := range cfgs {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	grps := make(DevGroups)
+	for _, k := range names {
+		v := cfgs[k]
+		grps[v.Group] = append(grps[v.Group], v)
+	}
+	tlog.DebugPrintf("Groups:\n%s\n", spew.Sdump(grps))
+
+	return grps
+}
+
+// DefaultDevices returns the devices of the default device database
+func DefaultDevices() (DevConfigs, DevGroups, error) {
+
+	cfgs := make(DevConfigs)
+
+	err := decodeDevices("default", func(v interface{}) error {
+		_, err := toml.Decode(defaultDeviceFile, v)
+		return err
+	}, cfgs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfgs, deviceGroups(cfgs), nil
+}
+
+// ValidateDeviceFile is a valid TOML file for devbind, the devices of the
+// file are returned without the default devices.
 func ValidateDeviceFile(file string) (DevConfigs, DevGroups, error) {
 
 	cfgs := make(DevConfigs)
-	grps := make(DevGroups)
 
-	if _, err := toml.DecodeFile(file, &cfgs); err != nil {
+	if err := decodeDeviceFile(file, cfgs); err != nil {
 		return nil, nil, err
 	}
+	return cfgs, deviceGroups(cfgs), nil
+}
 
-	// Create the device class grouping from the device list
-	for _, v := range cfgs {
-		grps[v.Group] = append(grps[v.Group], v)
-		if !isGroup(v.Group) {
-			return nil, nil, fmt.Errorf("group (%s) is not valid %v",
-				v.Group, ValidGroups)
-		}
+// LoadDeviceFiles returns the default devices with the devices of each file
+// layered on top in the given order.
+func LoadDeviceFiles(files ...string) (DevConfigs, DevGroups, error) {
+
+	cfgs, _, err := DefaultDevices()
+	if err != nil {
+		return nil, nil, err
 	}
-	tlog.DebugPrintf("Groups:\n%s\n", spew.Sdump(grps))
 
-	return cfgs, grps, nil
+	for _, file := range files {
+		tlog.InfoPrintf("Process TOML device file (%s)\n", file)
+
+		if err := decodeDeviceFile(file, cfgs); err != nil {
+			return nil, nil, err
+		}
+	}
+	return cfgs, deviceGroups(cfgs), nil
 }
 
-// LoadDeviceFile and create the config and groups
-func LoadDeviceFile(files string) (DevConfigs, DevGroups, error) {
+// LoadDeviceFile and create the config and groups, the devices of the file
+// are layered on top of the default devices.
+func LoadDeviceFile(file string) (DevConfigs, DevGroups, error) {
 
-	return ValidateDeviceFile(files)
+	return LoadDeviceFiles(file)
 }
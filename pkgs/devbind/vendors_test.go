@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2019-2020 Intel Corporation
+
+package devbind
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultDevices(t *testing.T) {
+
+	cfgs, grps, err := DefaultDevices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfgs) == 0 {
+		t.Fatal("no default devices")
+	}
+	for _, g := range ValidGroups {
+		if len(grps[g]) == 0 {
+			t.Errorf("no default devices in group %s", g)
+		}
+	}
+}
+
+// writeDeviceFile writes a device file into a temporary directory
+func writeDeviceFile(t *testing.T, name, text string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoadDeviceFiles(t *testing.T) {
+
+	defaults, defaultGrps, err := DefaultDevices()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file1 := writeDeviceFile(t, "site.toml", `
+[Network]
+  group = "NetworkGroup"
+  desc = "Intel NICs only"
+  vendor_id = "8086"
+  [Network.class]
+    devclass = "02"
+
+[MyDMA]
+  group = "DMAGroup"
+  desc = "My DMA engine"
+  vendor_id = "1234"
+  device_id = "0001"
+`)
+	file2 := writeDeviceFile(t, "host.toml", `
+[MyDMA]
+  group = "DMAGroup"
+  desc = "My DMA engine v2"
+  vendor_id = "1234"
+  device_id = "0002"
+`)
+
+	cfgs, grps, err := LoadDeviceFiles(file1, file2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfgs) != len(defaults)+1 {
+		t.Errorf("got %d devices, want %d", len(cfgs), len(defaults)+1)
+	}
+	if cfgs["Network"].Vendor != "8086" {
+		t.Errorf("Network not replaced: %+v", cfgs["Network"])
+	}
+	if cfgs["MyDMA"].Device != "0002" {
+		t.Errorf("MyDMA not replaced by the last file: %+v", cfgs["MyDMA"])
+	}
+	if len(grps[DMAGroup]) != len(defaultGrps[DMAGroup])+1 {
+		t.Errorf("DMA group has %d devices", len(grps[DMAGroup]))
+	}
+}
+
+func TestLoadDeviceFilesErrors(t *testing.T) {
+
+	bad := writeDeviceFile(t, "bad.toml", `
+[Foo]
+  group = "FooGroup"
+`)
+	if _, _, err := LoadDeviceFiles(bad); err == nil || !strings.Contains(err.Error(), "FooGroup") {
+		t.Errorf("invalid group error %v", err)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.toml")
+	if _, err := New(missing); err == nil {
+		t.Error("New did not fail for a missing device file")
+	}
+}
@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022 Intel Corporation
+
+package ethtool
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The settings of a kernel network interface are read with the SIOCETHTOOL
+// ioctl, the same requests the ethtool command sends. A driver that does not
+// implement a request leaves that part of the Info empty.
+
+// ErrNotSupported is returned when the ethtool requests are not available
+var ErrNotSupported = errors.New("ethtool is only supported on linux")
+
+// SpeedUnknown is the speed of a link without a carrier
+const SpeedUnknown = -1
+
+// Duplex mode of a link
+type Duplex int
+
+// Duplex modes
+const (
+	DuplexHalf    Duplex = 0x00
+	DuplexFull    Duplex = 0x01
+	DuplexUnknown Duplex = 0xff
+)
+
+func (d Duplex) String() string {
+
+	switch d {
+	case DuplexHalf:
+		return "half"
+	case DuplexFull:
+		return "full"
+	}
+	return "unknown"
+}
+
+// DriverInfo is the driver and firmware of an interface
+type DriverInfo struct {
+	Driver   string // Kernel driver
+	Version  string // Version of the driver
+	Firmware string // Firmware version of the NIC
+	BusInfo  string // PCI address of the NIC
+}
+
+// Link is the link settings of an interface
+type Link struct {
+	Up      bool   // Link detected
+	Speed   int    // Speed in Mb/s or SpeedUnknown
+	Duplex  Duplex // Duplex mode
+	Autoneg bool   // Autonegotiation enabled
+}
+
+func (l Link) String() string {
+
+	state := "down"
+	if l.Up {
+		state = "up"
+	}
+	speed := "speed unknown"
+	if l.Speed != SpeedUnknown {
+		speed = fmt.Sprintf("%dMb/s", l.Speed)
+		if l.Speed >= 1000 && l.Speed%1000 == 0 {
+			speed = fmt.Sprintf("%dGb/s", l.Speed/1000)
+		}
+	}
+	autoneg := "off"
+	if l.Autoneg {
+		autoneg = "on"
+	}
+	return fmt.Sprintf("%s %s, %s duplex, autoneg %s", state, speed, l.Duplex, autoneg)
+}
+
+// Rings is the current and maximum descriptor ring sizes
+type Rings struct {
+	Rx, RxMax uint32
+	Tx, TxMax uint32
+}
+
+// Channels is the current and maximum number of queues of each type
+type Channels struct {
+	Rx, RxMax             uint32
+	Tx, TxMax             uint32
+	Other, OtherMax       uint32
+	Combined, CombinedMax uint32
+}
+
+// Feature is an offload or other feature of an interface
+type Feature struct {
+	Name   string
+	Active bool // The feature is on
+	Fixed  bool // The feature can not be changed
+}
+
+// Info is the ethtool settings of an interface, a part the driver does not
+// support is nil or empty.
+type Info struct {
+	Interface string
+	Driver    *DriverInfo
+	Link      *Link
+	Rings     *Rings
+	Channels  *Channels
+	Features  []Feature
+}
+
+// Get returns the settings of the interface, an error is only returned if
+// none of the settings can be read.
+func Get(iface string) (*Info, error) {
+
+	info := &Info{Interface: iface}
+
+	var errs []string
+	fail := func(what string, err error) {
+		errs = append(errs, fmt.Sprintf("%s: %v", what, err))
+	}
+
+	if d, err := DriverInfoOf(iface); err == nil {
+		info.Driver = &d
+	} else {
+		fail("driver", err)
+	}
+	if l, err := LinkOf(iface); err == nil {
+		info.Link = &l
+	} else {
+		fail("link", err)
+	}
+	if r, err := RingsOf(iface); err == nil {
+		info.Rings = &r
+	} else {
+		fail("rings", err)
+	}
+	if c, err := ChannelsOf(iface); err == nil {
+		info.Channels = &c
+	} else {
+		fail("channels", err)
+	}
+	if f, err := FeaturesOf(iface); err == nil {
+		info.Features = f
+	} else {
+		fail("features", err)
+	}
+
+	if len(errs) == 5 {
+		return nil, fmt.Errorf("%s: %s", iface, strings.Join(errs, ", "))
+	}
+	return info, nil
+}
+
+// Offloads returns the names of the active features and of the features
+// that are off but can be turned on, sorted by name.
+func (info *Info) Offloads() (on, off []string) {
+
+	for _, f := range info.Features {
+		switch {
+		case f.Active:
+			on = append(on, f.Name)
+		case !f.Fixed:
+			off = append(off, f.Name)
+		}
+	}
+	sort.Strings(on)
+	sort.Strings(off)
+
+	return on, off
+}
+
+// featureBlock is the state of 32 features, bit i of each word is the
+// state of feature i of the block
+type featureBlock struct {
+	Available    uint32 // Features that can be changed
+	Requested    uint32 // Features requested by the user
+	Active       uint32 // Features that are on
+	NeverChanged uint32 // Features that are fixed by the driver or kernel
+}
+
+// features returns the named features with the state of the feature blocks
+func features(names []string, blocks []featureBlock) []Feature {
+
+	f := make([]Feature, 0, len(names))
+	for i, name := range names {
+		if len(name) == 0 || i/32 >= len(blocks) {
+			continue
+		}
+		b := blocks[i/32]
+		bit := uint32(1) << (i % 32)
+		f = append(f, Feature{
+			Name:   name,
+			Active: b.Active&bit != 0,
+			Fixed:  b.Available&bit == 0 || b.NeverChanged&bit != 0,
+		})
+	}
+	return f
+}
+
+// cString returns the string of a NUL terminated byte array
+func cString(b []byte) string {
+
+	if i := strings.IndexByte(string(b), 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
@@ -0,0 +1,261 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022 Intel Corporation
+
+package ethtool
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// Requests and sizes of linux/ethtool.h and linux/sockios.h
+const (
+	siocEthtool = 0x8946
+
+	ethtoolGSet       = 0x00000001
+	ethtoolGDrvInfo   = 0x00000003
+	ethtoolGLink      = 0x0000000a
+	ethtoolGRingParam = 0x00000010
+	ethtoolGStrings   = 0x0000001b
+	ethtoolGSSetInfo  = 0x00000037
+	ethtoolGFeatures  = 0x0000003a
+	ethtoolGChannels  = 0x0000003c
+
+	ethSSFeatures  = 4  // String set of the feature names
+	ethGStringLen  = 32 // Length of a name in a string set
+	ifNameSize     = 16
+	speedUnknownHi = 0xffff
+)
+
+// ifreq is struct ifreq with the ifr_data member of the union
+type ifreq struct {
+	name [ifNameSize]byte
+	data unsafe.Pointer
+	_    [24 - unsafe.Sizeof(uintptr(0))]byte
+}
+
+// ethtoolDrvInfo is struct ethtool_drvinfo
+type ethtoolDrvInfo struct {
+	cmd         uint32
+	driver      [32]byte
+	version     [32]byte
+	fwVersion   [32]byte
+	busInfo     [32]byte
+	eromVersion [32]byte
+	reserved2   [12]byte
+	nPrivFlags  uint32
+	nStats      uint32
+	testInfoLen uint32
+	eedumpLen   uint32
+	regdumpLen  uint32
+}
+
+// ethtoolCmd is struct ethtool_cmd of the legacy link settings request
+type ethtoolCmd struct {
+	cmd           uint32
+	supported     uint32
+	advertising   uint32
+	speed         uint16
+	duplex        uint8
+	port          uint8
+	phyAddress    uint8
+	transceiver   uint8
+	autoneg       uint8
+	mdioSupport   uint8
+	maxTxPkt      uint32
+	maxRxPkt      uint32
+	speedHi       uint16
+	ethTpMdix     uint8
+	ethTpMdixCtrl uint8
+	lpAdvertising uint32
+	reserved      [2]uint32
+}
+
+// ethtoolValue is struct ethtool_value
+type ethtoolValue struct {
+	cmd  uint32
+	data uint32
+}
+
+// ethtoolRingParam is struct ethtool_ringparam
+type ethtoolRingParam struct {
+	cmd            uint32
+	rxMaxPending   uint32
+	rxMiniMax      uint32
+	rxJumboMax     uint32
+	txMaxPending   uint32
+	rxPending      uint32
+	rxMiniPending  uint32
+	rxJumboPending uint32
+	txPending      uint32
+}
+
+// ethtoolChannels is struct ethtool_channels
+type ethtoolChannels struct {
+	cmd           uint32
+	maxRx         uint32
+	maxTx         uint32
+	maxOther      uint32
+	maxCombined   uint32
+	rxCount       uint32
+	txCount       uint32
+	otherCount    uint32
+	combinedCount uint32
+}
+
+// ethtoolSSetInfo is struct ethtool_sset_info with room for one set
+type ethtoolSSetInfo struct {
+	cmd      uint32
+	reserved uint32
+	mask     uint64
+	data     uint32
+}
+
+// ioctl sends the ethtool request in data to the interface
+func ioctl(iface string, data unsafe.Pointer) error {
+
+	if len(iface) == 0 || len(iface) >= ifNameSize {
+		return fmt.Errorf("invalid interface name %q", iface)
+	}
+
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM, 0)
+	if err != nil {
+		return fmt.Errorf("socket: %w", err)
+	}
+	defer syscall.Close(fd)
+
+	ifr := ifreq{data: data}
+	copy(ifr.name[:], iface)
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), siocEthtool, uintptr(unsafe.Pointer(&ifr)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// DriverInfoOf returns the driver and firmware of the interface
+func DriverInfoOf(iface string) (DriverInfo, error) {
+
+	d := ethtoolDrvInfo{cmd: ethtoolGDrvInfo}
+	if err := ioctl(iface, unsafe.Pointer(&d)); err != nil {
+		return DriverInfo{}, err
+	}
+
+	return DriverInfo{
+		Driver:   cString(d.driver[:]),
+		Version:  cString(d.version[:]),
+		Firmware: cString(d.fwVersion[:]),
+		BusInfo:  cString(d.busInfo[:]),
+	}, nil
+}
+
+// LinkOf returns the link state, speed, duplex and autonegotiation of the
+// interface
+func LinkOf(iface string) (Link, error) {
+
+	c := ethtoolCmd{cmd: ethtoolGSet}
+	if err := ioctl(iface, unsafe.Pointer(&c)); err != nil {
+		return Link{}, err
+	}
+
+	l := Link{
+		Speed:   int(c.speedHi)<<16 | int(c.speed),
+		Duplex:  Duplex(c.duplex),
+		Autoneg: c.autoneg != 0,
+	}
+	if c.speedHi == speedUnknownHi || l.Speed == 0 {
+		l.Speed = SpeedUnknown
+	}
+	if l.Duplex != DuplexHalf && l.Duplex != DuplexFull {
+		l.Duplex = DuplexUnknown
+	}
+
+	v := ethtoolValue{cmd: ethtoolGLink}
+	if err := ioctl(iface, unsafe.Pointer(&v)); err == nil {
+		l.Up = v.data != 0
+	}
+
+	return l, nil
+}
+
+// RingsOf returns the RX and TX descriptor ring sizes of the interface
+func RingsOf(iface string) (Rings, error) {
+
+	r := ethtoolRingParam{cmd: ethtoolGRingParam}
+	if err := ioctl(iface, unsafe.Pointer(&r)); err != nil {
+		return Rings{}, err
+	}
+
+	return Rings{
+		Rx:    r.rxPending,
+		RxMax: r.rxMaxPending,
+		Tx:    r.txPending,
+		TxMax: r.txMaxPending,
+	}, nil
+}
+
+// ChannelsOf returns the queue counts of the interface
+func ChannelsOf(iface string) (Channels, error) {
+
+	c := ethtoolChannels{cmd: ethtoolGChannels}
+	if err := ioctl(iface, unsafe.Pointer(&c)); err != nil {
+		return Channels{}, err
+	}
+
+	return Channels{
+		Rx:          c.rxCount,
+		RxMax:       c.maxRx,
+		Tx:          c.txCount,
+		TxMax:       c.maxTx,
+		Other:       c.otherCount,
+		OtherMax:    c.maxOther,
+		Combined:    c.combinedCount,
+		CombinedMax: c.maxCombined,
+	}, nil
+}
+
+// FeaturesOf returns the offloads and other features of the interface
+func FeaturesOf(iface string) ([]Feature, error) {
+
+	// The number of feature names
+	info := ethtoolSSetInfo{cmd: ethtoolGSSetInfo, mask: 1 << ethSSFeatures}
+	if err := ioctl(iface, unsafe.Pointer(&info)); err != nil {
+		return nil, err
+	}
+	if info.mask == 0 || info.data == 0 {
+		return nil, nil
+	}
+	count := int(info.data)
+
+	// struct ethtool_gstrings followed by the names
+	strs := make([]byte, 12+count*ethGStringLen)
+	*(*uint32)(unsafe.Pointer(&strs[0])) = ethtoolGStrings
+	*(*uint32)(unsafe.Pointer(&strs[4])) = ethSSFeatures
+	*(*uint32)(unsafe.Pointer(&strs[8])) = uint32(count)
+	if err := ioctl(iface, unsafe.Pointer(&strs[0])); err != nil {
+		return nil, fmt.Errorf("feature names: %w", err)
+	}
+	names := make([]string, count)
+	for i := range names {
+		off := 12 + i*ethGStringLen
+		names[i] = cString(strs[off : off+ethGStringLen])
+	}
+
+	// struct ethtool_gfeatures followed by the feature blocks
+	n := (count + 31) / 32
+	size := int(unsafe.Sizeof(featureBlock{}))
+	feats := make([]byte, 8+n*size)
+	*(*uint32)(unsafe.Pointer(&feats[0])) = ethtoolGFeatures
+	*(*uint32)(unsafe.Pointer(&feats[4])) = uint32(n)
+	if err := ioctl(iface, unsafe.Pointer(&feats[0])); err != nil {
+		return nil, fmt.Errorf("feature states: %w", err)
+	}
+	blocks := make([]featureBlock, n)
+	for i := range blocks {
+		blocks[i] = *(*featureBlock)(unsafe.Pointer(&feats[8+i*size]))
+	}
+
+	return features(names, blocks), nil
+}
@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022 Intel Corporation
+
+//go:build !linux
+
+package ethtool
+
+// DriverInfoOf is only supported on Linux
+func DriverInfoOf(iface string) (DriverInfo, error) {
+	return DriverInfo{}, ErrNotSupported
+}
+
+// LinkOf is only supported on Linux
+func LinkOf(iface string) (Link, error) {
+	return Link{}, ErrNotSupported
+}
+
+// RingsOf is only supported on Linux
+func RingsOf(iface string) (Rings, error) {
+	return Rings{}, ErrNotSupported
+}
+
+// ChannelsOf is only supported on Linux
+func ChannelsOf(iface string) (Channels, error) {
+	return Channels{}, ErrNotSupported
+}
+
+// FeaturesOf is only supported on Linux
+func FeaturesOf(iface string) ([]Feature, error) {
+	return nil, ErrNotSupported
+}
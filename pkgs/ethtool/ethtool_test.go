@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022 Intel Corporation
+
+package ethtool
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLinkString(t *testing.T) {
+
+	tests := []struct {
+		link Link
+		want string
+	}{
+		{Link{Up: true, Speed: 25000, Duplex: DuplexFull, Autoneg: true}, "up 25Gb/s, full duplex, autoneg on"},
+		{Link{Up: true, Speed: 100, Duplex: DuplexHalf}, "up 100Mb/s, half duplex, autoneg off"},
+		{Link{Speed: SpeedUnknown, Duplex: DuplexUnknown}, "down speed unknown, unknown duplex, autoneg off"},
+	}
+	for _, tt := range tests {
+		if got := tt.link.String(); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestFeatures(t *testing.T) {
+
+	names := make([]string, 34)
+	names[0] = "rx-checksum"
+	names[1] = "tx-tcp-segmentation"
+	names[2] = "rx-lro"
+	names[33] = "rx-gro"
+
+	blocks := []featureBlock{
+		{Available: 0x6, Active: 0x3},
+		{Available: 0x2, Active: 0x2},
+	}
+	want := []Feature{
+		{Name: "rx-checksum", Active: true, Fixed: true},
+		{Name: "tx-tcp-segmentation", Active: true},
+		{Name: "rx-lro"},
+		{Name: "rx-gro", Active: true},
+	}
+	f := features(names, blocks)
+	if !reflect.DeepEqual(f, want) {
+		t.Fatalf("got %+v, want %+v", f, want)
+	}
+
+	// A feature the kernel never changed is fixed
+	blocks[0].NeverChanged = 0x4
+	info := &Info{Features: features(names, blocks)}
+	on, off := info.Offloads()
+	if !reflect.DeepEqual(on, []string{"rx-checksum", "rx-gro", "tx-tcp-segmentation"}) || len(off) != 0 {
+		t.Errorf("offloads got %v off %v", on, off)
+	}
+}
+
+func TestCString(t *testing.T) {
+
+	if s := cString([]byte{'i', 'c', 'e', 0, 'x'}); s != "ice" {
+		t.Errorf("got %q", s)
+	}
+	if s := cString([]byte("virtio")); s != "virtio" {
+		t.Errorf("unterminated got %q", s)
+	}
+}
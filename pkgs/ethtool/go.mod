@@ -0,0 +1,3 @@
+module github.com/KeithWiles/go-pktgen/pkgs/ethtool
+
+go 1.18
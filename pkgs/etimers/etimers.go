@@ -4,7 +4,7 @@
 package etimers
 
 import (
-	"strings"
+	"context"
 	"sync"
 	"time"
 
@@ -14,6 +14,12 @@ import (
 // etimers is a package to handle timers for the performance monitor tool.
 // A number of timers needed to be handled in a consistent way and from a single
 // go routine.
+//
+// Actions added with Add are called on every tick of the timers with a step
+// counter, actions added with AddPeriodic, AddOneShot or AddDeadline are
+// called at their own times. All actions are called from the timer go routine
+// without holding any lock, an action can add or remove actions. Adding and
+// removing actions never waits on the timer go routine.
 
 // EventTimers to process when timer expires
 type EventTimers struct {
@@ -21,22 +27,31 @@ type EventTimers struct {
 	timo     time.Duration
 	maxSteps int
 	step     int
-	list     map[string]*EventAction
-	action   chan *EventAction
-	ticker   *time.Ticker
 	ticks    uint64
+	nextTick time.Time
+	list     map[string]*EventAction
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wake     chan struct{}
+	done     chan struct{}
+	started  bool
 }
 
 // EventAction information
 type EventAction struct {
-	Name    string
-	Action  string
-	Routine func(step int, ticks uint64)
+	Name      string
+	Period    time.Duration // Time between calls, zero when called only once
+	Next      time.Time     // Time of the next call, unused for tick actions
+	tick      func(step int, ticks uint64)
+	routine   func()
+	cancelled bool
+	fired     bool // A one-shot action waiting to be called
 }
 
 // New for calling tview events
-// Takes New(timo time.duration, steps int)
-// defaults to time.Second and 0 steps
+// Takes New(timo time.duration, steps int, ctx context.Context)
+// defaults to time.Second, 0 steps and context.Background(), canceling the
+// context stops the timers.
 func New(arg ...interface{}) *EventTimers {
 	te := &EventTimers{}
 
@@ -46,19 +61,23 @@ func New(arg ...interface{}) *EventTimers {
 	te.maxSteps = 0
 	te.timo = time.Second
 
+	ctx := context.Background()
+
 	// Loop in the arg(s) to create any supplied events.
 	for _, a := range arg {
-		switch a.(type) {
+		switch v := a.(type) {
 		case time.Duration:
-			te.timo = a.(time.Duration)
+			te.timo = v
 		case int:
-			te.maxSteps = a.(int)
+			te.maxSteps = v
+		case context.Context:
+			ctx = v
 		}
 	}
 
-	// Create the channel and ticker instances
-	te.action = make(chan *EventAction, 16)
-	te.ticker = time.NewTicker(te.timo)
+	te.ctx, te.cancel = context.WithCancel(ctx)
+	te.wake = make(chan struct{}, 1)
+	te.done = make(chan struct{})
 
 	tlog.DebugPrintf("New etimers: %+v\n", te)
 
@@ -68,118 +87,231 @@ func New(arg ...interface{}) *EventTimers {
 // Start to handle timeouts with tview
 func (te *EventTimers) Start() {
 
+	te.lock.Lock()
+	defer te.lock.Unlock()
+
+	if te.started {
+		return
+	}
+	te.started = true
+	te.nextTick = time.Now().Add(te.timo)
+
 	// Create a go routine that handles all of the timer events
-	go func() {
+	go te.run()
+}
 
-		// Loop forever until a quit message is received over the channel
-	ForLoop:
-		for {
-			select {
-			case event := <-te.action:
-				tlog.DebugPrintf("EventAction: %s --> %s\n", event.Name, event.Action)
+// Done returns a channel closed when the timer go routine created by Start
+// has stopped
+func (te *EventTimers) Done() <-chan struct{} {
+	return te.done
+}
 
-				// When a timer expires then execute the actions attached to the events.
-				te.doAction(event)
+// run the timer go routine until the context is canceled
+func (te *EventTimers) run() {
 
-				if strings.ToLower(event.Action) == "quit" {
-					break ForLoop
-				}
+	defer close(te.done)
 
-			// Process the timer ticks and call the timeout routine handler.
-			case <-te.ticker.C:
-				te.doTimeout()
+	for {
+		te.lock.Lock()
+		next := te.nextTick
+		for _, a := range te.list {
+			if a.tick == nil && !a.fired && a.Next.Before(next) {
+				next = a.Next
 			}
 		}
-	}()
+		te.lock.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-te.ctx.Done():
+			timer.Stop()
+			tlog.DebugPrintf("EventTimers stopped: %v\n", te.ctx.Err())
+			return
+
+		// An action was added or removed, the next timeout may have changed
+		case <-te.wake:
+
+		case <-timer.C:
+		}
+		timer.Stop()
+
+		te.dispatch(time.Now())
+	}
 }
 
-func (te *EventTimers) doTimeout() {
+// dispatch calls the actions due at the given time
+func (te *EventTimers) dispatch(now time.Time) {
+
+	var due []*EventAction
+	var step int
+	var ticks uint64
 
-	// Lock the timer while processing a timeout event
 	te.lock.Lock()
-	defer te.lock.Unlock()
 
-	// Bump the step counter, which is passed to the action routines as a time
-	// reference like value normally 0-4 or 0-8 steps. Each step is 1/4 or 1/8
-	// of a second.
-	te.step++
-	if te.step >= te.maxSteps {
-		te.step = 0
+	tick := !now.Before(te.nextTick)
+	if tick {
+		// Bump the step counter, which is passed to the action routines as a time
+		// reference like value normally 0-4 or 0-8 steps. Each step is 1/4 or 1/8
+		// of a second.
+		te.step++
+		if te.step >= te.maxSteps {
+			te.step = 0
+		}
+		step, ticks = te.step, te.ticks
+
+		// bump the ticks processed as a type of tick counter.
+		te.ticks++
+
+		// Skip the ticks missed by a slow action instead of bunching them up
+		te.nextTick = te.nextTick.Add(te.timo)
+		if te.nextTick.Before(now) {
+			te.nextTick = now.Add(te.timo)
+		}
 	}
 
-	// Call all of the actions when for this timer event
 	for _, a := range te.list {
-		tlog.DebugPrintf("Call Action: %s\n", a.Name)
-		a.Routine(te.step, te.ticks)
+		if a.tick != nil {
+			if tick {
+				due = append(due, a)
+			}
+			continue
+		}
+		if a.fired || now.Before(a.Next) {
+			continue
+		}
+		due = append(due, a)
+
+		if a.Period > 0 {
+			a.Next = a.Next.Add(a.Period)
+			if a.Next.Before(now) {
+				a.Next = now.Add(a.Period)
+			}
+		} else {
+			// Stays in the list until called so Remove can still cancel it
+			a.fired = true
+		}
 	}
 
-	// bump the ticks processed as a type of tick counter.
-	te.ticks++
+	te.lock.Unlock()
+
+	// Call the actions without the lock, letting them add and remove actions
+	for _, a := range due {
+		if te.isCancelled(a) {
+			continue
+		}
+		if a.tick != nil {
+			a.tick(step, ticks)
+		} else {
+			a.routine()
+		}
+		if a.fired {
+			te.removeFired(a)
+		}
+	}
 }
 
-// Process an action when a timer event happens
-func (te *EventTimers) doAction(a *EventAction) {
+// removeFired removes a one-shot action after it was called
+func (te *EventTimers) removeFired(a *EventAction) {
+
+	te.lock.Lock()
+	defer te.lock.Unlock()
+
+	if te.list[a.Name] == a {
+		delete(te.list, a.Name)
+	}
+}
 
-	// Covert the action to a string for the switch below
-	action := strings.ToLower(a.Action)
+// isCancelled returns true if the action was removed or replaced
+func (te *EventTimers) isCancelled(a *EventAction) bool {
 
 	te.lock.Lock()
 	defer te.lock.Unlock()
 
-	// Handle the action for a given event
-	switch action {
-	case "add":
-		// Add a new action to the list atomically
-		tlog.DebugPrintf("Add Action: %s\n", a.Name)
-		te.list[a.Name] = a
-
-	case "remove":
-		// Remove an action atomically
-		tlog.DebugPrintf("Remove Action: %s\n", a.Name)
-		if _, ok := te.list[a.Name]; ok {
-			tlog.DebugPrintf("Removed: %s\n", a.Name)
-			delete(te.list, a.Name)
-		}
+	return a.cancelled
+}
+
+// add an action to the list, replacing an action with the same name
+func (te *EventTimers) add(a *EventAction) {
+
+	te.lock.Lock()
+	if old, ok := te.list[a.Name]; ok {
+		old.cancelled = true
+	}
+	tlog.DebugPrintf("Add Action: %s\n", a.Name)
+	te.list[a.Name] = a
+	te.lock.Unlock()
+
+	te.wakeup()
+}
+
+// wakeup the timer go routine to compute the next timeout, never blocks
+func (te *EventTimers) wakeup() {
 
-	case "quit":
-		// Quit the event timer go routine
-		close(te.action)
-		te.ticker.Stop()
+	select {
+	case te.wake <- struct{}{}:
+	default:
 	}
 }
 
-// Add to the list of timers
+// Add to the list of timers, the routine is called on every tick with the
+// step and tick counters.
 func (te *EventTimers) Add(name string, f func(step int, ticks uint64)) {
 
-	te.lock.Lock()
-	defer te.lock.Unlock()
+	te.add(&EventAction{Name: name, Period: te.timo, tick: f})
+}
+
+// AddPeriodic adds a routine called every period, the first call is one
+// period from now. The count is the number of previous calls.
+func (te *EventTimers) AddPeriodic(name string, period time.Duration, f func(count uint64)) {
 
-	// Add a timer event by passing it to the timer go routine
-	ea := &EventAction{Name: name, Action: "Add", Routine: f}
+	if period <= 0 {
+		period = te.timo
+	}
 
-	te.action <- ea
+	var count uint64
+	te.add(&EventAction{
+		Name:   name,
+		Period: period,
+		Next:   time.Now().Add(period),
+		routine: func() {
+			f(count)
+			count++
+		},
+	})
 }
 
-// Remove to the list of timers
-func (te *EventTimers) Remove(name string) {
+// AddOneShot adds a routine called once after the delay
+func (te *EventTimers) AddOneShot(name string, delay time.Duration, f func()) {
 
-	te.lock.Lock()
-	defer te.lock.Unlock()
+	te.AddDeadline(name, time.Now().Add(delay), f)
+}
 
-	// Remove the timer event action by sending it to the go routine
-	ea := &EventAction{Name: name, Action: "Remove"}
+// AddDeadline adds a routine called once at the deadline, a deadline in the
+// past calls the routine on the next dispatch.
+func (te *EventTimers) AddDeadline(name string, deadline time.Time, f func()) {
 
-	te.action <- ea
+	te.add(&EventAction{Name: name, Next: deadline, routine: f})
 }
 
-// Stop the timers
-func (te *EventTimers) Stop() {
+// Remove to the list of timers, the routine is not called after Remove
+// returns unless it is running.
+func (te *EventTimers) Remove(name string) {
 
 	te.lock.Lock()
-	defer te.lock.Unlock()
+	if a, ok := te.list[name]; ok {
+		tlog.DebugPrintf("Removed: %s\n", name)
+		a.cancelled = true
+		delete(te.list, name)
+	}
+	te.lock.Unlock()
+
+	te.wakeup()
+}
 
-	// Force the timer routine to quit
-	ea := &EventAction{Action: "quit"}
+// Stop the timers, Done is closed once the timer go routine has stopped.
+// Stop does not wait and can be called more than once.
+func (te *EventTimers) Stop() {
 
-	te.action <- ea
+	te.cancel()
 }
@@ -4,7 +4,11 @@
 package etimers
 
 import (
+	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"testing"
 )
@@ -17,3 +21,166 @@ func TestClose(t *testing.T) {
 	fmt.Printf("Close eTimers\n")
 
 }
+
+// waitFor polls the condition until it is true or the timeout expires
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+
+	for end := time.Now().Add(timeout); time.Now().Before(end); {
+		if cond() {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("condition not met after %v", timeout)
+}
+
+func TestTickSteps(t *testing.T) {
+	te := New(time.Millisecond, 4)
+	te.Start()
+	defer te.Stop()
+
+	var lock sync.Mutex
+	var steps []int
+	var ticks []uint64
+	te.Add("steps", func(step int, tick uint64) {
+		lock.Lock()
+		defer lock.Unlock()
+		steps = append(steps, step)
+		ticks = append(ticks, tick)
+	})
+
+	waitFor(t, time.Second, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(steps) >= 8
+	})
+	te.Remove("steps")
+
+	lock.Lock()
+	defer lock.Unlock()
+	for i := 1; i < len(steps); i++ {
+		if steps[i] != (steps[i-1]+1)%4 {
+			t.Errorf("step %d follows step %d", steps[i], steps[i-1])
+		}
+		if ticks[i] != ticks[i-1]+1 {
+			t.Errorf("tick %d follows tick %d", ticks[i], ticks[i-1])
+		}
+	}
+}
+
+func TestPeriodic(t *testing.T) {
+	te := New(time.Hour)
+	te.Start()
+	defer te.Stop()
+
+	var fast, slow int32
+	te.AddPeriodic("fast", 2*time.Millisecond, func(count uint64) {
+		atomic.AddInt32(&fast, 1)
+	})
+	te.AddPeriodic("slow", 40*time.Millisecond, func(count uint64) {
+		atomic.AddInt32(&slow, 1)
+	})
+
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt32(&slow) >= 2 })
+
+	if f, s := atomic.LoadInt32(&fast), atomic.LoadInt32(&slow); f <= s*4 {
+		t.Errorf("fast action called %d times, slow action %d times", f, s)
+	}
+}
+
+func TestOneShotAndDeadline(t *testing.T) {
+	te := New(time.Hour)
+	te.Start()
+	defer te.Stop()
+
+	var oneShot, deadline, removed int32
+	te.AddOneShot("oneShot", 5*time.Millisecond, func() { atomic.AddInt32(&oneShot, 1) })
+	te.AddDeadline("deadline", time.Now().Add(10*time.Millisecond), func() { atomic.AddInt32(&deadline, 1) })
+	te.AddOneShot("removed", 10*time.Millisecond, func() { atomic.AddInt32(&removed, 1) })
+	te.Remove("removed")
+
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt32(&deadline) == 1 })
+	time.Sleep(30 * time.Millisecond)
+
+	if n := atomic.LoadInt32(&oneShot); n != 1 {
+		t.Errorf("one-shot action called %d times", n)
+	}
+	if n := atomic.LoadInt32(&deadline); n != 1 {
+		t.Errorf("deadline action called %d times", n)
+	}
+	if n := atomic.LoadInt32(&removed); n != 0 {
+		t.Errorf("removed action called %d times", n)
+	}
+}
+
+func TestContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	te := New(time.Millisecond, ctx)
+	te.Start()
+
+	cancel()
+	select {
+	case <-te.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("timers not stopped by the context")
+	}
+
+	// Stopping again and adding after the stop must not block
+	te.Stop()
+	te.Add("late", func(step int, ticks uint64) {})
+	te.Remove("late")
+}
+
+func TestConcurrentAddRemove(t *testing.T) {
+	te := New(100*time.Microsecond, 4)
+	te.Start()
+
+	var calls int64
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				name := fmt.Sprintf("action-%d-%d", g, i%8)
+				switch i % 3 {
+				case 0:
+					te.Add(name, func(step int, ticks uint64) { atomic.AddInt64(&calls, 1) })
+				case 1:
+					te.AddPeriodic(name, 50*time.Microsecond, func(count uint64) { atomic.AddInt64(&calls, 1) })
+				default:
+					te.Remove(name)
+				}
+			}
+		}(g)
+	}
+
+	// Actions adding and removing actions from the timer go routine
+	te.Add("reentrant", func(step int, ticks uint64) {
+		te.AddOneShot("child", 0, func() { te.Remove("child") })
+		te.Remove("missing")
+	})
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("add/remove blocked")
+	}
+
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&calls) > 0 })
+
+	te.Stop()
+	select {
+	case <-te.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("timers not stopped")
+	}
+}
@@ -4,6 +4,8 @@
 package graphdata
 
 import (
+	"time"
+
 	"github.com/KeithWiles/go-pktgen/pkgs/asciichart"
 	"github.com/rivo/tview"
 )
@@ -11,12 +13,25 @@ import (
 // GraphPoints used to build the graph
 type GraphPoints []float64
 
-// GraphData contains the points and name of graph
+// Reduce selects the value of a downsampled bucket plotted in the graph
+type Reduce int
+
+// Reduce values
+const (
+	ReduceAvg Reduce = iota
+	ReduceMin
+	ReduceMax
+)
+
+// GraphData contains the samples and name of graph
 type GraphData struct {
 	index     int
 	name      string
+	color     string
 	maxPoints int
-	points    GraphPoints
+	window    time.Duration
+	reduce    Reduce
+	series    *Series
 }
 
 // GraphInfo information to construct the chart
@@ -28,37 +43,115 @@ type GraphInfo struct {
 	numGraphs    int
 	fieldWidth   int
 	precision    int
+	combined     bool
+	mode         asciichart.PlotMode
+	fill         bool
 	graphs       []*GraphData
 }
 
-// Trim the data to fit the width
-func (gd *GraphData) Trim() bool {
-
-	if len(gd.points) > 0 {
-		// Make sure the number of data points is capped at a given size
-		if len(gd.points) >= gd.maxPoints {
-			gd.points = gd.points[1:gd.maxPoints]
-		}
-		return true
-	}
-	return false
-}
-
 // Index of the current graph
 func (gd *GraphData) Index() int {
 	return gd.index
 }
 
-// AddPoint to the graph list and trim the data to fit
+// AddPoint to the graph taken at the current time
 func (gd *GraphData) AddPoint(point float64) *GraphData {
 
-	gd.points = append(gd.points, point)
+	gd.series.Add(time.Now(), point)
 
-	gd.Trim()
+	return gd
+}
+
+// AddSample to the graph taken at the given time
+func (gd *GraphData) AddSample(t time.Time, point float64) *GraphData {
+
+	gd.series.Add(t, point)
+
+	return gd
+}
+
+// Series returns the samples of the graph
+func (gd *GraphData) Series() *Series {
+	return gd.series
+}
+
+// SetSeries of the graph, a series can be shared by graphs and outlive them
+func (gd *GraphData) SetSeries(series *Series) *GraphData {
+	gd.series = series
 
 	return gd
 }
 
+// SetCapacity of the graph in the number of samples kept, the newest samples
+// are kept when the capacity is reduced.
+func (gd *GraphData) SetCapacity(capacity int) *GraphData {
+
+	series := NewSeries(capacity)
+	samples := gd.series.Samples()
+	if len(samples) > series.Cap() {
+		samples = samples[len(samples)-series.Cap():]
+	}
+	for _, smp := range samples {
+		series.Add(smp.Time, smp.Value)
+	}
+	gd.series = series
+
+	return gd
+}
+
+// Window returns the time window displayed in the graph
+func (gd *GraphData) Window() time.Duration {
+	return gd.window
+}
+
+// SetWindow of time displayed in the graph, the samples of the window are
+// downsampled to the number of points of the graph. A window of zero
+// displays the newest samples fitting in the graph.
+func (gd *GraphData) SetWindow(window time.Duration) *GraphData {
+	gd.window = window
+
+	return gd
+}
+
+// SetReduce selects the value of the downsampled buckets plotted in the graph
+func (gd *GraphData) SetReduce(reduce Reduce) *GraphData {
+	gd.reduce = reduce
+
+	return gd
+}
+
+// Points returns at most maxPoints points of the graph to plot
+func (gd *GraphData) Points() GraphPoints {
+
+	if gd.window <= 0 {
+		samples := gd.series.Samples()
+		if gd.maxPoints > 0 && len(samples) > gd.maxPoints {
+			samples = samples[len(samples)-gd.maxPoints:]
+		}
+
+		points := make(GraphPoints, len(samples))
+		for i, smp := range samples {
+			points[i] = smp.Value
+		}
+		return points
+	}
+
+	buckets := Downsample(gd.series.Window(gd.window), gd.maxPoints)
+
+	points := make(GraphPoints, len(buckets))
+	for i, b := range buckets {
+		switch gd.reduce {
+		case ReduceMin:
+			points[i] = b.Min
+		case ReduceMax:
+			points[i] = b.Max
+		default:
+			points[i] = b.Avg
+		}
+	}
+	return points
+}
+
 // MaxPoints is the number of allowed points in the graph
 func (gd *GraphData) MaxPoints() int {
 	return gd.maxPoints
@@ -83,6 +176,51 @@ func (gd *GraphData) SetName(name string) *GraphData {
 	return gd
 }
 
+// Color returns the line color of the graph
+func (gd *GraphData) Color() string {
+	return gd.color
+}
+
+// SetColor of the graph line, used when the graphs are combined in one chart
+func (gd *GraphData) SetColor(color string) *GraphData {
+	gd.color = color
+
+	return gd
+}
+
+// SetCombined plots the graphs in one chart sharing the axes, each graph
+// has its own line color and the names are shown in a legend row.
+func (gi *GraphInfo) SetCombined(flag bool) *GraphInfo {
+	gi.combined = flag
+
+	return gi
+}
+
+// SetMode of the charts, asciichart.PlotLine or asciichart.PlotBraille
+func (gi *GraphInfo) SetMode(mode asciichart.PlotMode) *GraphInfo {
+	gi.mode = mode
+
+	return gi
+}
+
+// SetFill of the area below the graphs, only used by asciichart.PlotBraille
+func (gi *GraphInfo) SetFill(flag bool) *GraphInfo {
+	gi.fill = flag
+
+	return gi
+}
+
+// Points returns the number of points of a graph displayed in a view of the
+// given inner width.
+func (gi *GraphInfo) Points(width int) int {
+
+	points := width - gi.fieldWidth - 2
+	if gi.mode == asciichart.PlotBraille {
+		points *= 2
+	}
+	return points
+}
+
 // SetFieldWidth of the values
 func (gi *GraphInfo) SetFieldWidth(width int) *GraphInfo {
 	gi.fieldWidth = width
@@ -114,17 +252,17 @@ func (gi *GraphInfo) GraphPoints(g int) *GraphData {
 	return gi.graphs[g]
 }
 
-// Reset the points in the graph to no points
+// Reset the samples in the graph to no samples
 func (gd *GraphData) Reset() {
 
-	gd.points = nil
+	gd.series.Reset()
 }
 
 // NewGraphData returning a new GraphData object
 // maxPoints is the number points allowed in the graph
 func NewGraphData(maxPoints int) *GraphData {
 
-	gd := &GraphData{}
+	gd := &GraphData{series: NewSeries(DefaultCapacity)}
 
 	gd.SetMaxPoints(maxPoints)
 
@@ -191,6 +329,10 @@ func (gi *GraphInfo) MakeChart(view *tview.TextView, w ...interface{}) string {
 	// Get the inside rectangle sizes
 	_, _, wOrig, hOrig := view.GetInnerRect()
 
+	if gi.combined {
+		return gi.makeCombinedChart(chart, start, end, wOrig, hOrig)
+	}
+
 	// Calculate the height of the chart based on the number of charts and
 	// the text view size
 	height := hOrig
@@ -202,7 +344,7 @@ func (gi *GraphInfo) MakeChart(view *tview.TextView, w ...interface{}) string {
 	for i := start; i < end; i++ {
 		gd := gi.graphs[i]
 
-		maxPoints := wOrig - gi.fieldWidth - 2
+		maxPoints := gi.Points(wOrig)
 		if gd.maxPoints != maxPoints {
 			gd.maxPoints = maxPoints
 		}
@@ -217,7 +359,9 @@ func (gi *GraphInfo) MakeChart(view *tview.TextView, w ...interface{}) string {
 			SetCaptionColor(gi.captionColor).
 			SetTickColor(gi.tickColor).
 			SetPrecision(gi.precision).
-			Plot(gd.points)
+			SetMode(gi.mode).
+			SetFill(gi.fill).
+			Plot(gd.Points())
 
 		// Add a line to the multiple graph string if not the last graph
 		if (i + 1) < end {
@@ -227,3 +371,36 @@ func (gi *GraphInfo) MakeChart(view *tview.TextView, w ...interface{}) string {
 
 	return graph
 }
+
+// makeCombinedChart plots the graphs from start to end in one chart
+func (gi *GraphInfo) makeCombinedChart(chart *asciichart.Chart, start, end, width, height int) string {
+
+	var series [][]float64
+	var names, colors []string
+
+	for i := start; i < end; i++ {
+		gd := gi.graphs[i]
+
+		gd.maxPoints = gi.Points(width)
+
+		series = append(series, gd.Points())
+		names = append(names, gd.name)
+		if len(gd.color) > 0 {
+			colors = append(colors, gd.color)
+		} else {
+			colors = append(colors, gi.lineColor)
+		}
+	}
+
+	// Leave a row for the legend
+	return chart.SetHeight(height - 2).SetFieldWidth(gi.fieldWidth).
+		SetLabelColor(gi.labelColor).
+		SetLineColor(gi.lineColor).
+		SetTickColor(gi.tickColor).
+		SetPrecision(gi.precision).
+		SetMode(gi.mode).
+		SetFill(gi.fill).
+		SetSeriesColors(colors...).
+		SetLegends(names...).
+		Plot(series...)
+}
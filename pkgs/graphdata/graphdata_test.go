@@ -5,6 +5,8 @@ package graphdata
 
 import (
 	"fmt"
+	"reflect"
+	"time"
 
 	"testing"
 )
@@ -17,3 +19,122 @@ func TestClose(t *testing.T) {
 	fmt.Printf("Close Graph Data\n")
 
 }
+
+func values(samples []Sample) []float64 {
+	var v []float64
+	for _, s := range samples {
+		v = append(v, s.Value)
+	}
+	return v
+}
+
+func TestSeries(t *testing.T) {
+	base := time.Unix(1000, 0)
+
+	s := NewSeries(4)
+	if _, ok := s.Last(); ok {
+		t.Errorf("Last of an empty series returned a sample")
+	}
+	for i := 0; i < 6; i++ {
+		s.Add(base.Add(time.Duration(i)*time.Second), float64(i))
+	}
+
+	if s.Len() != 4 || s.Cap() != 4 {
+		t.Errorf("Len/Cap = %d/%d, want 4/4", s.Len(), s.Cap())
+	}
+	if got, want := values(s.Samples()), []float64{2, 3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Samples = %v, want %v", got, want)
+	}
+	if last, _ := s.Last(); last.Value != 5 || !last.Time.Equal(base.Add(5*time.Second)) {
+		t.Errorf("Last = %v", last)
+	}
+
+	s.Reset()
+	if s.Len() != 0 {
+		t.Errorf("Len after Reset = %d", s.Len())
+	}
+	s.Add(base, 7)
+	if got := values(s.Samples()); !reflect.DeepEqual(got, []float64{7}) {
+		t.Errorf("Samples after Reset = %v", got)
+	}
+}
+
+func TestSeriesWindow(t *testing.T) {
+	base := time.Unix(1000, 0)
+
+	s := NewSeries(100)
+	for i := 0; i < 60; i++ {
+		s.Add(base.Add(time.Duration(i)*time.Second), float64(i))
+	}
+
+	if n := len(s.Window(10 * time.Second)); n != 10 {
+		t.Errorf("Window(10s) has %d samples, want 10", n)
+	}
+	if n := len(s.Window(5 * time.Minute)); n != 60 {
+		t.Errorf("Window(5m) has %d samples, want 60", n)
+	}
+	if n := len(s.Window(0)); n != 60 {
+		t.Errorf("Window(0) has %d samples, want 60", n)
+	}
+
+	got := values(s.Range(base.Add(10*time.Second), base.Add(12*time.Second)))
+	if want := []float64{10, 11, 12}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Range = %v, want %v", got, want)
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	base := time.Unix(1000, 0)
+
+	var samples []Sample
+	for i, v := range []float64{1, 5, 3, 2, 8, 4} {
+		samples = append(samples, Sample{Time: base.Add(time.Duration(i) * time.Second), Value: v})
+	}
+
+	buckets := Downsample(samples, 2)
+	if len(buckets) != 2 {
+		t.Fatalf("Downsample returned %d buckets, want 2", len(buckets))
+	}
+	want := Bucket{Start: base, End: base.Add(2 * time.Second), Min: 1, Max: 5, Avg: 3, Count: 3}
+	if buckets[0] != want {
+		t.Errorf("bucket 0 = %+v, want %+v", buckets[0], want)
+	}
+	if b := buckets[1]; b.Min != 2 || b.Max != 8 || b.Avg != 14.0/3 || b.Count != 3 {
+		t.Errorf("bucket 1 = %+v", b)
+	}
+
+	if n := len(Downsample(samples, 10)); n != len(samples) {
+		t.Errorf("Downsample to more buckets than samples returned %d buckets", n)
+	}
+	if Downsample(nil, 10) != nil {
+		t.Errorf("Downsample of no samples returned buckets")
+	}
+}
+
+func TestGraphDataPoints(t *testing.T) {
+	base := time.Unix(1000, 0)
+
+	gd := NewGraphData(3)
+	for i := 0; i < 8; i++ {
+		gd.AddSample(base.Add(time.Duration(i)*time.Second), float64(i))
+	}
+
+	if got, want := gd.Points(), (GraphPoints{5, 6, 7}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Points = %v, want %v", got, want)
+	}
+
+	gd.SetWindow(6 * time.Second)
+	if got, want := gd.Points(), (GraphPoints{2.5, 4.5, 6.5}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Points of window = %v, want %v", got, want)
+	}
+
+	gd.SetReduce(ReduceMax)
+	if got, want := gd.Points(), (GraphPoints{3, 5, 7}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Points of window max = %v, want %v", got, want)
+	}
+
+	gd.SetCapacity(2)
+	if got := values(gd.Series().Samples()); !reflect.DeepEqual(got, []float64{6, 7}) {
+		t.Errorf("Samples after SetCapacity = %v", got)
+	}
+}
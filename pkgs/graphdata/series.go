@@ -0,0 +1,193 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2019-2022 Intel Corporation
+
+package graphdata
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// DefaultCapacity is the number of samples kept by a graph when no capacity
+// is given, one hour of samples taken once a second.
+const DefaultCapacity = 60 * 60
+
+// Sample is a value of a series at a point in time
+type Sample struct {
+	Time  time.Time
+	Value float64
+}
+
+// Bucket is the reduction of consecutive samples of a series
+type Bucket struct {
+	Start time.Time // Time of the first sample in the bucket
+	End   time.Time // Time of the last sample in the bucket
+	Min   float64
+	Max   float64
+	Avg   float64
+	Count int
+}
+
+// Series is a fixed capacity ring of timestamped samples, once full a new
+// sample replaces the oldest one. A series is safe for concurrent use.
+type Series struct {
+	lock     sync.RWMutex
+	capacity int
+	head     int      // Index of the oldest sample when the ring is full
+	samples  []Sample // Grows up to capacity and is reused after that
+}
+
+// NewSeries returns a series holding at most capacity samples
+func NewSeries(capacity int) *Series {
+
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+
+	return &Series{capacity: capacity}
+}
+
+// Cap returns the number of samples the series can hold
+func (s *Series) Cap() int {
+	return s.capacity
+}
+
+// Len returns the number of samples in the series
+func (s *Series) Len() int {
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.samples)
+}
+
+// Add a value taken at time t to the series, the samples are expected to be
+// added in time order.
+func (s *Series) Add(t time.Time, value float64) *Series {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.samples) < s.capacity {
+		s.samples = append(s.samples, Sample{Time: t, Value: value})
+		return s
+	}
+
+	s.samples[s.head] = Sample{Time: t, Value: value}
+	s.head = (s.head + 1) % s.capacity
+
+	return s
+}
+
+// Reset the series to no samples
+func (s *Series) Reset() {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.samples = s.samples[:0]
+	s.head = 0
+}
+
+// at returns the i'th oldest sample, the lock must be held
+func (s *Series) at(i int) Sample {
+	return s.samples[(s.head+i)%len(s.samples)]
+}
+
+// Last returns the newest sample of the series
+func (s *Series) Last() (Sample, bool) {
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if len(s.samples) == 0 {
+		return Sample{}, false
+	}
+	return s.at(len(s.samples) - 1), true
+}
+
+// Samples returns a copy of all samples, oldest first
+func (s *Series) Samples() []Sample {
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	samples := make([]Sample, len(s.samples))
+	for i := range samples {
+		samples[i] = s.at(i)
+	}
+	return samples
+}
+
+// Range returns a copy of the samples taken from one time up to and
+// including another time, oldest first.
+func (s *Series) Range(from, to time.Time) []Sample {
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var samples []Sample
+	for i := 0; i < len(s.samples); i++ {
+		smp := s.at(i)
+		if smp.Time.Before(from) {
+			continue
+		}
+		if smp.Time.After(to) {
+			break
+		}
+		samples = append(samples, smp)
+	}
+	return samples
+}
+
+// Window returns the samples taken in the last d of the series, measured from
+// the newest sample. A window of zero returns all samples.
+func (s *Series) Window(d time.Duration) []Sample {
+
+	if d <= 0 {
+		return s.Samples()
+	}
+
+	last, ok := s.Last()
+	if !ok {
+		return nil
+	}
+	return s.Range(last.Time.Add(-d).Add(1), last.Time)
+}
+
+// Downsample reduces the samples to at most n buckets of consecutive samples,
+// each bucket holds the minimum, maximum and average of its samples. With n
+// samples or less every sample is a bucket of its own.
+func Downsample(samples []Sample, n int) []Bucket {
+
+	if n <= 0 || len(samples) == 0 {
+		return nil
+	}
+	if len(samples) < n {
+		n = len(samples)
+	}
+
+	buckets := make([]Bucket, n)
+	for i := range buckets {
+		start, end := i*len(samples)/n, (i+1)*len(samples)/n
+
+		b := Bucket{
+			Start: samples[start].Time,
+			End:   samples[end-1].Time,
+			Min:   math.Inf(1),
+			Max:   math.Inf(-1),
+			Count: end - start,
+		}
+		sum := 0.0
+		for _, smp := range samples[start:end] {
+			b.Min = math.Min(b.Min, smp.Value)
+			b.Max = math.Max(b.Max, smp.Value)
+			sum += smp.Value
+		}
+		b.Avg = sum / float64(b.Count)
+
+		buckets[i] = b
+	}
+	return buckets
+}
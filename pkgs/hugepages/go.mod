@@ -0,0 +1,3 @@
+module github.com/KeithWiles/go-pktgen/pkgs/hugepages
+
+go 1.18
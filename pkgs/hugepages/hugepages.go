@@ -0,0 +1,237 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022 Intel Corporation
+
+package hugepages
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The hugepage pools are read from sysfs, the system wide pools from
+// kernel/mm/hugepages and the pools of each NUMA node from the node
+// directories. The hugetlbfs mounts are read from the proc mounts file.
+
+const (
+	// DefaultSysfsRoot is the root of the sysfs tree
+	DefaultSysfsRoot = "/sys"
+	// DefaultProcRoot is the root of the proc tree
+	DefaultProcRoot = "/proc"
+
+	// AllNodes is the node of the system wide pools
+	AllNodes = -1
+)
+
+// Pool is a hugepage pool of a page size, the counts are in pages
+type Pool struct {
+	Node     int    // NUMA node of the pool or AllNodes
+	Size     uint64 // Size of a page in bytes
+	Total    uint64 // Number of pages in the pool
+	Free     uint64 // Number of pages not allocated
+	Reserved uint64 // Number of pages reserved but not allocated, system wide pools only
+	Surplus  uint64 // Number of pages above the pool size
+}
+
+// Mount is a mounted hugetlbfs file system
+type Mount struct {
+	Path     string // Mount point
+	PageSize uint64 // Page size of the mount in bytes, 0 for the default size
+	Options  string // Mount options
+}
+
+// Info reads the hugepage information below the sysfs and proc roots
+type Info struct {
+	SysfsRoot string
+	ProcRoot  string
+}
+
+// New returns the hugepage information reader of the roots, empty roots are
+// the default roots.
+func New(sysfsRoot, procRoot string) *Info {
+
+	if len(sysfsRoot) == 0 {
+		sysfsRoot = DefaultSysfsRoot
+	}
+	if len(procRoot) == 0 {
+		procRoot = DefaultProcRoot
+	}
+	return &Info{SysfsRoot: sysfsRoot, ProcRoot: procRoot}
+}
+
+// FreeBytes returns the size of the free pages
+func (p Pool) FreeBytes() uint64 {
+	return p.Free * p.Size
+}
+
+// TotalBytes returns the size of all pages of the pool
+func (p Pool) TotalBytes() uint64 {
+	return p.Total * p.Size
+}
+
+// readCount returns the number in the file, zero if it can not be read
+func readCount(path string) uint64 {
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	v, _ := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+
+	return v
+}
+
+// readPools returns the pools of the hugepages-<size>kB directories in dir
+func readPools(dir string, node int) ([]Pool, error) {
+
+	dirs, err := filepath.Glob(filepath.Join(dir, "hugepages-*kB"))
+	if err != nil {
+		return nil, err
+	}
+
+	pools := []Pool{}
+	for _, d := range dirs {
+		kb, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(d), "hugepages-"), "kB"), 10, 64)
+		if err != nil {
+			continue
+		}
+		pools = append(pools, Pool{
+			Node:     node,
+			Size:     kb * 1024,
+			Total:    readCount(filepath.Join(d, "nr_hugepages")),
+			Free:     readCount(filepath.Join(d, "free_hugepages")),
+			Reserved: readCount(filepath.Join(d, "resv_hugepages")),
+			Surplus:  readCount(filepath.Join(d, "surplus_hugepages")),
+		})
+	}
+	return pools, nil
+}
+
+// Pools returns the system wide pools followed by the pools of each NUMA
+// node, the pools are sorted by node and page size.
+func (h *Info) Pools() ([]Pool, error) {
+
+	pools, err := readPools(filepath.Join(h.SysfsRoot, "kernel", "mm", "hugepages"), AllNodes)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := filepath.Glob(filepath.Join(h.SysfsRoot, "devices", "system", "node", "node*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nodes {
+		node, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(n), "node"))
+		if err != nil {
+			continue
+		}
+		p, err := readPools(filepath.Join(n, "hugepages"), node)
+		if err != nil {
+			return nil, err
+		}
+		pools = append(pools, p...)
+	}
+
+	sort.SliceStable(pools, func(i, j int) bool {
+		if pools[i].Node != pools[j].Node {
+			return pools[i].Node < pools[j].Node
+		}
+		return pools[i].Size < pools[j].Size
+	})
+	return pools, nil
+}
+
+// FreeBytes returns the free hugepage memory of each NUMA node, the system
+// wide pools are used when the system has no NUMA nodes.
+func FreeBytes(pools []Pool) map[int]uint64 {
+
+	free := make(map[int]uint64)
+	numa := false
+	for _, p := range pools {
+		if p.Node != AllNodes {
+			numa = true
+		}
+	}
+	for _, p := range pools {
+		if (p.Node == AllNodes) != numa {
+			free[p.Node] += p.FreeBytes()
+		}
+	}
+	return free
+}
+
+// ParseSize returns the bytes of a size with an optional K, M or G suffix
+func ParseSize(s string) (uint64, error) {
+
+	s = strings.TrimSpace(strings.ToUpper(s))
+	shift := 0
+	switch {
+	case strings.HasSuffix(s, "K"):
+		shift = 10
+	case strings.HasSuffix(s, "M"):
+		shift = 20
+	case strings.HasSuffix(s, "G"):
+		shift = 30
+	}
+	if shift > 0 {
+		s = s[:len(s)-1]
+	}
+
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return v << shift, nil
+}
+
+// Mounts returns the mounted hugetlbfs file systems
+func (h *Info) Mounts() ([]Mount, error) {
+
+	fd, err := os.Open(filepath.Join(h.ProcRoot, "mounts"))
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	mounts := []Mount{}
+
+	// device mount-point type options dump pass
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		f := strings.Fields(scanner.Text())
+		if len(f) < 4 || f[2] != "hugetlbfs" {
+			continue
+		}
+		m := Mount{Path: f[1], Options: f[3]}
+		for _, opt := range strings.Split(f[3], ",") {
+			if strings.HasPrefix(opt, "pagesize=") {
+				m.PageSize, _ = ParseSize(strings.TrimPrefix(opt, "pagesize="))
+			}
+		}
+		mounts = append(mounts, m)
+	}
+	return mounts, scanner.Err()
+}
+
+// DPDK mempool sizes of an mbuf element, the sizes of a build without the
+// mempool debug cookies.
+const (
+	MempoolHeaderSize = 64   // Size of the mempool object header
+	MbufHeaderSize    = 128  // Size of the rte_mbuf structure
+	DefaultMbufSize   = 2176 // RTE_MBUF_DEFAULT_BUF_SIZE, data room and headroom
+	cacheLineSize     = 64
+)
+
+// MempoolBytes returns the memory of a mempool of mbufs with the given
+// data room size including the headroom.
+func MempoolBytes(mbufs, mbufSize uint64) uint64 {
+
+	elt := MempoolHeaderSize + MbufHeaderSize + mbufSize
+	elt = (elt + cacheLineSize - 1) &^ (cacheLineSize - 1)
+
+	return mbufs * elt
+}
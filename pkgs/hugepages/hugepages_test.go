@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022 Intel Corporation
+
+package hugepages
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFiles writes the files below root
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, value := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(value), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPools(t *testing.T) {
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"sys/kernel/mm/hugepages/hugepages-2048kB/nr_hugepages":                      "1024\n",
+		"sys/kernel/mm/hugepages/hugepages-2048kB/free_hugepages":                    "1000\n",
+		"sys/kernel/mm/hugepages/hugepages-2048kB/resv_hugepages":                    "8\n",
+		"sys/kernel/mm/hugepages/hugepages-2048kB/surplus_hugepages":                 "0\n",
+		"sys/kernel/mm/hugepages/hugepages-1048576kB/nr_hugepages":                   "2\n",
+		"sys/kernel/mm/hugepages/hugepages-1048576kB/free_hugepages":                 "2\n",
+		"sys/devices/system/node/node0/hugepages/hugepages-2048kB/nr_hugepages":      "512\n",
+		"sys/devices/system/node/node0/hugepages/hugepages-2048kB/free_hugepages":    "500\n",
+		"sys/devices/system/node/node1/hugepages/hugepages-2048kB/nr_hugepages":      "512\n",
+		"sys/devices/system/node/node1/hugepages/hugepages-2048kB/free_hugepages":    "500\n",
+		"sys/devices/system/node/node1/hugepages/hugepages-1048576kB/nr_hugepages":   "2\n",
+		"sys/devices/system/node/node1/hugepages/hugepages-1048576kB/free_hugepages": "2\n",
+		"proc/mounts": "sysfs /sys sysfs rw 0 0\n" +
+			"hugetlbfs /dev/hugepages hugetlbfs rw,relatime,pagesize=2M 0 0\n" +
+			"nodev /mnt/huge1G hugetlbfs rw,pagesize=1024M 0 0\n",
+	})
+
+	h := New(filepath.Join(root, "sys"), filepath.Join(root, "proc"))
+
+	pools, err := h.Pools()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pools) != 5 {
+		t.Fatalf("got %d pools, want 5: %+v", len(pools), pools)
+	}
+	want := Pool{Node: AllNodes, Size: 2 << 20, Total: 1024, Free: 1000, Reserved: 8}
+	if pools[0] != want {
+		t.Errorf("got %+v, want %+v", pools[0], want)
+	}
+	if pools[4].Node != 1 || pools[4].Size != 1<<30 {
+		t.Errorf("last pool %+v", pools[4])
+	}
+
+	free := FreeBytes(pools)
+	if len(free) != 2 || free[0] != 500*(2<<20) || free[1] != 500*(2<<20)+2<<30 {
+		t.Errorf("free bytes %v", free)
+	}
+
+	mounts, err := h.Mounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mounts) != 2 || mounts[0].Path != "/dev/hugepages" || mounts[0].PageSize != 2<<20 ||
+		mounts[1].PageSize != 1<<30 {
+		t.Errorf("mounts %+v", mounts)
+	}
+}
+
+func TestFreeBytesNoNUMA(t *testing.T) {
+
+	free := FreeBytes([]Pool{{Node: AllNodes, Size: 4096, Free: 10}})
+	if free[AllNodes] != 40960 {
+		t.Errorf("free bytes %v", free)
+	}
+}
+
+func TestMempoolBytes(t *testing.T) {
+
+	// 64 + 128 + 2176 is 2368 bytes, a multiple of the cache line
+	if b := MempoolBytes(8192, DefaultMbufSize); b != 8192*2368 {
+		t.Errorf("mempool of %d bytes", b)
+	}
+	if b := MempoolBytes(1, 100); b != 320 {
+		t.Errorf("mempool of %d bytes", b)
+	}
+}
@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022 Intel Corporation
+
+package irqs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/KeithWiles/go-pktgen/pkgs/cpudata"
+)
+
+// The interrupts of a PCI device are the msi_irqs of the device in sysfs or
+// the legacy irq of the device. The counts of each CPU are read from the proc
+// interrupts file and the CPU affinity from proc irq/<irq>/smp_affinity_list.
+
+const (
+	// DefaultSysfsRoot is the root of the sysfs tree
+	DefaultSysfsRoot = "/sys"
+	// DefaultProcRoot is the root of the proc tree
+	DefaultProcRoot = "/proc"
+)
+
+// IRQ is an interrupt with its counts and CPU affinity
+type IRQ struct {
+	Number   int
+	Name     string   // Name of the handler i.e. ice-eth0-TxRx-3
+	Counts   []uint64 // Interrupts handled by each CPU of the interrupts file
+	Affinity []uint16 // Lcores the interrupt may be sent to
+}
+
+// Nodes returns the NUMA node of an lcore, cpudata.NodeMap is a Nodes
+type Nodes interface {
+	Node(lcore uint16) (int, bool)
+}
+
+// Info reads the interrupts below the sysfs and proc roots
+type Info struct {
+	SysfsRoot string
+	ProcRoot  string
+}
+
+// New returns the interrupt reader of the roots, empty roots are the default
+// roots.
+func New(sysfsRoot, procRoot string) *Info {
+
+	if len(sysfsRoot) == 0 {
+		sysfsRoot = DefaultSysfsRoot
+	}
+	if len(procRoot) == 0 {
+		procRoot = DefaultProcRoot
+	}
+	return &Info{SysfsRoot: sysfsRoot, ProcRoot: procRoot}
+}
+
+// Total returns the interrupts handled by all CPUs
+func (irq *IRQ) Total() uint64 {
+
+	total := uint64(0)
+	for _, c := range irq.Counts {
+		total += c
+	}
+	return total
+}
+
+// Remote returns the lcores of the affinity that are not on the node, the
+// lcores of an unknown node are not remote.
+func (irq *IRQ) Remote(node int, nodes Nodes) []uint16 {
+
+	remote := []uint16{}
+	for _, lcore := range irq.Affinity {
+		if n, ok := nodes.Node(lcore); ok && n != node {
+			remote = append(remote, lcore)
+		}
+	}
+	return remote
+}
+
+// Parse returns the numbered interrupts of an interrupts file, the rows of
+// the architecture counters like NMI and LOC are skipped.
+func Parse(r io.Reader) ([]*IRQ, error) {
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("interrupts file is empty")
+	}
+	ncpus := len(strings.Fields(scanner.Text()))
+
+	list := []*IRQ{}
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(fields[0], ":"))
+		if err != nil {
+			continue
+		}
+
+		irq := &IRQ{Number: n}
+		i := 1
+		for ; i < len(fields) && i <= ncpus; i++ {
+			c, err := strconv.ParseUint(fields[i], 10, 64)
+			if err != nil {
+				break
+			}
+			irq.Counts = append(irq.Counts, c)
+		}
+
+		// The chip and the trigger come before the names of the handlers
+		if i+2 < len(fields) {
+			irq.Name = strings.Join(fields[i+2:], " ")
+		}
+		list = append(list, irq)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// DeviceIRQs returns the interrupt numbers of the PCI device
+func (info *Info) DeviceIRQs(slot string) ([]int, error) {
+
+	dir := filepath.Join(info.SysfsRoot, "bus", "pci", "devices", slot)
+
+	numbers := []int{}
+	entries, err := os.ReadDir(filepath.Join(dir, "msi_irqs"))
+	if err == nil {
+		for _, e := range entries {
+			if n, err := strconv.Atoi(e.Name()); err == nil {
+				numbers = append(numbers, n)
+			}
+		}
+	}
+
+	// Without MSI the device has a legacy interrupt, zero is none
+	if len(numbers) == 0 {
+		b, err := os.ReadFile(filepath.Join(dir, "irq"))
+		if err != nil {
+			return nil, fmt.Errorf("device %s: %w", slot, err)
+		}
+		if n, err := strconv.Atoi(strings.TrimSpace(string(b))); err == nil && n > 0 {
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Ints(numbers)
+
+	return numbers, nil
+}
+
+// Devices returns the interrupts of each PCI device, an interrupt missing
+// from the interrupts file is not returned. A device with interrupts that
+// can not be read is skipped, the interrupts of the other devices are
+// returned with an error of the skipped devices.
+func (info *Info) Devices(slots []string) (map[string][]*IRQ, error) {
+
+	f, err := os.Open(filepath.Join(info.ProcRoot, "interrupts"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	list, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("interrupts: %w", err)
+	}
+	byNumber := make(map[int]*IRQ)
+	for _, irq := range list {
+		byNumber[irq.Number] = irq
+	}
+
+	devs := make(map[string][]*IRQ)
+	skipped := []string{}
+	for _, slot := range slots {
+		numbers, err := info.DeviceIRQs(slot)
+		if err != nil {
+			skipped = append(skipped, err.Error())
+			continue
+		}
+		devs[slot] = []*IRQ{}
+		for _, n := range numbers {
+			irq, ok := byNumber[n]
+			if !ok {
+				continue
+			}
+			path := filepath.Join(info.ProcRoot, "irq", strconv.Itoa(n), "smp_affinity_list")
+			if b, err := os.ReadFile(path); err == nil {
+				irq.Affinity, _ = cpudata.ParseList(string(b))
+			}
+			devs[slot] = append(devs[slot], irq)
+		}
+	}
+	if len(skipped) > 0 {
+		return devs, fmt.Errorf("%s", strings.Join(skipped, ", "))
+	}
+	return devs, nil
+}
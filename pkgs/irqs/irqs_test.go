@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022 Intel Corporation
+
+package irqs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/KeithWiles/go-pktgen/pkgs/cpudata"
+)
+
+const interrupts = `           CPU0       CPU1       CPU2       CPU3
+  0:         44          0          0          0   IO-APIC   2-edge      timer
+ 16:         10          0          5          0   IO-APIC  16-fasteoi   ehci_hcd:usb1, i801_smbus
+ 98:          1          0          0          0  IR-PCI-MSI 1048576-edge      ice-0000:18:00.0:misc
+ 99:       1200        300          0          0  IR-PCI-MSI 1048577-edge      ice-eth2-TxRx-0
+100:          0          0       7000         10  IR-PCI-MSI 1048578-edge      ice-eth2-TxRx-1
+NMI:          3          3          3          3   Non-maskable interrupts
+LOC:     123456     123456     123456     123456   Local timer interrupts
+ERR:          0
+`
+
+// writeFiles writes the files below root
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, value := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(value), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// nodes has lcores 0-1 on node 0 and 2-3 on node 1
+var nodes = cpudata.NodeMap{0: {0, 1}, 1: {2, 3}}
+
+func TestParse(t *testing.T) {
+
+	list, err := Parse(strings.NewReader(interrupts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 5 {
+		t.Fatalf("got %d interrupts, want 5", len(list))
+	}
+	if irq := list[1]; irq.Number != 16 || irq.Name != "ehci_hcd:usb1, i801_smbus" || irq.Total() != 15 {
+		t.Errorf("shared irq got %+v", irq)
+	}
+	if irq := list[3]; irq.Name != "ice-eth2-TxRx-0" || !reflect.DeepEqual(irq.Counts, []uint64{1200, 300, 0, 0}) {
+		t.Errorf("queue irq got %+v", irq)
+	}
+
+	if _, err := Parse(strings.NewReader("")); err == nil {
+		t.Errorf("empty file has no error")
+	}
+}
+
+func TestDevices(t *testing.T) {
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"proc/interrupts":                               interrupts,
+		"proc/irq/99/smp_affinity_list":                 "0-1\n",
+		"proc/irq/100/smp_affinity_list":                "1-3\n",
+		"sys/bus/pci/devices/0000:18:00.0/msi_irqs/99":  "msix\n",
+		"sys/bus/pci/devices/0000:18:00.0/msi_irqs/100": "msix\n",
+		"sys/bus/pci/devices/0000:18:00.0/msi_irqs/98":  "msix\n",
+		"sys/bus/pci/devices/0000:18:00.0/irq":          "16\n",
+		"sys/bus/pci/devices/0000:00:1d.0/irq":          "16\n",
+		"sys/bus/pci/devices/0000:00:1f.0/irq":          "0\n",
+		"sys/bus/pci/devices/0000:3b:00.0/msi_irqs/120": "msix\n",
+		"sys/bus/pci/devices/0000:3b:00.0/irq":          "0\n",
+	})
+	info := New(filepath.Join(root, "sys"), filepath.Join(root, "proc"))
+
+	if n, err := info.DeviceIRQs("0000:18:00.0"); err != nil || !reflect.DeepEqual(n, []int{98, 99, 100}) {
+		t.Errorf("msi irqs got %v, %v", n, err)
+	}
+	if n, err := info.DeviceIRQs("0000:00:1d.0"); err != nil || !reflect.DeepEqual(n, []int{16}) {
+		t.Errorf("legacy irq got %v, %v", n, err)
+	}
+	if n, err := info.DeviceIRQs("0000:00:1f.0"); err != nil || len(n) != 0 {
+		t.Errorf("no irq got %v, %v", n, err)
+	}
+	if _, err := info.DeviceIRQs("0000:af:00.0"); err == nil {
+		t.Errorf("missing device has no error")
+	}
+
+	// The missing device is skipped and the others are returned
+	devs, err := info.Devices([]string{"0000:18:00.0", "0000:af:00.0", "0000:3b:00.0"})
+	if err == nil || !strings.Contains(err.Error(), "0000:af:00.0") {
+		t.Errorf("missing device got error %v", err)
+	}
+	if _, ok := devs["0000:af:00.0"]; ok || devs == nil {
+		t.Fatalf("missing device got %v", devs)
+	}
+	if irqs, ok := devs["0000:3b:00.0"]; !ok || len(irqs) != 0 {
+		t.Errorf("irq missing from the interrupts file got %+v", devs["0000:3b:00.0"])
+	}
+	nic := devs["0000:18:00.0"]
+	if len(nic) != 3 {
+		t.Fatalf("got %d irqs, want 3", len(nic))
+	}
+	if nic[0].Affinity != nil {
+		t.Errorf("irq without affinity file got %v", nic[0].Affinity)
+	}
+	if r := nic[1].Remote(1, nodes); len(r) != 2 {
+		t.Errorf("node 1 remote lcores got %v", r)
+	}
+	if r := nic[2].Remote(1, nodes); !reflect.DeepEqual(r, []uint16{1}) {
+		t.Errorf("node 1 remote lcores got %v", r)
+	}
+	if r := nic[2].Remote(0, nodes); !reflect.DeepEqual(r, []uint16{2, 3}) {
+		t.Errorf("node 0 remote lcores got %v", r)
+	}
+}
@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2019-2022 Intel Corporation
+
+package keybind
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// keybind is the registry of the key bindings of the application. A binding
+// belongs to a scope, the global scope is the empty string, a panel scope is
+// the panel name and a window scope is the panel name and the window name
+// separated by a slash, i.e. "Single/singleConfig". A key of a scope hides
+// the same key of the enclosing scopes, which is reported as a conflict.
+
+// ScopeSeparator separates the panel and window names of a scope
+const ScopeSeparator = "/"
+
+// Binding of a key to an action in a scope
+type Binding struct {
+	Scope   string
+	Key     tcell.Key // tcell.KeyRune for a rune key
+	Rune    rune
+	Desc    string
+	Handler func() // Action of the key, nil when handled elsewhere
+}
+
+// Registry of key bindings
+type Registry struct {
+	lock     sync.Mutex
+	bindings []*Binding
+}
+
+var std = New()
+
+// New returns an empty registry
+func New() *Registry {
+	return &Registry{}
+}
+
+// Name returns the printable name of the key i.e. "q", "Ctrl-S" or "F1"
+func (b *Binding) Name() string {
+
+	if b.Key == tcell.KeyRune {
+		if b.Rune == ' ' {
+			return "Space"
+		}
+		return string(b.Rune)
+	}
+
+	name := tcell.NewEventKey(b.Key, 0, tcell.ModNone).Name()
+	return strings.Replace(name, "Ctrl+", "Ctrl-", 1)
+}
+
+// Matches returns true if the event is the key of the binding
+func (b *Binding) Matches(ev *tcell.EventKey) bool {
+
+	if ev.Key() != b.Key {
+		return false
+	}
+	return b.Key != tcell.KeyRune || ev.Rune() == b.Rune
+}
+
+// Parent returns the enclosing scope of a scope, the parent of a panel
+// scope is the global scope.
+func Parent(scope string) string {
+
+	if i := strings.LastIndex(scope, ScopeSeparator); i >= 0 {
+		return scope[:i]
+	}
+	return ""
+}
+
+// Scope returns the scope of a window of a panel
+func Scope(panel, window string) string {
+	return panel + ScopeSeparator + window
+}
+
+// encloses returns true if the outer scope is the scope or encloses it
+func encloses(outer, scope string) bool {
+
+	for {
+		if outer == scope {
+			return true
+		}
+		if scope == "" {
+			return false
+		}
+		scope = Parent(scope)
+	}
+}
+
+// Add a key binding to a scope, the key is a rune or a tcell.Key
+func (r *Registry) Add(scope string, key interface{}, desc string, handler ...func()) *Binding {
+
+	b := &Binding{Scope: scope, Desc: desc}
+
+	switch k := key.(type) {
+	case rune:
+		b.Key, b.Rune = tcell.KeyRune, k
+	case tcell.Key:
+		b.Key = k
+	}
+	if len(handler) > 0 {
+		b.Handler = handler[0]
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.bindings = append(r.bindings, b)
+
+	return b
+}
+
+// Bindings returns the bindings of a scope in the order added
+func (r *Registry) Bindings(scope string) []*Binding {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var list []*Binding
+	for _, b := range r.bindings {
+		if b.Scope == scope {
+			list = append(list, b)
+		}
+	}
+	return list
+}
+
+// Find returns the binding of the event, the scope and its enclosing scopes
+// are searched from the innermost to the global scope.
+func (r *Registry) Find(ev *tcell.EventKey, scope string) *Binding {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for {
+		for _, b := range r.bindings {
+			if b.Scope == scope && b.Matches(ev) {
+				return b
+			}
+		}
+		if scope == "" {
+			return nil
+		}
+		scope = Parent(scope)
+	}
+}
+
+// Dispatch calls the handler of the binding of the event, see Find. It
+// returns true if a handler was called.
+func (r *Registry) Dispatch(ev *tcell.EventKey, scope string) bool {
+
+	b := r.Find(ev, scope)
+	if b == nil || b.Handler == nil {
+		return false
+	}
+	b.Handler()
+
+	return true
+}
+
+// Conflict is a key bound twice in a scope or in a scope and an enclosing
+// scope
+type Conflict struct {
+	First, Second *Binding
+}
+
+// String returns the description of the conflict
+func (c Conflict) String() string {
+
+	scope := func(s string) string {
+		if s == "" {
+			return "global"
+		}
+		return s
+	}
+	return fmt.Sprintf("key %s: %s (%s) and %s (%s)", c.First.Name(),
+		c.First.Desc, scope(c.First.Scope), c.Second.Desc, scope(c.Second.Scope))
+}
+
+// Conflicts returns the keys bound more than once in a scope or in a scope
+// and one of its enclosing scopes.
+func (r *Registry) Conflicts() []Conflict {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var list []Conflict
+	for i, a := range r.bindings {
+		for _, b := range r.bindings[i+1:] {
+			if a.Key != b.Key || a.Rune != b.Rune {
+				continue
+			}
+			if encloses(a.Scope, b.Scope) || encloses(b.Scope, a.Scope) {
+				list = append(list, Conflict{First: a, Second: b})
+			}
+		}
+	}
+	return list
+}
+
+// Add a key binding to the application registry
+func Add(scope string, key interface{}, desc string, handler ...func()) *Binding {
+	return std.Add(scope, key, desc, handler...)
+}
+
+// Bindings returns the bindings of a scope of the application registry
+func Bindings(scope string) []*Binding {
+	return std.Bindings(scope)
+}
+
+// Find returns the binding of the event in the application registry
+func Find(ev *tcell.EventKey, scope string) *Binding {
+	return std.Find(ev, scope)
+}
+
+// Dispatch calls the handler of the binding of the event in the application
+// registry
+func Dispatch(ev *tcell.EventKey, scope string) bool {
+	return std.Dispatch(ev, scope)
+}
+
+// Conflicts returns the conflicting keys of the application registry
+func Conflicts() []Conflict {
+	return std.Conflicts()
+}
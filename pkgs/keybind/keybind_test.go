@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2019-2022 Intel Corporation
+
+package keybind
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func runeKey(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func TestName(t *testing.T) {
+	r := New()
+
+	tests := []struct {
+		key  interface{}
+		name string
+	}{
+		{'q', "q"},
+		{' ', "Space"},
+		{tcell.KeyCtrlS, "Ctrl-S"},
+		{tcell.KeyF1, "F1"},
+		{tcell.KeyEscape, "Esc"},
+	}
+	for _, tt := range tests {
+		if name := r.Add("", tt.key, "").Name(); name != tt.name {
+			t.Errorf("Name of %v is %q, want %q", tt.key, name, tt.name)
+		}
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	r := New()
+
+	var called string
+	r.Add("", 'q', "Quit", func() { called = "global" })
+	r.Add("Single", 'r', "Start", func() { called = "panel" })
+	r.Add(Scope("Single", "config"), 'e', "Edit", func() { called = "window" })
+	r.Add("Single", 'x', "No handler")
+
+	tests := []struct {
+		scope  string
+		r      rune
+		called string
+	}{
+		{Scope("Single", "config"), 'e', "window"},
+		{Scope("Single", "config"), 'r', "panel"},
+		{Scope("Single", "config"), 'q', "global"},
+		{"Single", 'e', ""},
+		{"Graphs", 'r', ""},
+		{"Single", 'x', ""},
+	}
+	for _, tt := range tests {
+		called = ""
+		ok := r.Dispatch(runeKey(tt.r), tt.scope)
+		if called != tt.called || ok != (tt.called != "") {
+			t.Errorf("Dispatch %c in %q called %q (%v), want %q", tt.r, tt.scope, called, ok, tt.called)
+		}
+	}
+
+	if b := r.Find(runeKey('x'), "Single"); b == nil || b.Desc != "No handler" {
+		t.Errorf("Find did not return the binding without handler")
+	}
+	if n := len(r.Bindings("Single")); n != 2 {
+		t.Errorf("Bindings of Single returned %d bindings, want 2", n)
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	r := New()
+
+	r.Add("", 'q', "Quit")
+	r.Add("Single", 'r', "Start")
+	r.Add("Graphs", 'r', "Reduce")
+	r.Add(Scope("Single", "config"), 'e', "Edit")
+	r.Add(Scope("Single", "stats"), 'e', "Edit stats")
+
+	if c := r.Conflicts(); len(c) != 0 {
+		t.Fatalf("unexpected conflicts %v", c)
+	}
+
+	r.Add(Scope("Single", "config"), 'q', "Quick start")
+	r.Add("Single", 'r', "Reset")
+
+	c := r.Conflicts()
+	if len(c) != 2 {
+		t.Fatalf("got %d conflicts, want 2: %v", len(c), c)
+	}
+	if c[0].First.Desc != "Quit" || c[0].Second.Desc != "Quick start" {
+		t.Errorf("wrong first conflict %v", c[0])
+	}
+	if c[1].First.Desc != "Start" || c[1].Second.Desc != "Reset" {
+		t.Errorf("wrong second conflict %v", c[1])
+	}
+}
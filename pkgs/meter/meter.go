@@ -84,3 +84,28 @@ func (m *Meter) Draw(rate float64, mi *Info) string {
 
 	return m.draw(mi)
 }
+
+// sparkBlocks are the eighth blocks of a sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline returns the values as one block character each, the values are
+// clamped to the low and high limits. The sparkline is padded on the left to
+// width characters, the newest values are kept if there are more.
+func Sparkline(values []float64, low, high float64, width int) string {
+
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	str := strings.Repeat(" ", width-len(values))
+	for _, v := range values {
+		v = math.Max(low, math.Min(high, v))
+
+		i := 0
+		if high > low {
+			i = int(math.Round((v - low) / (high - low) * float64(len(sparkBlocks)-1)))
+		}
+		str += string(sparkBlocks[i])
+	}
+	return str
+}
@@ -17,3 +17,16 @@ func TestClose(t *testing.T) {
 	fmt.Printf("Close meter\n")
 
 }
+
+func TestSparkline(t *testing.T) {
+
+	if s := Sparkline([]float64{0, 50, 100, 150, -5}, 0, 100, 5); s != "▁▅██▁" {
+		t.Errorf("got %q", s)
+	}
+	if s := Sparkline([]float64{100}, 0, 100, 3); s != "  █" {
+		t.Errorf("padded got %q", s)
+	}
+	if s := Sparkline([]float64{0, 0, 100, 100}, 0, 100, 2); s != "██" {
+		t.Errorf("newest got %q", s)
+	}
+}
@@ -0,0 +1,8 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022 Intel Corporation
+
+// Package pktgenrpc holds the gRPC API definition of go-pktgen and the Go code
+// generated from pktgen.proto. Regenerate the code after changing the proto file.
+package pktgenrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative pktgen.proto
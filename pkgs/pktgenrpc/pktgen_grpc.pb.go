@@ -0,0 +1,411 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022 Intel Corporation
+
+// gRPC API for go-pktgen. The unary calls configure and control the ports
+// while StreamStats pushes the port statistics to the client every time the
+// statistics are collected by the timer routines.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: pktgen.proto
+
+package pktgenrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Pktgen_GetVersion_FullMethodName    = "/pktgen.Pktgen/GetVersion"
+	Pktgen_ListPorts_FullMethodName     = "/pktgen.Pktgen/ListPorts"
+	Pktgen_GetPortConfig_FullMethodName = "/pktgen.Pktgen/GetPortConfig"
+	Pktgen_SetPortConfig_FullMethodName = "/pktgen.Pktgen/SetPortConfig"
+	Pktgen_StartTraffic_FullMethodName  = "/pktgen.Pktgen/StartTraffic"
+	Pktgen_StopTraffic_FullMethodName   = "/pktgen.Pktgen/StopTraffic"
+	Pktgen_GetStats_FullMethodName      = "/pktgen.Pktgen/GetStats"
+	Pktgen_StreamStats_FullMethodName   = "/pktgen.Pktgen/StreamStats"
+)
+
+// PktgenClient is the client API for Pktgen service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PktgenClient interface {
+	// Version of the running go-pktgen
+	GetVersion(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionReply, error)
+	// Port configuration
+	ListPorts(ctx context.Context, in *ListPortsRequest, opts ...grpc.CallOption) (*ListPortsReply, error)
+	GetPortConfig(ctx context.Context, in *PortRequest, opts ...grpc.CallOption) (*PortConfig, error)
+	SetPortConfig(ctx context.Context, in *PortConfig, opts ...grpc.CallOption) (*PortConfig, error)
+	// Traffic control, an empty port list selects all ports
+	StartTraffic(ctx context.Context, in *PortSelect, opts ...grpc.CallOption) (*TrafficReply, error)
+	StopTraffic(ctx context.Context, in *PortSelect, opts ...grpc.CallOption) (*TrafficReply, error)
+	// Statistics, an empty port list selects all ports
+	GetStats(ctx context.Context, in *PortSelect, opts ...grpc.CallOption) (*StatsReply, error)
+	StreamStats(ctx context.Context, in *PortSelect, opts ...grpc.CallOption) (Pktgen_StreamStatsClient, error)
+}
+
+type pktgenClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPktgenClient(cc grpc.ClientConnInterface) PktgenClient {
+	return &pktgenClient{cc}
+}
+
+func (c *pktgenClient) GetVersion(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionReply, error) {
+	out := new(VersionReply)
+	err := c.cc.Invoke(ctx, Pktgen_GetVersion_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pktgenClient) ListPorts(ctx context.Context, in *ListPortsRequest, opts ...grpc.CallOption) (*ListPortsReply, error) {
+	out := new(ListPortsReply)
+	err := c.cc.Invoke(ctx, Pktgen_ListPorts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pktgenClient) GetPortConfig(ctx context.Context, in *PortRequest, opts ...grpc.CallOption) (*PortConfig, error) {
+	out := new(PortConfig)
+	err := c.cc.Invoke(ctx, Pktgen_GetPortConfig_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pktgenClient) SetPortConfig(ctx context.Context, in *PortConfig, opts ...grpc.CallOption) (*PortConfig, error) {
+	out := new(PortConfig)
+	err := c.cc.Invoke(ctx, Pktgen_SetPortConfig_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pktgenClient) StartTraffic(ctx context.Context, in *PortSelect, opts ...grpc.CallOption) (*TrafficReply, error) {
+	out := new(TrafficReply)
+	err := c.cc.Invoke(ctx, Pktgen_StartTraffic_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pktgenClient) StopTraffic(ctx context.Context, in *PortSelect, opts ...grpc.CallOption) (*TrafficReply, error) {
+	out := new(TrafficReply)
+	err := c.cc.Invoke(ctx, Pktgen_StopTraffic_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pktgenClient) GetStats(ctx context.Context, in *PortSelect, opts ...grpc.CallOption) (*StatsReply, error) {
+	out := new(StatsReply)
+	err := c.cc.Invoke(ctx, Pktgen_GetStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pktgenClient) StreamStats(ctx context.Context, in *PortSelect, opts ...grpc.CallOption) (Pktgen_StreamStatsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Pktgen_ServiceDesc.Streams[0], Pktgen_StreamStats_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pktgenStreamStatsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Pktgen_StreamStatsClient interface {
+	Recv() (*StatsReply, error)
+	grpc.ClientStream
+}
+
+type pktgenStreamStatsClient struct {
+	grpc.ClientStream
+}
+
+func (x *pktgenStreamStatsClient) Recv() (*StatsReply, error) {
+	m := new(StatsReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PktgenServer is the server API for Pktgen service.
+// All implementations must embed UnimplementedPktgenServer
+// for forward compatibility
+type PktgenServer interface {
+	// Version of the running go-pktgen
+	GetVersion(context.Context, *VersionRequest) (*VersionReply, error)
+	// Port configuration
+	ListPorts(context.Context, *ListPortsRequest) (*ListPortsReply, error)
+	GetPortConfig(context.Context, *PortRequest) (*PortConfig, error)
+	SetPortConfig(context.Context, *PortConfig) (*PortConfig, error)
+	// Traffic control, an empty port list selects all ports
+	StartTraffic(context.Context, *PortSelect) (*TrafficReply, error)
+	StopTraffic(context.Context, *PortSelect) (*TrafficReply, error)
+	// Statistics, an empty port list selects all ports
+	GetStats(context.Context, *PortSelect) (*StatsReply, error)
+	StreamStats(*PortSelect, Pktgen_StreamStatsServer) error
+	mustEmbedUnimplementedPktgenServer()
+}
+
+// UnimplementedPktgenServer must be embedded to have forward compatible implementations.
+type UnimplementedPktgenServer struct {
+}
+
+func (UnimplementedPktgenServer) GetVersion(context.Context, *VersionRequest) (*VersionReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
+}
+func (UnimplementedPktgenServer) ListPorts(context.Context, *ListPortsRequest) (*ListPortsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPorts not implemented")
+}
+func (UnimplementedPktgenServer) GetPortConfig(context.Context, *PortRequest) (*PortConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPortConfig not implemented")
+}
+func (UnimplementedPktgenServer) SetPortConfig(context.Context, *PortConfig) (*PortConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPortConfig not implemented")
+}
+func (UnimplementedPktgenServer) StartTraffic(context.Context, *PortSelect) (*TrafficReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartTraffic not implemented")
+}
+func (UnimplementedPktgenServer) StopTraffic(context.Context, *PortSelect) (*TrafficReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopTraffic not implemented")
+}
+func (UnimplementedPktgenServer) GetStats(context.Context, *PortSelect) (*StatsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedPktgenServer) StreamStats(*PortSelect, Pktgen_StreamStatsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamStats not implemented")
+}
+func (UnimplementedPktgenServer) mustEmbedUnimplementedPktgenServer() {}
+
+// UnsafePktgenServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PktgenServer will
+// result in compilation errors.
+type UnsafePktgenServer interface {
+	mustEmbedUnimplementedPktgenServer()
+}
+
+func RegisterPktgenServer(s grpc.ServiceRegistrar, srv PktgenServer) {
+	s.RegisterService(&Pktgen_ServiceDesc, srv)
+}
+
+func _Pktgen_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PktgenServer).GetVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Pktgen_GetVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PktgenServer).GetVersion(ctx, req.(*VersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pktgen_ListPorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPortsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PktgenServer).ListPorts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Pktgen_ListPorts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PktgenServer).ListPorts(ctx, req.(*ListPortsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pktgen_GetPortConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PortRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PktgenServer).GetPortConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Pktgen_GetPortConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PktgenServer).GetPortConfig(ctx, req.(*PortRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pktgen_SetPortConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PortConfig)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PktgenServer).SetPortConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Pktgen_SetPortConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PktgenServer).SetPortConfig(ctx, req.(*PortConfig))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pktgen_StartTraffic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PortSelect)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PktgenServer).StartTraffic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Pktgen_StartTraffic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PktgenServer).StartTraffic(ctx, req.(*PortSelect))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pktgen_StopTraffic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PortSelect)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PktgenServer).StopTraffic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Pktgen_StopTraffic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PktgenServer).StopTraffic(ctx, req.(*PortSelect))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pktgen_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PortSelect)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PktgenServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Pktgen_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PktgenServer).GetStats(ctx, req.(*PortSelect))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pktgen_StreamStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PortSelect)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PktgenServer).StreamStats(m, &pktgenStreamStatsServer{stream})
+}
+
+type Pktgen_StreamStatsServer interface {
+	Send(*StatsReply) error
+	grpc.ServerStream
+}
+
+type pktgenStreamStatsServer struct {
+	grpc.ServerStream
+}
+
+func (x *pktgenStreamStatsServer) Send(m *StatsReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Pktgen_ServiceDesc is the grpc.ServiceDesc for Pktgen service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Pktgen_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pktgen.Pktgen",
+	HandlerType: (*PktgenServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetVersion",
+			Handler:    _Pktgen_GetVersion_Handler,
+		},
+		{
+			MethodName: "ListPorts",
+			Handler:    _Pktgen_ListPorts_Handler,
+		},
+		{
+			MethodName: "GetPortConfig",
+			Handler:    _Pktgen_GetPortConfig_Handler,
+		},
+		{
+			MethodName: "SetPortConfig",
+			Handler:    _Pktgen_SetPortConfig_Handler,
+		},
+		{
+			MethodName: "StartTraffic",
+			Handler:    _Pktgen_StartTraffic_Handler,
+		},
+		{
+			MethodName: "StopTraffic",
+			Handler:    _Pktgen_StopTraffic_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _Pktgen_GetStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamStats",
+			Handler:       _Pktgen_StreamStats_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pktgen.proto",
+}
@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022 Intel Corporation
+
+package pktgenrpc
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStatsMarshal(t *testing.T) {
+	in := &StatsReply{
+		Ticks: 42,
+		Ports: []*PortStats{
+			{Port: 1, RxPps: 100, TxPps: 200, Latency: &Latency{AvgUsec: 12.5, Samples: 3}},
+		},
+	}
+
+	b, err := proto.Marshal(in)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	out := &StatsReply{}
+	if err := proto.Unmarshal(b, out); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if !proto.Equal(in, out) {
+		t.Errorf("stats mismatch: got %v want %v", out, in)
+	}
+}
@@ -0,0 +1,19 @@
+module github.com/KeithWiles/go-pktgen/pkgs/preflight
+
+replace github.com/KeithWiles/go-pktgen/pkgs/cpudata => ../cpudata
+
+replace github.com/KeithWiles/go-pktgen/pkgs/hugepages => ../hugepages
+
+go 1.18
+
+require (
+	github.com/KeithWiles/go-pktgen/pkgs/cpudata v0.0.0-00010101000000-000000000000
+	github.com/KeithWiles/go-pktgen/pkgs/hugepages v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/shirou/gopsutil v3.21.11+incompatible // indirect
+	github.com/tklauser/go-sysconf v0.3.10 // indirect
+	github.com/tklauser/numcpus v0.4.0 // indirect
+	golang.org/x/sys v0.1.0 // indirect
+)
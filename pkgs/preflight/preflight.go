@@ -0,0 +1,535 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022 Intel Corporation
+
+package preflight
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/KeithWiles/go-pktgen/pkgs/cpudata"
+	"github.com/KeithWiles/go-pktgen/pkgs/hugepages"
+)
+
+// The checks read the state of the host from the proc and sysfs trees only,
+// the roots can be changed to run the checks against a fixture tree.
+
+const (
+	// DefaultSysfsRoot is the root of the sysfs tree
+	DefaultSysfsRoot = "/sys"
+	// DefaultProcRoot is the root of the proc tree
+	DefaultProcRoot = "/proc"
+)
+
+// Status of a check
+type Status int
+
+// Status values, in the order of severity
+const (
+	Pass Status = iota
+	Warn
+	Fail
+)
+
+func (s Status) String() string {
+
+	switch s {
+	case Pass:
+		return "PASS"
+	case Warn:
+		return "WARN"
+	case Fail:
+		return "FAIL"
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// Result of a check
+type Result struct {
+	Name   string // Name of the check
+	Status Status // Pass, Warn or Fail
+	Detail string // What the check found
+	Fix    string // Suggested fix, empty when the check passed
+}
+
+// Topology returns the hyperthread siblings of an lcore including the lcore,
+// cpudata.CPUData implements it with its core map.
+type Topology interface {
+	Siblings(lcore uint16) []uint16
+}
+
+// SysfsTopology returns the hyperthread siblings from the CPU topology of
+// the sysfs tree
+type SysfsTopology struct {
+	Root string
+}
+
+// Siblings returns the hyperthread siblings of the lcore
+func (t SysfsTopology) Siblings(lcore uint16) []uint16 {
+
+	path := filepath.Join(t.Root, "devices", "system", "cpu", fmt.Sprintf("cpu%d", lcore),
+		"topology", "thread_siblings_list")
+
+	l, err := cpudata.ParseList(readFile(path))
+	if err != nil {
+		return nil
+	}
+	return l
+}
+
+// Checker runs the checks of the host
+type Checker struct {
+	SysfsRoot string
+	ProcRoot  string
+	Lcores    []uint16 // Lcores used by go-pktgen, empty if not chosen
+	Modules   []string // DPDK driver modules, the first one is preferred
+	Devices   []string // PCI addresses of the port devices
+	Topology  Topology // Hyperthread siblings, nil to read the sysfs topology
+}
+
+// New returns a checker of the roots, empty roots are the default roots
+func New(sysfsRoot, procRoot string) *Checker {
+
+	if len(sysfsRoot) == 0 {
+		sysfsRoot = DefaultSysfsRoot
+	}
+	if len(procRoot) == 0 {
+		procRoot = DefaultProcRoot
+	}
+	return &Checker{
+		SysfsRoot: sysfsRoot,
+		ProcRoot:  procRoot,
+		Modules:   []string{"vfio-pci"},
+	}
+}
+
+// Worst returns the most severe status of the results
+func Worst(results []Result) Status {
+
+	worst := Pass
+	for _, r := range results {
+		if r.Status > worst {
+			worst = r.Status
+		}
+	}
+	return worst
+}
+
+// readFile returns the trimmed content of the file, empty on errors
+func readFile(path string) string {
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// sysfs returns a path in the sysfs tree
+func (c *Checker) sysfs(elem ...string) string {
+	return filepath.Join(append([]string{c.SysfsRoot}, elem...)...)
+}
+
+// cmdline returns the kernel command line parameters, a parameter without a
+// value has an empty value.
+func (c *Checker) cmdline() map[string]string {
+
+	params := make(map[string]string)
+	for _, f := range strings.Fields(readFile(filepath.Join(c.ProcRoot, "cmdline"))) {
+		k, v, _ := strings.Cut(f, "=")
+		params[k] = v
+	}
+	return params
+}
+
+// missing returns the lcores not in the list
+func missing(lcores, list []uint16) []uint16 {
+
+	in := make(map[uint16]bool)
+	for _, l := range list {
+		in[l] = true
+	}
+
+	m := []uint16{}
+	for _, l := range lcores {
+		if !in[l] {
+			m = append(m, l)
+		}
+	}
+	return m
+}
+
+// cpuList returns the lcores of a CPU list parameter, the flags of the
+// isolcpus parameter i.e. "managed_irq,domain,2-5" are skipped.
+func cpuList(value string) []uint16 {
+
+	lcores := []uint16{}
+	for _, item := range strings.Split(value, ",") {
+		if l, err := cpudata.ParseList(item); err == nil {
+			lcores = append(lcores, l...)
+		}
+	}
+	return lcores
+}
+
+// Run the checks and return the results in a fixed order
+func (c *Checker) Run() []Result {
+
+	results := []Result{
+		c.CheckIOMMU(),
+		c.CheckModules(),
+	}
+	for _, param := range []string{"isolcpus", "nohz_full", "rcu_nocbs"} {
+		results = append(results, c.CheckCmdline(param))
+	}
+	results = append(results,
+		c.CheckGovernor(),
+		c.CheckSiblings(),
+		c.CheckHugepages(),
+		c.CheckNUMA(),
+	)
+	return results
+}
+
+// CheckIOMMU checks the IOMMU is enabled for vfio
+func (c *Checker) CheckIOMMU() Result {
+
+	r := Result{Name: "IOMMU"}
+
+	groups, _ := filepath.Glob(c.sysfs("kernel", "iommu_groups", "*"))
+	params := c.cmdline()
+
+	switch {
+	case len(groups) > 0 && params["iommu"] == "pt":
+		r.Detail = fmt.Sprintf("enabled, %d groups, passthrough mode", len(groups))
+	case len(groups) > 0:
+		r.Status = Warn
+		r.Detail = fmt.Sprintf("enabled, %d groups, DMA of the host is translated", len(groups))
+		r.Fix = "add iommu=pt to the kernel command line"
+	case readFile(c.sysfs("module", "vfio", "parameters", "enable_unsafe_noiommu_mode")) == "Y":
+		r.Status = Warn
+		r.Detail = "disabled, vfio runs in the unsafe no-IOMMU mode"
+		r.Fix = "enable VT-d or AMD-Vi in the BIOS and add intel_iommu=on iommu=pt to the kernel command line"
+	default:
+		r.Status = Fail
+		r.Detail = "disabled, no IOMMU groups"
+		r.Fix = "enable VT-d or AMD-Vi in the BIOS and add intel_iommu=on iommu=pt to the kernel command line"
+	}
+	return r
+}
+
+// moduleLoaded returns true if the kernel module or its PCI driver exists
+func (c *Checker) moduleLoaded(module string) bool {
+
+	for _, path := range []string{
+		c.sysfs("module", strings.ReplaceAll(module, "-", "_")),
+		c.sysfs("bus", "pci", "drivers", module),
+	} {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckModules checks a DPDK driver module is loaded, the first module is
+// the preferred one.
+func (c *Checker) CheckModules() Result {
+
+	r := Result{Name: "Driver modules"}
+
+	loaded := []string{}
+	for _, m := range c.Modules {
+		if c.moduleLoaded(m) {
+			loaded = append(loaded, m)
+		}
+	}
+
+	switch {
+	case len(c.Modules) == 0:
+		r.Detail = "no driver modules to check"
+	case len(loaded) > 0 && loaded[0] == c.Modules[0]:
+		r.Detail = strings.Join(loaded, ", ") + " loaded"
+	case len(loaded) > 0:
+		r.Status = Warn
+		r.Detail = fmt.Sprintf("%s loaded, %s is not", strings.Join(loaded, ", "), c.Modules[0])
+		r.Fix = "modprobe " + c.Modules[0]
+	default:
+		r.Status = Fail
+		r.Detail = fmt.Sprintf("none of %s loaded", strings.Join(c.Modules, ", "))
+		r.Fix = "modprobe " + c.Modules[0]
+	}
+	return r
+}
+
+// CheckCmdline checks the kernel command line parameter with a CPU list
+// includes the lcores
+func (c *Checker) CheckCmdline(param string) Result {
+
+	r := Result{Name: param}
+
+	value, ok := c.cmdline()[param]
+	if !ok {
+		r.Status = Warn
+		r.Detail = "not on the kernel command line"
+		r.Fix = "add " + param + "=<lcores> to the kernel command line"
+		if len(c.Lcores) > 0 {
+			r.Fix = fmt.Sprintf("add %s=%s to the kernel command line", param, cpudata.FormatList(c.Lcores))
+		}
+		return r
+	}
+
+	list := cpuList(value)
+	r.Detail = cpudata.FormatList(list)
+	if m := missing(c.Lcores, list); len(m) > 0 {
+		r.Status = Warn
+		r.Detail = fmt.Sprintf("%s, lcores %s not included", r.Detail, cpudata.FormatList(m))
+		r.Fix = fmt.Sprintf("add lcores %s to %s on the kernel command line", cpudata.FormatList(m), param)
+	}
+	return r
+}
+
+// onlineCPUs returns the online lcores
+func (c *Checker) onlineCPUs() []uint16 {
+
+	l, err := cpudata.ParseList(readFile(c.sysfs("devices", "system", "cpu", "online")))
+	if err != nil {
+		return nil
+	}
+	return l
+}
+
+// CheckGovernor checks the CPU frequency governor of the lcores, or of all
+// online lcores if none are chosen
+func (c *Checker) CheckGovernor() Result {
+
+	r := Result{Name: "CPU governor"}
+
+	lcores := c.Lcores
+	if len(lcores) == 0 {
+		lcores = c.onlineCPUs()
+	}
+
+	governors := make(map[string][]uint16)
+	for _, l := range lcores {
+		g := readFile(c.sysfs("devices", "system", "cpu", fmt.Sprintf("cpu%d", l), "cpufreq", "scaling_governor"))
+		if len(g) > 0 {
+			governors[g] = append(governors[g], l)
+		}
+	}
+
+	if len(governors) == 0 {
+		r.Detail = "no cpufreq driver, the frequency is not scaled"
+		return r
+	}
+
+	names := []string{}
+	slow := []uint16{}
+	for g, l := range governors {
+		names = append(names, fmt.Sprintf("%s on %s", g, cpudata.FormatList(l)))
+		if g != "performance" {
+			slow = append(slow, l...)
+		}
+	}
+	sort.Strings(names)
+	r.Detail = strings.Join(names, ", ")
+
+	if len(slow) > 0 {
+		r.Status = Warn
+		r.Fix = fmt.Sprintf("cpupower -c %s frequency-set -g performance", cpudata.FormatList(slow))
+	}
+	return r
+}
+
+// CheckSiblings checks the hyperthread siblings of the lcores, an lcore
+// sharing its core with a busy sibling loses performance
+func (c *Checker) CheckSiblings() Result {
+
+	r := Result{Name: "HT siblings"}
+
+	if len(c.Lcores) == 0 {
+		r.Status = Warn
+		r.Detail = "no lcores chosen"
+		r.Fix = "set the lcores in the configuration or with --lcores"
+		return r
+	}
+
+	topo := c.Topology
+	if topo == nil {
+		topo = SysfsTopology{Root: c.SysfsRoot}
+	}
+
+	chosen := make(map[uint16]bool)
+	for _, l := range c.Lcores {
+		chosen[l] = true
+	}
+	isolated := make(map[uint16]bool)
+	for _, l := range cpuList(c.cmdline()["isolcpus"]) {
+		isolated[l] = true
+	}
+
+	shared := []string{}
+	busy := []uint16{}
+	for _, l := range c.Lcores {
+		for _, s := range topo.Siblings(l) {
+			switch {
+			case s == l:
+			case chosen[s]:
+				// Report each pair once
+				if s > l {
+					shared = append(shared, fmt.Sprintf("%d/%d", l, s))
+				}
+			case !isolated[s]:
+				busy = append(busy, s)
+			}
+		}
+	}
+
+	switch {
+	case len(shared) > 0:
+		r.Status = Warn
+		r.Detail = "lcores " + strings.Join(shared, ", ") + " share a core"
+		r.Fix = "choose one lcore of each core or disable SMT"
+	case len(busy) > 0:
+		r.Status = Warn
+		r.Detail = fmt.Sprintf("siblings %s of the lcores are not isolated", cpudata.FormatList(busy))
+		r.Fix = fmt.Sprintf("add %s to isolcpus or disable SMT", cpudata.FormatList(busy))
+	default:
+		r.Detail = "no lcore shares its core with a busy sibling"
+	}
+	return r
+}
+
+// deviceNode returns the NUMA node of the PCI device, -1 if unknown
+func (c *Checker) deviceNode(slot string) int {
+
+	node, err := strconv.Atoi(readFile(c.sysfs("bus", "pci", "devices", slot, "numa_node")))
+	if err != nil {
+		return -1
+	}
+	return node
+}
+
+// CheckHugepages checks hugepages are reserved, free and mounted and free
+// on the NUMA nodes of the port devices
+func (c *Checker) CheckHugepages() Result {
+
+	r := Result{Name: "Hugepages"}
+
+	h := hugepages.New(c.SysfsRoot, c.ProcRoot)
+	pools, _ := h.Pools()
+	mounts, _ := h.Mounts()
+
+	var total, free uint64
+	for _, p := range pools {
+		if p.Node == hugepages.AllNodes {
+			total += p.TotalBytes()
+			free += p.FreeBytes()
+		}
+	}
+	fix := "echo 1024 > /sys/kernel/mm/hugepages/hugepages-2048kB/nr_hugepages"
+
+	switch {
+	case total == 0:
+		r.Status = Fail
+		r.Detail = "no hugepages reserved"
+		r.Fix = fix
+		return r
+	case free == 0:
+		r.Status = Fail
+		r.Detail = "all hugepages in use"
+		r.Fix = "stop the processes using the hugepages or reserve more, " + fix
+		return r
+	case len(mounts) == 0:
+		r.Status = Fail
+		r.Detail = "hugetlbfs not mounted"
+		r.Fix = "mount -t hugetlbfs nodev /dev/hugepages"
+		return r
+	}
+	r.Detail = fmt.Sprintf("%d MB free of %d MB, mounted on %s", free>>20, total>>20, mounts[0].Path)
+
+	// The NUMA nodes of the devices need free hugepages
+	nodeFree := hugepages.FreeBytes(pools)
+	if _, ok := nodeFree[hugepages.AllNodes]; ok {
+		return r
+	}
+	empty := []string{}
+	for _, slot := range c.Devices {
+		if node := c.deviceNode(slot); node >= 0 && nodeFree[node] == 0 {
+			empty = append(empty, fmt.Sprintf("%d (%s)", node, slot))
+		}
+	}
+	if len(empty) > 0 {
+		r.Status = Warn
+		r.Detail += ", no free hugepages on node " + strings.Join(empty, ", ")
+		r.Fix = "reserve hugepages on the node, echo 1024 > " +
+			"/sys/devices/system/node/node<N>/hugepages/hugepages-2048kB/nr_hugepages"
+	}
+	return r
+}
+
+// nodeCPUs returns the lcores of each NUMA node
+func (c *Checker) nodeCPUs() map[int][]uint16 {
+	return cpudata.ReadNodes(c.SysfsRoot)
+}
+
+// CheckNUMA checks the lcores are on the NUMA nodes of the port devices
+func (c *Checker) CheckNUMA() Result {
+
+	r := Result{Name: "NIC NUMA"}
+
+	if len(c.Devices) == 0 {
+		r.Status = Warn
+		r.Detail = "no port devices"
+		r.Fix = "set the pci address of the ports or bind the devices to " + strings.Join(c.Modules, " or ")
+		return r
+	}
+
+	nodes := c.nodeCPUs()
+	lcoreNode := make(map[uint16]int)
+	for node, lcores := range nodes {
+		for _, l := range lcores {
+			lcoreNode[l] = node
+		}
+	}
+	used := make(map[int]bool)
+	for _, l := range c.Lcores {
+		if node, ok := lcoreNode[l]; ok {
+			used[node] = true
+		}
+	}
+
+	found := []string{}
+	remote := []string{}
+	fixes := []string{}
+	for _, slot := range c.Devices {
+		node := c.deviceNode(slot)
+		if node < 0 {
+			found = append(found, slot+" no NUMA node")
+			continue
+		}
+		found = append(found, fmt.Sprintf("%s node %d", slot, node))
+		if len(c.Lcores) > 0 && len(nodes) > 1 && !used[node] {
+			remote = append(remote, fmt.Sprintf("%s on node %d", slot, node))
+			if len(nodes[node]) == 0 {
+				fixes = append(fixes, fmt.Sprintf("lcores of node %d for %s", node, slot))
+			} else {
+				fixes = append(fixes, fmt.Sprintf("lcores %s for %s", cpudata.FormatList(nodes[node]), slot))
+			}
+		}
+	}
+
+	if len(remote) > 0 {
+		r.Status = Warn
+		r.Detail = "no lcores on the node of " + strings.Join(remote, ", ")
+		r.Fix = "use " + strings.Join(fixes, ", ")
+		return r
+	}
+	r.Detail = strings.Join(found, ", ")
+
+	return r
+}
@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2022 Intel Corporation
+
+package preflight
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes the files below root
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, value := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(value), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// tunedHost is a two node host with lcores 0-3 on node 0 and 4-7 on node 1,
+// lcore N and N+2 are hyperthread siblings of a node.
+var tunedHost = map[string]string{
+	"proc/cmdline": "BOOT_IMAGE=/vmlinuz ro intel_iommu=on iommu=pt " +
+		"isolcpus=managed_irq,domain,4-7 nohz_full=4-7 rcu_nocbs=4-7\n",
+	"proc/mounts":                                                             "hugetlbfs /dev/hugepages hugetlbfs rw,relatime,pagesize=2M 0 0\n",
+	"sys/kernel/iommu_groups/0/type":                                          "identity\n",
+	"sys/kernel/iommu_groups/1/type":                                          "identity\n",
+	"sys/module/vfio_pci/refcnt":                                              "0\n",
+	"sys/devices/system/cpu/online":                                           "0-7\n",
+	"sys/devices/system/node/node0/cpulist":                                   "0-3\n",
+	"sys/devices/system/node/node1/cpulist":                                   "4-7\n",
+	"sys/bus/pci/devices/0000:18:00.0/numa_node":                              "1\n",
+	"sys/bus/pci/devices/0000:18:00.1/numa_node":                              "1\n",
+	"sys/kernel/mm/hugepages/hugepages-2048kB/nr_hugepages":                   "1024\n",
+	"sys/kernel/mm/hugepages/hugepages-2048kB/free_hugepages":                 "1024\n",
+	"sys/devices/system/node/node0/hugepages/hugepages-2048kB/nr_hugepages":   "512\n",
+	"sys/devices/system/node/node0/hugepages/hugepages-2048kB/free_hugepages": "512\n",
+	"sys/devices/system/node/node1/hugepages/hugepages-2048kB/nr_hugepages":   "512\n",
+	"sys/devices/system/node/node1/hugepages/hugepages-2048kB/free_hugepages": "512\n",
+}
+
+// newHost writes the host files with the changes, an empty change removes
+// the file, and returns a checker of the host
+func newHost(t *testing.T, changes map[string]string) *Checker {
+	t.Helper()
+
+	files := make(map[string]string)
+	for name, value := range tunedHost {
+		files[name] = value
+	}
+	for name, value := range changes {
+		if len(value) == 0 {
+			delete(files, name)
+		} else {
+			files[name] = value
+		}
+	}
+	for l := 0; l < 8; l++ {
+		cpu := fmt.Sprintf("sys/devices/system/cpu/cpu%d", l)
+		if _, ok := files[cpu+"/cpufreq/scaling_governor"]; !ok {
+			files[cpu+"/cpufreq/scaling_governor"] = "performance\n"
+		}
+		first := l/4*4 + l%2
+		files[cpu+"/topology/thread_siblings_list"] = fmt.Sprintf("%d,%d\n", first, first+2)
+	}
+
+	root := t.TempDir()
+	writeFiles(t, root, files)
+
+	c := New(filepath.Join(root, "sys"), filepath.Join(root, "proc"))
+	c.Lcores = []uint16{4, 5}
+	c.Modules = []string{"vfio-pci", "igb_uio"}
+	c.Devices = []string{"0000:18:00.0", "0000:18:00.1"}
+
+	return c
+}
+
+func TestRunTuned(t *testing.T) {
+
+	results := newHost(t, nil).Run()
+	if len(results) != 9 {
+		t.Fatalf("got %d results, want 9", len(results))
+	}
+	for _, r := range results {
+		if r.Status != Pass {
+			t.Errorf("%s: %s %s, fix %q", r.Name, r.Status, r.Detail, r.Fix)
+		}
+	}
+	if w := Worst(results); w != Pass {
+		t.Errorf("worst %s, want PASS", w)
+	}
+}
+
+func TestRunUntuned(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		changes map[string]string
+		check   func(c *Checker) Result
+		status  Status
+		fix     string
+	}{
+		{"no iommu", map[string]string{
+			"sys/kernel/iommu_groups/0/type": "",
+			"sys/kernel/iommu_groups/1/type": "",
+		}, (*Checker).CheckIOMMU, Fail, "intel_iommu=on"},
+		{"no passthrough", map[string]string{
+			"proc/cmdline": "ro isolcpus=4-7 nohz_full=4-7 rcu_nocbs=4-7\n",
+		}, (*Checker).CheckIOMMU, Warn, "iommu=pt"},
+		{"noiommu mode", map[string]string{
+			"sys/kernel/iommu_groups/0/type":                        "",
+			"sys/kernel/iommu_groups/1/type":                        "",
+			"sys/module/vfio/parameters/enable_unsafe_noiommu_mode": "Y\n",
+		}, (*Checker).CheckIOMMU, Warn, "intel_iommu=on"},
+		{"other module", map[string]string{
+			"sys/module/vfio_pci/refcnt":       "",
+			"sys/bus/pci/drivers/igb_uio/bind": "\n",
+		}, (*Checker).CheckModules, Warn, "modprobe vfio-pci"},
+		{"no module", map[string]string{
+			"sys/module/vfio_pci/refcnt": "",
+		}, (*Checker).CheckModules, Fail, "modprobe vfio-pci"},
+		{"no isolcpus", map[string]string{
+			"proc/cmdline": "ro iommu=pt nohz_full=4-7 rcu_nocbs=4-7\n",
+		}, func(c *Checker) Result { return c.CheckCmdline("isolcpus") }, Warn, "isolcpus=4-5"},
+		{"lcore not in nohz_full", map[string]string{
+			"proc/cmdline": "ro iommu=pt isolcpus=4-7 nohz_full=5-7 rcu_nocbs=4-7\n",
+		}, func(c *Checker) Result { return c.CheckCmdline("nohz_full") }, Warn, "lcores 4 to nohz_full"},
+		{"powersave", map[string]string{
+			"sys/devices/system/cpu/cpu5/cpufreq/scaling_governor": "powersave\n",
+		}, (*Checker).CheckGovernor, Warn, "cpupower -c 5 "},
+		{"busy sibling", map[string]string{
+			"proc/cmdline": "ro iommu=pt isolcpus=4-5 nohz_full=4-7 rcu_nocbs=4-7\n",
+		}, (*Checker).CheckSiblings, Warn, "add 6-7 to isolcpus"},
+		{"no hugepages", map[string]string{
+			"sys/kernel/mm/hugepages/hugepages-2048kB/nr_hugepages": "0\n",
+		}, (*Checker).CheckHugepages, Fail, "nr_hugepages"},
+		{"no hugetlbfs", map[string]string{
+			"proc/mounts": "sysfs /sys sysfs rw 0 0\n",
+		}, (*Checker).CheckHugepages, Fail, "mount -t hugetlbfs"},
+		{"no node hugepages", map[string]string{
+			"sys/devices/system/node/node1/hugepages/hugepages-2048kB/free_hugepages": "0\n",
+		}, (*Checker).CheckHugepages, Warn, "node<N>"},
+		{"remote nic", map[string]string{
+			"sys/bus/pci/devices/0000:18:00.1/numa_node": "0\n",
+		}, (*Checker).CheckNUMA, Warn, "lcores 0-3 for 0000:18:00.1"},
+	}
+
+	for _, tt := range tests {
+		r := tt.check(newHost(t, tt.changes))
+		if r.Status != tt.status || !strings.Contains(r.Fix, tt.fix) {
+			t.Errorf("%s: got %s %s, fix %q, want %s with %q", tt.name, r.Status, r.Detail, r.Fix, tt.status, tt.fix)
+		}
+	}
+}
+
+func TestSiblingsShareCore(t *testing.T) {
+
+	c := newHost(t, nil)
+	c.Lcores = []uint16{4, 6}
+
+	if r := c.CheckSiblings(); r.Status != Warn || !strings.Contains(r.Detail, "4/6") {
+		t.Errorf("got %s %s, want lcores 4/6 sharing a core", r.Status, r.Detail)
+	}
+
+	c.Lcores = nil
+	if r := c.CheckSiblings(); r.Status != Warn {
+		t.Errorf("got %s without lcores, want WARN", r.Status)
+	}
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+
+	"github.com/KeithWiles/go-pktgen/pkgs/keybind"
 )
 
 var (
@@ -32,13 +34,19 @@ type Tab struct {
 	Application   *tview.Application
 }
 
-// New information object
+// New information object, the Tab and Backtab keys are registered in the
+// key bindings of the panel scope, the name of the tab order.
 func New(name string, application *tview.Application) *Tab {
+
+	keybind.Add(name, tcell.KeyTab, "Select the next window")
+	keybind.Add(name, tcell.KeyBacktab, "Select the previous window")
+
 	return &Tab{Name: name, Application: application}
 }
 
-// Add to the given list of windows
-func (to *Tab) Add(name string, w interface{}, key interface{}) (*TabInfo, error) {
+// Add to the given list of windows, the key selecting the window is
+// registered in the key bindings of the panel with the optional description.
+func (to *Tab) Add(name string, w interface{}, key interface{}, desc ...string) (*TabInfo, error) {
 	if to == nil {
 		return nil, fmt.Errorf("invalid tabOrder pointer")
 	}
@@ -52,6 +60,14 @@ func (to *Tab) Add(name string, w interface{}, key interface{}) (*TabInfo, error
 		case rune:
 			tabInfo.EKey = tcell.NewEventKey(tcell.KeyRune, k, tcell.ModNone)
 		}
+
+		d := fmt.Sprintf("Select the %s window", name)
+		if len(desc) > 0 {
+			d = desc[0]
+		}
+		keybind.Add(to.Name, key, d, func() {
+			to.SetInputFocus(key)
+		})
 	}
 
 	tabInfo.Index = len(to.TabList)
@@ -77,6 +93,13 @@ func (to *Tab) SetHighlightBorderColor(color tcell.Color) {
 	highlightBorderColor = color
 }
 
+// SetBorderColors of the non-selected and selected windows of all panels,
+// the colors must be set before the windows are added.
+func SetBorderColors(normal, highlight tcell.Color) {
+	defaultBorderColor = normal
+	highlightBorderColor = highlight
+}
+
 // setFocus to the tview primitive
 func (to *Tab) setFocus(a interface{}) {
 
@@ -150,8 +173,31 @@ func (to *Tab) SetInputFocus(key interface{}) {
 	}
 }
 
+// Current returns the window with the input focus
+func (to *Tab) Current() *TabInfo {
+
+	if to.CurrentIndex >= len(to.TabList) {
+		return nil
+	}
+	return to.TabList[to.CurrentIndex]
+}
+
+// Scope returns the key binding scope of the window with the input focus
+func (to *Tab) Scope() string {
+
+	if tab := to.Current(); tab != nil {
+		return keybind.Scope(to.Name, tab.Name)
+	}
+	return to.Name
+}
+
+// SetCurrentInputFocus sets the focus back to the window with the focus
 func (to *Tab) SetCurrentInputFocus() {
-	to.SetInputFocus(to.TabList[to.CurrentIndex].EKey)
+
+	if tab := to.Current(); tab != nil {
+		to.setFocus(tab.View)
+		to.colorBorder(tab.View, highlightBorderColor)
+	}
 }
 
 // doDone key handling for Tab and Backtab
@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2019-2022 Intel Corporation
+
+package ttylog
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMemorySize is the number of log entries kept in memory
+const DefaultMemorySize = 2000
+
+// Level of a log entry
+type Level int
+
+// Level values, from the least to the most severe
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+var levelNames = []string{"Debug", "Info", "Warn", "Error", "Fatal"}
+
+// String returns the name of the level
+func (l Level) String() string {
+
+	if l < LevelDebug || l > LevelFatal {
+		return "Unknown"
+	}
+	return levelNames[l]
+}
+
+// ParseLevel returns the level of a level name, the case is ignored
+func ParseLevel(name string) (Level, error) {
+
+	for i, n := range levelNames {
+		if strings.EqualFold(n, name) {
+			return Level(i), nil
+		}
+	}
+	return LevelDebug, fmt.Errorf("unknown log level %s", name)
+}
+
+// Levels returns all levels from the least to the most severe
+func Levels() []Level {
+	return []Level{LevelDebug, LevelInfo, LevelWarn, LevelError, LevelFatal}
+}
+
+// Entry is a log message kept in memory
+type Entry struct {
+	Time  time.Time
+	ID    string
+	Level Level
+	Text  string
+}
+
+// memory is the in-memory sink, a bounded ring of the newest log entries
+type memory struct {
+	lock    sync.Mutex
+	size    int
+	head    int // Index of the oldest entry when the ring is full
+	seq     uint64
+	entries []Entry
+}
+
+var mem = &memory{size: DefaultMemorySize}
+
+// idLevel returns the level of the messages logged with a log id
+func idLevel(id string) Level {
+
+	switch id {
+	case FatalLog:
+		return LevelFatal
+	case ErrorLog:
+		return LevelError
+	case WarnLog:
+		return LevelWarn
+	case DebugLog:
+		return LevelDebug
+	}
+	return LevelInfo
+}
+
+// Write adds a log entry to the memory ring
+func (m *memory) Write(e Entry) error {
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.seq++
+	if len(m.entries) < m.size {
+		m.entries = append(m.entries, e)
+		return nil
+	}
+	m.entries[m.head] = e
+	m.head = (m.head + 1) % m.size
+
+	return nil
+}
+
+// Close of the memory ring keeps the entries
+func (m *memory) Close() error {
+	return nil
+}
+
+// SetMemorySize sets the number of log entries kept in memory, the newest
+// entries are kept when the size is reduced.
+func SetMemorySize(size int) {
+
+	if size <= 0 {
+		size = DefaultMemorySize
+	}
+
+	entries := Entries()
+	if len(entries) > size {
+		entries = entries[len(entries)-size:]
+	}
+
+	mem.lock.Lock()
+	defer mem.lock.Unlock()
+
+	mem.size = size
+	mem.head = 0
+	mem.entries = append(make([]Entry, 0, len(entries)), entries...)
+}
+
+// Entries returns a copy of the log entries kept in memory, oldest first
+func Entries() []Entry {
+
+	mem.lock.Lock()
+	defer mem.lock.Unlock()
+
+	entries := make([]Entry, len(mem.entries))
+	for i := range entries {
+		entries[i] = mem.entries[(mem.head+i)%len(mem.entries)]
+	}
+	return entries
+}
+
+// Sequence returns the number of entries logged so far, a change of the
+// sequence means new entries are in memory.
+func Sequence() uint64 {
+
+	mem.lock.Lock()
+	defer mem.lock.Unlock()
+
+	return mem.seq
+}
+
+// ClearEntries removes all log entries kept in memory
+func ClearEntries() {
+
+	mem.lock.Lock()
+	defer mem.lock.Unlock()
+
+	mem.entries = mem.entries[:0]
+	mem.head = 0
+	mem.seq++
+}
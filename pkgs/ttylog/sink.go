@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2019-2022 Intel Corporation
+
+package ttylog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// Sink receives the log entries, the logger serializes the calls to Write
+// so a sink does not need its own locking.
+type Sink interface {
+	Write(e Entry) error
+	Close() error
+}
+
+// Format of the log entries written by a sink
+type Format int
+
+// Format values
+const (
+	// FormatPlain writes the message text only, the level is a prefix
+	FormatPlain Format = iota
+	// FormatText writes the time, level, log id and message text
+	FormatText
+	// FormatJSON writes one JSON object per line
+	FormatJSON
+)
+
+// jsonEntry is the JSON lines form of a log entry
+type jsonEntry struct {
+	Time  string `json:"time"`
+	Level string `json:"level"`
+	ID    string `json:"id"`
+	Msg   string `json:"msg"`
+}
+
+// levelPrefix returns the prefix of the plain format of the level log ids
+func levelPrefix(e Entry) string {
+
+	switch e.ID {
+	case FatalLog:
+		return "Fatal: "
+	case ErrorLog:
+		return "Error: "
+	case WarnLog:
+		return "Warning: "
+	case InfoLog:
+		return "Info: "
+	case DebugLog:
+		return "Debug: "
+	}
+	return ""
+}
+
+// FormatEntry returns the log entry in the format as one line
+func FormatEntry(e Entry, format Format) []byte {
+
+	switch format {
+	case FormatJSON:
+		b, err := json.Marshal(jsonEntry{
+			Time:  e.Time.Format(time.RFC3339Nano),
+			Level: e.Level.String(),
+			ID:    e.ID,
+			Msg:   e.Text,
+		})
+		if err != nil {
+			return nil
+		}
+		return append(b, '\n')
+
+	case FormatText:
+		return []byte(fmt.Sprintf("%s %-5s %s %s\n",
+			e.Time.Format("2006-01-02 15:04:05.000"), e.Level, e.ID, e.Text))
+	}
+	return []byte(levelPrefix(e) + e.Text + "\n")
+}
+
+// WriterSink writes the log entries to a writer like a tty
+type WriterSink struct {
+	w      io.Writer
+	format Format
+}
+
+// NewWriterSink returns a sink writing the entries in the format to w, the
+// writer is closed with the sink if it is an io.Closer.
+func NewWriterSink(w io.Writer, format Format) *WriterSink {
+	return &WriterSink{w: w, format: format}
+}
+
+// Write the log entry
+func (ws *WriterSink) Write(e Entry) error {
+
+	_, err := ws.w.Write(FormatEntry(e, ws.format))
+
+	return err
+}
+
+// Close the writer
+func (ws *WriterSink) Close() error {
+
+	if c, ok := ws.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// DefaultQueueSize is the number of entries queued by an AsyncSink
+const DefaultQueueSize = 256
+
+// asyncCloseTimeout is the time Close waits for the queued entries
+const asyncCloseTimeout = time.Second
+
+// AsyncSink writes the log entries to another sink from its own goroutine,
+// so a slow sink like a stalled tty does not block the logger. The entries
+// logged while the queue is full are dropped and counted.
+type AsyncSink struct {
+	sink    Sink
+	out     chan Entry
+	done    chan bool
+	dropped uint64
+}
+
+// NewAsyncSink returns a sink queueing up to size entries for sink
+func NewAsyncSink(sink Sink, size int) *AsyncSink {
+
+	as := &AsyncSink{
+		sink: sink,
+		out:  make(chan Entry, size),
+		done: make(chan bool),
+	}
+
+	go as.logger()
+
+	return as
+}
+
+// logger writes the queued entries to the sink until the queue is closed
+func (as *AsyncSink) logger() {
+
+	for e := range as.out {
+		as.sink.Write(e)
+	}
+	close(as.done)
+}
+
+// Write queues the log entry, it fails if the queue is full
+func (as *AsyncSink) Write(e Entry) error {
+
+	select {
+	case as.out <- e:
+		return nil
+	default:
+		atomic.AddUint64(&as.dropped, 1)
+		return fmt.Errorf("log queue full, entry dropped")
+	}
+}
+
+// Dropped returns the number of entries dropped while the queue was full
+func (as *AsyncSink) Dropped() uint64 {
+	return atomic.LoadUint64(&as.dropped)
+}
+
+// Close waits a short time for the queued entries to be written and closes
+// the sink, a write blocked on the sink is not waited for.
+func (as *AsyncSink) Close() error {
+
+	close(as.out)
+
+	select {
+	case <-as.done:
+	case <-time.After(asyncCloseTimeout):
+	}
+	return as.sink.Close()
+}
+
+// FileSink writes the log entries to a file, the file is rotated once it
+// grows beyond the maximum size. The rotated files are named path.1 being
+// the newest up to path.N being the oldest.
+type FileSink struct {
+	path       string
+	format     Format
+	maxSize    int64
+	maxBackups int
+	size       int64
+	fd         *os.File
+}
+
+// NewFileSink opens or creates the log file at path, a maxSize of zero
+// never rotates the file and maxBackups is the number of rotated files kept.
+func NewFileSink(path string, format Format, maxSize int64, maxBackups int) (*FileSink, error) {
+
+	fs := &FileSink{
+		path:       path,
+		format:     format,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+
+	if err := fs.open(); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+// open the log file for appending
+func (fs *FileSink) open() error {
+
+	fd, err := os.OpenFile(fs.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to open log file: %w", err)
+	}
+
+	info, err := fd.Stat()
+	if err != nil {
+		fd.Close()
+		return fmt.Errorf("unable to stat log file: %w", err)
+	}
+
+	fs.fd = fd
+	fs.size = info.Size()
+
+	return nil
+}
+
+// rotate renames the log files one up, dropping the oldest, and opens a
+// new log file. The current file stays open until the new one is opened, on
+// an error the entries keep going to it and the next rotation is tried once
+// it has grown by another maxSize bytes.
+func (fs *FileSink) rotate() error {
+
+	if fs.maxBackups == 0 {
+		fs.size = 0
+		return fs.fd.Truncate(0)
+	}
+
+	os.Remove(fmt.Sprintf("%s.%d", fs.path, fs.maxBackups))
+	for i := fs.maxBackups - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", fs.path, i), fmt.Sprintf("%s.%d", fs.path, i+1))
+	}
+	if err := os.Rename(fs.path, fs.path+".1"); err != nil {
+		fs.size = 0
+		return err
+	}
+
+	old := fs.fd
+	if err := fs.open(); err != nil {
+		fs.size = 0
+		return err
+	}
+	old.Close()
+
+	return nil
+}
+
+// Write the log entry, rotating the file first when the entry does not fit.
+// The entry is written even if the rotation fails.
+func (fs *FileSink) Write(e Entry) error {
+
+	b := FormatEntry(e, fs.format)
+
+	var rerr error
+	if fs.maxSize > 0 && fs.size > 0 && fs.size+int64(len(b)) > fs.maxSize {
+		rerr = fs.rotate()
+	}
+
+	n, err := fs.fd.Write(b)
+	fs.size += int64(n)
+
+	if err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// Close the log file
+func (fs *FileSink) Close() error {
+	return fs.fd.Close()
+}
@@ -6,20 +6,31 @@ package ttylog
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
+// ttylog logs the messages of registered log ids to a set of sinks. The
+// in-memory sink is always present, a tty and log files are added with Open
+// and OpenFile. A message is logged when its level is at least the level set
+// with SetLevel. The in-memory sink keeps the messages of all log ids, the
+// other sinks only get the messages of the active log ids. All functions are
+// safe for concurrent use.
+
 // LogStates map of log id states
 type LogStates map[string]bool
 
 // TTYLog - Log tty information
 type TTYLog struct {
-	tty    string
-	fd     *os.File
-	out    chan string
-	done   chan bool
-	states LogStates
+	lock     sync.RWMutex // Lock for the states and level
+	tty      string
+	states   LogStates
+	level    Level
+	sinkLock sync.Mutex // Lock for the sinks, serializes the writes
+	sinks    map[string]Sink
+	names    []string // Sink names in the order added
 }
 
 var tlog *TTYLog
@@ -35,33 +46,120 @@ const (
 	InfoLog string = "InfoLog"
 	// DebugLog for normal information
 	DebugLog string = "DebugLog"
+
+	// MemorySink is the name of the in-memory sink
+	MemorySink string = "memory"
+	// TTYSink is the name of the tty sink added by Open
+	TTYSink string = "tty"
+	// FileSinkName is the name of the file sink added by OpenFile
+	FileSinkName string = "file"
 )
 
 func init() {
 	tlog = new(TTYLog)
 	tlog.states = make(LogStates)
+	tlog.level = LevelDebug
 
 	tlog.states[FatalLog] = true
 	tlog.states[ErrorLog] = true
 	tlog.states[WarnLog] = true
 	tlog.states[InfoLog] = true
 	tlog.states[DebugLog] = false
+
+	tlog.sinks = make(map[string]Sink)
+	AddSink(MemorySink, mem)
 }
 
-// logger go function to log data to tty
-func logger() {
+// output a log message to the sinks if the level is enabled, the message of
+// an inactive log id only goes to the in-memory sink
+func output(id string, level Level, s string) {
+
+	if level < GetLevel() {
+		return
+	}
+	active := IsActive(id)
+
+	e := Entry{
+		Time:  time.Now(),
+		ID:    id,
+		Level: level,
+		Text:  strings.Trim(s, "\n"),
+	}
 
-ForLoop:
-	for {
-		select {
-		case <-tlog.done: // Quit
-			break ForLoop
-		case str := <-tlog.out:
-			fmt.Fprintf(tlog.fd, "%s", str)
+	tlog.sinkLock.Lock()
+	defer tlog.sinkLock.Unlock()
+
+	for _, name := range tlog.names {
+		if active || name == MemorySink {
+			tlog.sinks[name].Write(e)
 		}
 	}
 }
 
+// AddSink adds a sink receiving all logged entries, a sink with the same
+// name is closed and replaced.
+func AddSink(name string, sink Sink) {
+
+	tlog.sinkLock.Lock()
+	defer tlog.sinkLock.Unlock()
+
+	if old, ok := tlog.sinks[name]; ok {
+		old.Close()
+	} else {
+		tlog.names = append(tlog.names, name)
+	}
+	tlog.sinks[name] = sink
+}
+
+// RemoveSink closes and removes a sink
+func RemoveSink(name string) error {
+
+	tlog.sinkLock.Lock()
+	defer tlog.sinkLock.Unlock()
+
+	sink, ok := tlog.sinks[name]
+	if !ok {
+		return fmt.Errorf("unknown sink %s", name)
+	}
+	delete(tlog.sinks, name)
+
+	for i, n := range tlog.names {
+		if n == name {
+			tlog.names = append(tlog.names[:i], tlog.names[i+1:]...)
+			break
+		}
+	}
+	return sink.Close()
+}
+
+// HasSink returns true if a sink with the name was added
+func HasSink(name string) bool {
+
+	tlog.sinkLock.Lock()
+	defer tlog.sinkLock.Unlock()
+
+	_, ok := tlog.sinks[name]
+	return ok
+}
+
+// SetLevel sets the lowest level of the logged messages
+func SetLevel(level Level) {
+
+	tlog.lock.Lock()
+	defer tlog.lock.Unlock()
+
+	tlog.level = level
+}
+
+// GetLevel returns the lowest level of the logged messages
+func GetLevel() Level {
+
+	tlog.lock.RLock()
+	defer tlog.lock.RUnlock()
+
+	return tlog.level
+}
+
 // Register is a function to register new logging type strings
 func Register(id string, state ...bool) {
 
@@ -70,12 +168,18 @@ func Register(id string, state ...bool) {
 		flg = state[0]
 	}
 
+	tlog.lock.Lock()
+	defer tlog.lock.Unlock()
+
 	tlog.states[id] = flg
 }
 
 // Delete a log id
 func Delete(id string) error {
 
+	tlog.lock.Lock()
+	defer tlog.lock.Unlock()
+
 	_, ok := tlog.states[id]
 	if ok {
 		delete(tlog.states, id)
@@ -88,6 +192,9 @@ func Delete(id string) error {
 // State is a function to return the current logid state
 func State(id string) (bool, error) {
 
+	tlog.lock.RLock()
+	defer tlog.lock.RUnlock()
+
 	state, ok := tlog.states[id]
 	if !ok {
 		return false, fmt.Errorf("unknown logid %s", id)
@@ -97,48 +204,63 @@ func State(id string) (bool, error) {
 
 // SetState on a logid
 func SetState(id string, state bool) error {
-	state, ok := tlog.states[id]
-	if !ok {
+
+	tlog.lock.Lock()
+	defer tlog.lock.Unlock()
+
+	if _, ok := tlog.states[id]; !ok {
 		return fmt.Errorf("unknown logid %s", id)
 	}
 	tlog.states[id] = state
 	return nil
 }
 
-// IsInited - return true if tty open and channels are active
+// IsInited - return true if the tty is open
 func IsInited() bool {
-
-	if tlog.fd == nil || tlog.out == nil || tlog.done == nil {
-		return false
-	}
-	return true
+	return HasSink(TTYSink)
 }
 
 // IsActive - return true if log type id is true else false
 func IsActive(id string) bool {
 
-	state, ok := tlog.states[id]
-	if !ok {
-		return false
-	}
+	tlog.lock.RLock()
+	defer tlog.lock.RUnlock()
 
-	return state
+	return tlog.states[id]
 }
 
-// GetList returns the list of states and log ids
+// GetList returns a copy of the list of states and log ids
 func GetList() LogStates {
 
-	return tlog.states
+	tlog.lock.RLock()
+	defer tlog.lock.RUnlock()
+
+	states := make(LogStates, len(tlog.states))
+	for id, state := range tlog.states {
+		states[id] = state
+	}
+	return states
 }
 
-// FatalPrintf to print out fatal error messages
-func FatalPrintf(format string, a ...interface{}) (err error) {
-	if !IsInited() {
-		return fmt.Errorf("tty or channel is not inited")
+// IDs returns the registered log ids sorted by name
+func IDs() []string {
+
+	tlog.lock.RLock()
+	defer tlog.lock.RUnlock()
+
+	ids := make([]string, 0, len(tlog.states))
+	for id := range tlog.states {
+		ids = append(ids, id)
 	}
+	sort.Strings(ids)
 
-	s := fmt.Sprintf("Fatal: "+format, a...)
-	tlog.out <- s
+	return ids
+}
+
+// FatalPrintf to print out fatal error messages
+func FatalPrintf(format string, a ...interface{}) (err error) {
+	s := fmt.Sprintf(format, a...)
+	output(FatalLog, LevelFatal, s)
 	os.Exit(1)
 
 	return nil
@@ -146,75 +268,56 @@ func FatalPrintf(format string, a ...interface{}) (err error) {
 
 // ErrorPrintf to print out error messages
 func ErrorPrintf(format string, a ...interface{}) (err error) {
-	if !IsInited() {
-		return fmt.Errorf("tty or channel is not inited")
-	}
-
-	if IsActive(ErrorLog) {
-		s := fmt.Sprintf("Error: "+format, a...)
-		tlog.out <- s
-	}
+	s := fmt.Sprintf(format, a...)
+	output(ErrorLog, LevelError, s)
 
 	return nil
 }
 
 // WarnPrintf to print out warning messages
 func WarnPrintf(format string, a ...interface{}) (err error) {
-	if !IsInited() {
-		return fmt.Errorf("tty or channel is not inited")
-	}
-
-	if IsActive(WarnLog) {
-		s := fmt.Sprintf("Warning: "+format, a...)
-		tlog.out <- s
-	}
+	s := fmt.Sprintf(format, a...)
+	output(WarnLog, LevelWarn, s)
 
 	return nil
 }
 
 // InfoPrintf to print out informational messages
 func InfoPrintf(format string, a ...interface{}) (err error) {
-	if !IsInited() {
-		return fmt.Errorf("tty or channel is not inited")
-	}
-
-	if IsActive(InfoLog) {
-		s := fmt.Sprintf("Info: "+format, a...)
-		tlog.out <- s
-	}
+	s := fmt.Sprintf(format, a...)
+	output(InfoLog, LevelInfo, s)
 
 	return nil
 }
 
 // DebugPrintf to print out informational messages
 func DebugPrintf(format string, a ...interface{}) (err error) {
-	if !IsInited() {
-		return fmt.Errorf("tty or channel is not inited")
-	}
-
-	if IsActive(DebugLog) {
-		s := fmt.Sprintf("Debug: "+format, a...)
-		tlog.out <- s
-	}
+	s := fmt.Sprintf(format, a...)
+	output(DebugLog, LevelDebug, s)
 
 	return nil
 }
 
-// Log - output using printf like routine
+// Log - output using printf like routine, the level of the message is the
+// level of the log id, Info for the registered log ids.
 func Log(id string, format string, a ...interface{}) (n int, err error) {
-	if !IsInited() {
-		return 0, fmt.Errorf("tty or channel is not inited")
-	}
+	return LogLevel(id, idLevel(id), format, a...)
+}
 
-	if IsActive(id) {
-		s := fmt.Sprintf(format, a...)
-		tlog.out <- s
-		if id == FatalLog {
-			os.Exit(1)
-		}
-		return len(s), nil
+// LogLevel - output using printf like routine at the given level, the message
+// of an inactive log id is only kept in memory and an error is returned
+func LogLevel(id string, level Level, format string, a ...interface{}) (n int, err error) {
+
+	s := fmt.Sprintf(format, a...)
+	output(id, level, s)
+
+	if !IsActive(id) {
+		return 0, fmt.Errorf("log id %s is not active", id)
 	}
-	return 0, fmt.Errorf("tty or channel is not active")
+	if level == LevelFatal {
+		os.Exit(1)
+	}
+	return len(s), nil
 }
 
 // Print a fmt.Print like function for verbose output
@@ -234,17 +337,15 @@ func Printf(id string, format string, a ...interface{}) (n int, err error) {
 
 // DoPrintf - output using printf like format without leading text and checks
 func DoPrintf(format string, a ...interface{}) (n int, err error) {
-	if !IsInited() {
-		return 0, fmt.Errorf("tty or channel is not inited")
-	}
 
 	s := fmt.Sprintf(format, a...)
-	tlog.out <- s
+	output(InfoLog, LevelInfo, s)
 
 	return len(s), nil
 }
 
-// Open - Open the tty and channel
+// Open - Open the tty, the messages are written to the tty in the plain
+// format from a queue so a stalled tty does not block the logger.
 func Open(w ...interface{}) error {
 
 	tty := "0"
@@ -252,61 +353,62 @@ func Open(w ...interface{}) error {
 		tty = w[0].(string)
 	}
 
-	// Test if arg is valid or we have not set tlog.tty
-	if len(tty) == 0 && len(tlog.tty) == 0 {
+	if len(tty) == 0 {
 		tty = "0"
 	}
 
-	if !IsInited() {
-		// Update tlog.tty if empty or tty has changed
-		if len(tlog.tty) == 0 || tty != tlog.tty {
-			tlog.tty = tty
-		}
-		tlog.out = make(chan string)
-		tlog.done = make(chan bool)
+	if IsInited() {
+		return nil
+	}
 
-		if strings.Contains(tty, "/dev/") == false {
-			tty = "/dev/pts/" + tty
-		}
-		tlog.tty = tty
+	if strings.Contains(tty, "/dev/") == false {
+		tty = "/dev/pts/" + tty
+	}
 
-		fd, err := os.OpenFile(tlog.tty, os.O_RDWR, 0755)
-		if err != nil {
-			fmt.Printf("Unable to open tty (%v)\n", tlog.tty)
-			return fmt.Errorf("unable to open tty")
-		}
-		tlog.fd = fd
+	fd, err := os.OpenFile(tty, os.O_RDWR, 0755)
+	if err != nil {
+		fmt.Printf("Unable to open tty (%v)\n", tty)
+		return fmt.Errorf("unable to open tty")
+	}
 
-		go logger()
+	tlog.lock.Lock()
+	tlog.tty = tty
+	tlog.lock.Unlock()
 
-		return nil
-	}
+	AddSink(TTYSink, NewAsyncSink(NewWriterSink(fd, FormatPlain), DefaultQueueSize))
 
 	return nil
 }
 
-// Close - close the channel and fd
-func Close() {
+// OpenFile - Open the log file, the file is rotated when it grows beyond
+// maxSize bytes keeping maxBackups rotated files.
+func OpenFile(path string, format Format, maxSize int64, maxBackups int) error {
 
-	if tlog.fd != nil {
-		tlog.fd.Close()
-		tlog.fd = nil
+	fs, err := NewFileSink(path, format, maxSize, maxBackups)
+	if err != nil {
+		return err
 	}
+	AddSink(FileSinkName, fs)
 
-	if tlog.out != nil {
-		tlog.done <- true
+	return nil
+}
 
-		time.Sleep(1)
+// Close - close the tty and the other sinks, the in-memory sink stays
+func Close() {
 
-		close(tlog.out)
-		tlog.out = nil
+	tlog.sinkLock.Lock()
+	names := append([]string{}, tlog.names...)
+	tlog.sinkLock.Unlock()
 
-		close(tlog.done)
-		tlog.done = nil
+	for _, name := range names {
+		if name != MemorySink {
+			RemoveSink(name)
+		}
 	}
 
+	tlog.lock.Lock()
 	tlog.tty = ""
-	tlog.states = make(LogStates)
+	tlog.lock.Unlock()
 }
 
 // HexDump the data to the ttylog
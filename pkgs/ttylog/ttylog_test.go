@@ -4,7 +4,14 @@
 package ttylog
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	"testing"
 )
@@ -17,3 +24,230 @@ func TestClose(t *testing.T) {
 	fmt.Printf("Close ttylog\n")
 
 }
+
+func TestSetState(t *testing.T) {
+	Register("testStateLog")
+
+	if err := SetState("testStateLog", true); err != nil {
+		t.Fatalf("SetState: %v", err)
+	}
+	if state, _ := State("testStateLog"); !state {
+		t.Errorf("state not set")
+	}
+	if err := SetState("unknownLog", true); err == nil {
+		t.Errorf("SetState of an unknown id did not fail")
+	}
+}
+
+func TestMemory(t *testing.T) {
+	Register("testMemLog", true)
+	Register("testMemOffLog")
+	ClearEntries()
+	SetMemorySize(3)
+	defer SetMemorySize(DefaultMemorySize)
+
+	var b bytes.Buffer
+	AddSink("testMemWriter", NewWriterSink(&b, FormatPlain))
+	defer RemoveSink("testMemWriter")
+
+	seq := Sequence()
+	for i := 0; i < 5; i++ {
+		Log("testMemLog", "line %d\n", i)
+	}
+	Log("testMemOffLog", "memory only\n")
+	WarnPrintf("warning %d\n", 5)
+
+	if Sequence() != seq+7 {
+		t.Errorf("Sequence = %d, want %d", Sequence(), seq+7)
+	}
+
+	entries := Entries()
+	if len(entries) != 3 {
+		t.Fatalf("Entries has %d entries, want 3", len(entries))
+	}
+	if e := entries[0]; e.ID != "testMemLog" || e.Text != "line 4" || e.Level != LevelInfo {
+		t.Errorf("entry 0 = %+v", e)
+	}
+	if e := entries[1]; e.ID != "testMemOffLog" || e.Text != "memory only" {
+		t.Errorf("inactive entry 1 = %+v", e)
+	}
+	if e := entries[2]; e.ID != WarnLog || e.Text != "warning 5" || e.Level != LevelWarn {
+		t.Errorf("entry 2 = %+v", e)
+	}
+	if strings.Contains(b.String(), "memory only") || !strings.Contains(b.String(), "line 4") {
+		t.Errorf("writer sink got %q", b.String())
+	}
+
+	SetMemorySize(2)
+	if entries := Entries(); len(entries) != 2 || entries[1].Text != "warning 5" {
+		t.Errorf("Entries after SetMemorySize = %+v", entries)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	Register("testLevelLog", true)
+	ClearEntries()
+
+	SetLevel(LevelWarn)
+	defer SetLevel(LevelDebug)
+
+	Log("testLevelLog", "info message\n")
+	LogLevel("testLevelLog", LevelError, "error message\n")
+
+	entries := Entries()
+	if len(entries) != 1 || entries[0].Text != "error message" || entries[0].Level != LevelError {
+		t.Errorf("Entries = %+v", entries)
+	}
+}
+
+func TestFormatEntry(t *testing.T) {
+	e := Entry{Time: time.Unix(0, 0).UTC(), ID: WarnLog, Level: LevelWarn, Text: "disk \"full\""}
+
+	if got := string(FormatEntry(e, FormatPlain)); got != "Warning: disk \"full\"\n" {
+		t.Errorf("plain = %q", got)
+	}
+
+	var je map[string]string
+	if err := json.Unmarshal(FormatEntry(e, FormatJSON), &je); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if je["level"] != "Warn" || je["id"] != WarnLog || je["msg"] != "disk \"full\"" {
+		t.Errorf("json = %v", je)
+	}
+}
+
+func TestFileSinkRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pktgen.log")
+
+	fs, err := NewFileSink(path, FormatJSON, 200, 2)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	defer fs.Close()
+
+	for i := 0; i < 20; i++ {
+		e := Entry{Time: time.Now(), ID: "testFileLog", Level: LevelInfo, Text: fmt.Sprintf("message %d", i)}
+		if err := fs.Write(e); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("missing log file: %v", err)
+		}
+		if info.Size() > 200 {
+			t.Errorf("%s has %d bytes", name, info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		t.Errorf("more than 2 rotated files kept")
+	}
+
+	b, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if last := lines[len(lines)-1]; !strings.Contains(last, "message 19") {
+		t.Errorf("last line = %s", last)
+	}
+}
+
+func TestFileSinkRotationError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pktgen.log")
+
+	fs, err := NewFileSink(path, FormatPlain, 15, 1)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	defer fs.Close()
+
+	// A non empty directory in place of the rotated file fails the rename
+	if err := os.MkdirAll(filepath.Join(path+".1", "busy"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	fs.Write(Entry{ID: "testFileLog", Text: "message 0"})
+	if err := fs.Write(Entry{ID: "testFileLog", Text: "message 1"}); err == nil {
+		t.Errorf("failed rotation has no error")
+	}
+	fs.Write(Entry{ID: "testFileLog", Text: "message 2"})
+
+	// The entries are kept in the log file
+	b, _ := os.ReadFile(path)
+	if string(b) != "message 0\nmessage 1\nmessage 2\n" {
+		t.Errorf("log file = %q", b)
+	}
+}
+
+// blockedSink blocks the writes until the channel is closed
+type blockedSink chan bool
+
+func (bs blockedSink) Write(e Entry) error {
+	<-bs
+	return nil
+}
+
+func (bs blockedSink) Close() error {
+	return nil
+}
+
+func TestAsyncSink(t *testing.T) {
+	var buf bytes.Buffer
+
+	as := NewAsyncSink(NewWriterSink(&buf, FormatPlain), 8)
+	as.Write(Entry{ID: InfoLog, Text: "queued"})
+	as.Close()
+
+	if buf.String() != "Info: queued\n" {
+		t.Errorf("async sink wrote %q", buf.String())
+	}
+
+	// The writes of a blocked sink are dropped once the queue is full
+	blocked := make(blockedSink)
+	as = NewAsyncSink(blocked, 2)
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 10; i++ {
+			as.Write(Entry{ID: InfoLog, Text: "message"})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Write blocked on a stalled sink")
+	}
+	if d := as.Dropped(); d < 7 {
+		t.Errorf("dropped %d entries, want at least 7", d)
+	}
+	close(blocked)
+	as.Close()
+}
+
+func TestConcurrentLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "concurrent.log")
+	if err := OpenFile(path, FormatText, 4096, 1); err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	defer RemoveSink(FileSinkName)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			id := fmt.Sprintf("testConcurrentLog%d", g)
+			for i := 0; i < 200; i++ {
+				Register(id, true)
+				SetState(id, i%2 == 0)
+				Log(id, "message %d\n", i)
+				IsActive(id)
+				GetList()
+				IDs()
+				Entries()
+			}
+		}(g)
+	}
+	wg.Wait()
+}
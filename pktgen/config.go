@@ -0,0 +1,372 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2022 Intel Corporation
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/KeithWiles/go-pktgen/pkgs/cfg"
+	cz "github.com/KeithWiles/go-pktgen/pkgs/colorize"
+	"github.com/KeithWiles/go-pktgen/pkgs/cpudata"
+	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
+)
+
+const (
+	configFormName = "configForm"
+)
+
+// mergePortInfo applies the configured settings of a port on top of the
+// given single packet configuration, zero values keep the current setting.
+func mergePortInfo(sc *SinglePacketConfig, p *cfg.PortInfo) error {
+
+	if p.TxCount != 0 {
+		sc.TxCount = p.TxCount
+	}
+	if p.PercentRate != 0 {
+		sc.PercentRate = p.PercentRate
+	}
+	if p.PktSize != 0 {
+		sc.PktSize = p.PktSize
+	}
+	if p.BurstCount != 0 {
+		sc.BurstCount = p.BurstCount
+	}
+	if p.TimeToLive != 0 {
+		sc.TimeToLive = p.TimeToLive
+	}
+	if p.SrcPort != 0 {
+		sc.SrcPort = p.SrcPort
+	}
+	if p.DstPort != 0 {
+		sc.DstPort = p.DstPort
+	}
+	if len(p.PType) > 0 {
+		sc.PType = p.PType
+	}
+	if len(p.ProtoType) > 0 {
+		sc.ProtoType = p.ProtoType
+	}
+	if p.VlanID != 0 {
+		sc.VlanId = p.VlanID
+	}
+
+	if len(p.SrcIP) > 0 {
+		ip, ipNet, err := net.ParseCIDR(p.SrcIP)
+		if err != nil {
+			return fmt.Errorf("port %d src_ip: %w", p.Port, err)
+		}
+		sc.SrcIP = net.IPNet{IP: ip, Mask: ipNet.Mask}
+	}
+	if len(p.DstIP) > 0 {
+		ip := net.ParseIP(p.DstIP)
+		if ip == nil {
+			return fmt.Errorf("port %d dst_ip: invalid address %q", p.Port, p.DstIP)
+		}
+		sc.DstIP = net.IPNet{IP: ip, Mask: ip.DefaultMask()}
+	}
+	if len(p.SrcMAC) > 0 {
+		mac, err := net.ParseMAC(p.SrcMAC)
+		if err != nil {
+			return fmt.Errorf("port %d src_mac: %w", p.Port, err)
+		}
+		sc.SrcMAC = mac
+	}
+	if len(p.DstMAC) > 0 {
+		mac, err := net.ParseMAC(p.DstMAC)
+		if err != nil {
+			return fmt.Errorf("port %d dst_mac: %w", p.Port, err)
+		}
+		sc.DstMAC = mac
+	}
+
+	if err := sc.Validate(); err != nil {
+		return fmt.Errorf("port %d: %w", p.Port, err)
+	}
+	return nil
+}
+
+// toPortInfo converts the single packet configuration of a port into the
+// configuration file format.
+func toPortInfo(sc *SinglePacketConfig) *cfg.PortInfo {
+
+	return &cfg.PortInfo{
+		Port:        sc.PortIndex,
+		TxCount:     sc.TxCount,
+		PercentRate: sc.PercentRate,
+		PktSize:     sc.PktSize,
+		BurstCount:  sc.BurstCount,
+		TimeToLive:  sc.TimeToLive,
+		SrcPort:     sc.SrcPort,
+		DstPort:     sc.DstPort,
+		PType:       sc.PType,
+		ProtoType:   sc.ProtoType,
+		VlanID:      sc.VlanId,
+		SrcIP:       sc.SrcIP.String(),
+		DstIP:       sc.DstIP.IP.String(),
+		SrcMAC:      sc.SrcMAC.String(),
+		DstMAC:      sc.DstMAC.String(),
+	}
+}
+
+// applyConfig sets the single packet configuration of the configured ports
+func applyConfig(c *cfg.Config) error {
+
+	return applyPorts(c.Ports)
+}
+
+// applyPorts sets the single packet configuration of the given ports
+func applyPorts(ports []*cfg.PortInfo) error {
+
+	for _, p := range ports {
+		if p.Port >= pktgen.portCnt {
+			return fmt.Errorf("port %d not in range 0-%d", p.Port, pktgen.portCnt-1)
+		}
+
+		sc, err := SingleConfig(p.Port)
+		if err != nil {
+			return err
+		}
+		if err := mergePortInfo(&sc, p); err != nil {
+			return err
+		}
+		if err := SetSingleConfig(p.Port, &sc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentPorts returns the settings of all ports in the configuration format
+func currentPorts() []*cfg.PortInfo {
+
+	ports := make([]*cfg.PortInfo, 0, pktgen.portCnt)
+	for port := 0; port < pktgen.portCnt; port++ {
+		if sc, err := SingleConfig(port); err == nil {
+			ports = append(ports, toPortInfo(&sc))
+		}
+	}
+	return ports
+}
+
+// loadedConfig returns the loaded or last saved configuration, nil if there
+// is none. A configuration is replaced and not changed once stored, so the
+// returned configuration can be read without the lock.
+func loadedConfig() *cfg.Config {
+
+	pktgen.cfgLock.Lock()
+	defer pktgen.cfgLock.Unlock()
+
+	return pktgen.config
+}
+
+// currentConfig returns the loaded configuration updated with the current
+// port settings and panel preferences, the loaded configuration is not changed.
+func currentConfig() *cfg.Config {
+
+	pktgen.cfgLock.Lock()
+	defer pktgen.cfgLock.Unlock()
+
+	c := &cfg.Config{}
+	if pktgen.config != nil {
+		*c = *pktgen.config
+	}
+	prefs := pktgen.prefs
+
+	c.Ports = currentPorts()
+	c.Prefs = &prefs
+	for _, p := range c.Ports {
+		portStartup(pktgen.config, p)
+	}
+
+	return c
+}
+
+// saveConfig writes the current configuration to the given path, an empty
+// path is the file of the -c option. The path is used by the next save.
+func saveConfig(path string) error {
+
+	return saveChangedConfig(path, nil)
+}
+
+// saveChangedConfig writes the current configuration with the change to the
+// given path, the loaded configuration is replaced only when the save works.
+func saveChangedConfig(path string, change func(c *cfg.Config)) error {
+
+	c := currentConfig()
+	if change != nil {
+		change(c)
+	}
+
+	pktgen.cfgLock.Lock()
+	defer pktgen.cfgLock.Unlock()
+
+	if len(path) == 0 {
+		path = pktgen.cfgPath
+	}
+	if len(path) == 0 {
+		return fmt.Errorf("no configuration file to save to")
+	}
+	if err := c.Save(path); err != nil {
+		return err
+	}
+	pktgen.config = c
+	pktgen.cfgPath = path
+	if info, err := os.Stat(path); err == nil {
+		pktgen.cfgModTime = info.ModTime()
+	}
+	tlog.Log(mainLog, "Configuration saved to %s\n", path)
+
+	return nil
+}
+
+// saveProfile stores the current port settings as a named profile and saves
+// the configuration to the given path.
+func saveProfile(name, path string) error {
+
+	if len(name) == 0 {
+		return fmt.Errorf("profile name is empty")
+	}
+
+	ports := currentPorts()
+
+	return saveChangedConfig(path, func(c *cfg.Config) {
+		profiles := make(map[string][]*cfg.PortInfo)
+		for n, p := range c.Profiles {
+			profiles[n] = p
+		}
+		profiles[name] = ports
+		c.Profiles = profiles
+	})
+}
+
+// savePlan writes the lcores of the plan into the configuration and saves
+// it, the ports use the lcores when they start.
+func savePlan(plan *cpudata.Plan) error {
+
+	return saveChangedConfig("", func(c *cfg.Config) {
+		for _, p := range c.Ports {
+			rx, tx := plan.Port(p.Port)
+			p.RxLcores, p.TxLcores = cpudata.FormatList(rx), cpudata.FormatList(tx)
+		}
+		mainLcore := int(plan.Main)
+		c.Lcores = cpudata.FormatList(plan.Lcores())
+		c.MainLcore = &mainLcore
+	})
+}
+
+// loadProfile applies the port settings of a named profile
+func loadProfile(name string) error {
+
+	pktgen.cfgLock.Lock()
+	var ports []*cfg.PortInfo
+	var ok bool
+	if pktgen.config != nil {
+		ports, ok = pktgen.config.Profiles[name]
+	}
+	pktgen.cfgLock.Unlock()
+
+	if !ok {
+		return fmt.Errorf("profile %q not found", name)
+	}
+	if err := applyPorts(ports); err != nil {
+		return fmt.Errorf("profile %s: %w", name, err)
+	}
+	tlog.Log(mainLog, "Profile %s loaded\n", name)
+
+	return nil
+}
+
+// profileNames returns the sorted names of the profiles
+func profileNames() []string {
+
+	pktgen.cfgLock.Lock()
+	defer pktgen.cfgLock.Unlock()
+
+	names := []string{}
+	if pktgen.config != nil {
+		for name := range pktgen.config.Profiles {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// showConfigForm displays the form to save the configuration and to save or
+// load the named profiles.
+func showConfigForm(pages *tview.Pages) {
+
+	pktgen.cfgLock.Lock()
+	path := pktgen.cfgPath
+	pktgen.cfgLock.Unlock()
+
+	profile := ""
+	names := profileNames()
+	selected := ""
+	if len(names) > 0 {
+		selected = names[0]
+	}
+
+	status := tview.NewTextView().SetDynamicColors(true)
+
+	result := func(msg string, err error) {
+		if err != nil {
+			status.SetText(cz.Error(err.Error()))
+			return
+		}
+		status.SetText(cz.Green(msg))
+	}
+
+	form := tview.NewForm().
+		SetFieldTextColor(tcell.ColorBlack).
+		SetFieldBackgroundColor(tcell.ColorBlue).
+		SetItemPadding(0).
+		SetCancelFunc(func() {
+			pages.RemovePage(configFormName)
+		})
+
+	form.AddInputField("Path     :", path, 40, nil, func(text string) {
+		path = text
+	})
+	form.AddInputField("Profile  :", profile, 20, nil, func(text string) {
+		profile = text
+	})
+	if len(names) > 0 {
+		form.AddDropDown("Load     :", names, 0, func(option string, optionIndex int) {
+			selected = option
+		})
+	}
+
+	form.AddButton("Save", func() {
+		result(fmt.Sprintf("Saved to %s", path), saveConfig(path))
+	})
+	form.AddButton("Save Profile", func() {
+		result(fmt.Sprintf("Profile %s saved to %s", profile, path), saveProfile(profile, path))
+	})
+	if len(names) > 0 {
+		form.AddButton("Load Profile", func() {
+			result(fmt.Sprintf("Profile %s loaded", selected), loadProfile(selected))
+		})
+	}
+	form.AddButton("Cancel", func() {
+		pages.RemovePage(configFormName)
+	})
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true).
+		AddItem(status, 1, 0, false)
+
+	flex.SetTitle(TitleColor("Save Configuration")).
+		SetTitleAlign(tview.AlignLeft).
+		SetBorder(true)
+
+	pages.AddPage(configFormName, CreateModal(flex, 56, 10), true, true)
+}
@@ -16,38 +16,73 @@ replace github.com/KeithWiles/go-pktgen/pkgs/taborder => ../pkgs/taborder
 
 replace github.com/KeithWiles/go-pktgen/pkgs/meter => ../pkgs/meter
 
+replace github.com/KeithWiles/go-pktgen/pkgs/pktgenrpc => ../pkgs/pktgenrpc
+
+replace github.com/KeithWiles/go-pktgen/pkgs/graphdata => ../pkgs/graphdata
+
+replace github.com/KeithWiles/go-pktgen/pkgs/asciichart => ../pkgs/asciichart
+
+replace github.com/KeithWiles/go-pktgen/pkgs/keybind => ../pkgs/keybind
+
+replace github.com/KeithWiles/go-pktgen/pkgs/hugepages => ../pkgs/hugepages
+
+replace github.com/KeithWiles/go-pktgen/pkgs/preflight => ../pkgs/preflight
+
+replace github.com/KeithWiles/go-pktgen/pkgs/ethtool => ../pkgs/ethtool
+
+replace github.com/KeithWiles/go-pktgen/pkgs/irqs => ../pkgs/irqs
+
 go 1.19
 
 require (
+	github.com/KeithWiles/go-pktgen/pkgs/asciichart v0.0.0-00010101000000-000000000000
 	github.com/KeithWiles/go-pktgen/pkgs/cfg v0.0.0-00010101000000-000000000000
 	github.com/KeithWiles/go-pktgen/pkgs/colorize v0.0.0-20221026164806-7a528bb011d0
 	github.com/KeithWiles/go-pktgen/pkgs/cpudata v0.0.0-20221026164806-7a528bb011d0
 	github.com/KeithWiles/go-pktgen/pkgs/devbind v0.0.0-20221026164806-7a528bb011d0
+	github.com/KeithWiles/go-pktgen/pkgs/ethtool v0.0.0-00010101000000-000000000000
 	github.com/KeithWiles/go-pktgen/pkgs/etimers v0.0.0-20221026164806-7a528bb011d0
+	github.com/KeithWiles/go-pktgen/pkgs/graphdata v0.0.0-00010101000000-000000000000
+	github.com/KeithWiles/go-pktgen/pkgs/hugepages v0.0.0-00010101000000-000000000000
+	github.com/KeithWiles/go-pktgen/pkgs/irqs v0.0.0-00010101000000-000000000000
+	github.com/KeithWiles/go-pktgen/pkgs/keybind v0.0.0-00010101000000-000000000000
 	github.com/KeithWiles/go-pktgen/pkgs/meter v0.0.0-00010101000000-000000000000
+	github.com/KeithWiles/go-pktgen/pkgs/pktgenrpc v0.0.0-00010101000000-000000000000
+	github.com/KeithWiles/go-pktgen/pkgs/preflight v0.0.0-00010101000000-000000000000
 	github.com/KeithWiles/go-pktgen/pkgs/taborder v0.0.0-20221026164806-7a528bb011d0
 	github.com/KeithWiles/go-pktgen/pkgs/ttylog v0.0.0-20221026164806-7a528bb011d0
 	github.com/gdamore/tcell/v2 v2.5.3
 	github.com/jessevdk/go-flags v1.5.0
+	github.com/prometheus/client_golang v1.15.1
 	github.com/rivo/tview v0.0.0-20221117065207-09f052e6ca98
 	github.com/shirou/gopsutil v3.21.11+incompatible
-	golang.org/x/text v0.4.0
+	golang.org/x/text v0.9.0
+	google.golang.org/grpc v1.56.3
+	google.golang.org/protobuf v1.31.0
 )
 
 require (
 	github.com/BurntSushi/toml v1.2.1 // indirect
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/gdamore/encoding v1.0.0 // indirect
 	github.com/go-ole/go-ole v1.2.6 // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-runewidth v0.0.14 // indirect
+	github.com/matttproud/golang_protobuf_extensions v1.0.4 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/prometheus/client_model v0.3.0 // indirect
+	github.com/prometheus/common v0.42.0 // indirect
+	github.com/prometheus/procfs v0.9.0 // indirect
 	github.com/rivo/uniseg v0.4.2 // indirect
 	github.com/tidwall/jsonc v0.3.2 // indirect
 	github.com/tklauser/go-sysconf v0.3.11 // indirect
 	github.com/tklauser/numcpus v0.6.0 // indirect
 	github.com/yusufpapurcu/wmi v1.2.2 // indirect
-	golang.org/x/sys v0.3.0 // indirect
-	golang.org/x/term v0.2.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	golang.org/x/net v0.9.0 // indirect
+	golang.org/x/sys v0.7.0 // indirect
+	golang.org/x/term v0.7.0 // indirect
+	google.golang.org/genproto v0.0.0-20230410155749-daa745c078e1 // indirect
 )
@@ -0,0 +1,561 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2022 Intel Corporation
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/KeithWiles/go-pktgen/pkgs/cfg"
+	"github.com/KeithWiles/go-pktgen/pkgs/cpudata"
+	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
+)
+
+// The headless mode runs the traffic profile of the configuration without
+// the text UI and writes the results to stdout or a file. The exit status
+// is one of the values below, a CI job can use it as the test result.
+const (
+	exitPass      = 0 // All ports passed the thresholds
+	exitError     = 1 // The run could not be done
+	exitThreshold = 2 // One or more ports violated a threshold
+)
+
+const (
+	headlessLog = "HeadlessLogID"
+
+	// headlessPoll is the rate the run checks for ports finishing a count
+	headlessPoll = 100 * time.Millisecond
+
+	// headlessPower is the rate the frequency of the lcores is sampled
+	headlessPower = time.Second
+)
+
+// headlessProfile is the traffic profile of the configuration merged with
+// the command line options.
+type headlessProfile struct {
+	ports      []int
+	duration   time.Duration
+	interval   time.Duration
+	count      uint64
+	maxLoss    *float64
+	maxLatency *float64
+	lcores     map[int][]uint16 // Lcores of each port, all lcores if none are configured
+	power      *powerMonitor
+}
+
+// powerMonitor samples the frequency, idle states and thermal throttle
+// events of the lcores during a run, a dip in the throughput can be matched
+// to an lcore running below its frequency or sleeping in a deep C-state.
+type powerMonitor struct {
+	start     map[uint16]cpudata.PowerState // State at the start of the run
+	last      map[uint16]cpudata.PowerState // Last sampled state
+	minFreq   map[uint16]uint64             // Lowest sampled frequency in kHz
+	startTime time.Time                     // Time of the start state
+	lastTime  time.Time                     // Time of the last sample
+}
+
+// HeadlessResult is one record of the headless results
+type HeadlessResult struct {
+	Type       string            `json:"type"` // "periodic" or "final"
+	Time       string            `json:"time"`
+	Elapsed    float64           `json:"elapsed_secs"`
+	Port       int               `json:"port"`
+	TxPkts     uint64            `json:"tx_pkts"`
+	RxPkts     uint64            `json:"rx_pkts"`
+	TxBytes    uint64            `json:"tx_bytes"`
+	RxBytes    uint64            `json:"rx_bytes"`
+	TxErrors   uint64            `json:"tx_errors"`
+	RxErrors   uint64            `json:"rx_errors"`
+	TxPPS      float64           `json:"tx_pps"`
+	RxPPS      float64           `json:"rx_pps"`
+	TxMbits    float64           `json:"tx_mbits"`
+	RxMbits    float64           `json:"rx_mbits"`
+	LossPct    float64           `json:"loss_pct"`
+	LatencyMin float64           `json:"latency_min_usec"`
+	LatencyAvg float64           `json:"latency_avg_usec"`
+	LatencyMax float64           `json:"latency_max_usec"`
+	FreqMin    float64           `json:"cpu_freq_min_mhz"`
+	Throttles  uint64            `json:"cpu_throttles"`
+	Governor   string            `json:"cpu_governor"`
+	CStates    []CStateResidency `json:"cpu_cstates"`
+	Pass       bool              `json:"pass"`
+}
+
+// CStateResidency is the percent of the run the lcores of a port spent in
+// an idle state
+type CStateResidency struct {
+	Name    string  `json:"name"`
+	Percent float64 `json:"pct"`
+}
+
+var headlessHeader = []string{
+	"type", "time", "elapsed_secs", "port",
+	"tx_pkts", "rx_pkts", "tx_bytes", "rx_bytes", "tx_errors", "rx_errors",
+	"tx_pps", "rx_pps", "tx_mbits", "rx_mbits", "loss_pct",
+	"latency_min_usec", "latency_avg_usec", "latency_max_usec",
+	"cpu_freq_min_mhz", "cpu_throttles", "cpu_governor", "cpu_cstates", "pass",
+}
+
+func init() {
+	tlog.Register(headlessLog)
+}
+
+// record returns the CSV fields of the result in the order of headlessHeader
+func (r *HeadlessResult) record() []string {
+
+	u := func(v uint64) string { return strconv.FormatUint(v, 10) }
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 3, 64) }
+
+	// The residency of the C-states is one field, i.e. "POLL:0.0 C1:2.5 C6:90.1"
+	cstates := []string{}
+	for _, c := range r.CStates {
+		cstates = append(cstates, c.Name+":"+strconv.FormatFloat(c.Percent, 'f', 1, 64))
+	}
+
+	return []string{
+		r.Type, r.Time, f(r.Elapsed), strconv.Itoa(r.Port),
+		u(r.TxPkts), u(r.RxPkts), u(r.TxBytes), u(r.RxBytes), u(r.TxErrors), u(r.RxErrors),
+		f(r.TxPPS), f(r.RxPPS), f(r.TxMbits), f(r.RxMbits), f(r.LossPct),
+		f(r.LatencyMin), f(r.LatencyAvg), f(r.LatencyMax),
+		f(r.FreqMin), u(r.Throttles), r.Governor, strings.Join(cstates, " "), strconv.FormatBool(r.Pass),
+	}
+}
+
+// resultWriter writes the results as JSON lines or CSV rows
+type resultWriter struct {
+	enc *json.Encoder
+	csv *csv.Writer
+}
+
+func newResultWriter(w io.Writer, format string) (*resultWriter, error) {
+
+	switch format {
+	case "", "json":
+		return &resultWriter{enc: json.NewEncoder(w)}, nil
+	case "csv":
+		rw := &resultWriter{csv: csv.NewWriter(w)}
+		if err := rw.csv.Write(headlessHeader); err != nil {
+			return nil, err
+		}
+		return rw, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func (rw *resultWriter) write(results []*HeadlessResult) error {
+
+	for _, r := range results {
+		if rw.enc != nil {
+			if err := rw.enc.Encode(r); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := rw.csv.Write(r.record()); err != nil {
+			return err
+		}
+	}
+	if rw.csv != nil {
+		rw.csv.Flush()
+		return rw.csv.Error()
+	}
+	return nil
+}
+
+// newHeadlessProfile merges the headless section of the configuration with
+// the command line options, the options take precedence.
+func newHeadlessProfile(c *cfg.Config) (*headlessProfile, error) {
+
+	hp := &headlessProfile{}
+	duration, interval := options.Duration, options.Interval
+
+	if h := c.Headless; h != nil {
+		hp.ports = h.Ports
+		hp.count = h.Count
+		hp.maxLoss = h.MaxLoss
+		hp.maxLatency = h.MaxLatency
+		if len(duration) == 0 {
+			duration = h.Duration
+		}
+		if len(interval) == 0 {
+			interval = h.Interval
+		}
+	}
+
+	if options.Count > 0 {
+		hp.count = options.Count
+	}
+	if options.MaxLoss >= 0 {
+		v := options.MaxLoss
+		hp.maxLoss = &v
+	}
+	if options.MaxLatency >= 0 {
+		v := options.MaxLatency
+		hp.maxLatency = &v
+	}
+
+	var err error
+	if len(duration) > 0 {
+		if hp.duration, err = time.ParseDuration(duration); err != nil {
+			return nil, fmt.Errorf("invalid duration: %w", err)
+		}
+	}
+	if len(interval) > 0 {
+		if hp.interval, err = time.ParseDuration(interval); err != nil {
+			return nil, fmt.Errorf("invalid interval: %w", err)
+		}
+	}
+
+	if hp.duration <= 0 && hp.count == 0 {
+		return nil, fmt.Errorf("headless mode needs a duration or a packet count")
+	}
+
+	if len(hp.ports) == 0 {
+		for port := 0; port < pktgen.portCnt; port++ {
+			hp.ports = append(hp.ports, port)
+		}
+	}
+	for _, port := range hp.ports {
+		if port < 0 || port >= pktgen.portCnt {
+			return nil, fmt.Errorf("port %d not in range 0-%d", port, pktgen.portCnt-1)
+		}
+	}
+
+	// The lcores of a port are its RX and TX lcores of the configuration
+	all := []uint16{}
+	for lcore := 0; lcore < int(pktgen.cpuData.NumLogicalCores()); lcore++ {
+		all = append(all, uint16(lcore))
+	}
+	hp.lcores = make(map[int][]uint16)
+	for _, port := range hp.ports {
+		p := &cfg.PortInfo{Port: port}
+		portStartup(c, p)
+
+		rx, _ := cpudata.ParseList(p.RxLcores)
+		tx, _ := cpudata.ParseList(p.TxLcores)
+		if lcores := append(rx, tx...); len(lcores) > 0 {
+			hp.lcores[port] = lcores
+		} else {
+			hp.lcores[port] = all
+		}
+	}
+	hp.power = newPowerMonitor(all)
+
+	return hp, nil
+}
+
+// newPowerMonitor returns a monitor of the lcores started with a sample
+func newPowerMonitor(lcores []uint16) *powerMonitor {
+
+	pm := &powerMonitor{
+		start:   make(map[uint16]cpudata.PowerState),
+		last:    make(map[uint16]cpudata.PowerState),
+		minFreq: make(map[uint16]uint64),
+	}
+	for _, lcore := range lcores {
+		pm.start[lcore] = cpudata.ReadPowerState(options.SysfsRoot, lcore)
+	}
+	pm.startTime = time.Now()
+	pm.sample()
+
+	return pm
+}
+
+// sample reads the power state of the lcores and keeps the lowest frequency
+func (pm *powerMonitor) sample() {
+
+	for lcore := range pm.start {
+		ps := cpudata.ReadPowerState(options.SysfsRoot, lcore)
+		pm.last[lcore] = ps
+
+		if f, ok := pm.minFreq[lcore]; ps.FreqKHz > 0 && (!ok || ps.FreqKHz < f) {
+			pm.minFreq[lcore] = ps.FreqKHz
+		}
+	}
+	pm.lastTime = time.Now()
+}
+
+// port sets the lowest frequency in MHz, the thermal throttle events, the
+// governors and the average C-state residency of the lcores since the start
+// of the run in the result. The frequency is 0 and the governor is empty if
+// the lcores have no cpufreq driver.
+func (pm *powerMonitor) port(r *HeadlessResult, lcores []uint16) {
+
+	var freq, throttles uint64
+	governors, seen := []string{}, make(map[string]bool)
+	names := []string{}
+	residency := make(map[string]float64)
+	idleLcores := 0
+	elapsed := pm.lastTime.Sub(pm.startTime)
+	cores, packages := make(map[uint16]bool), make(map[uint16]bool)
+
+	delta := func(last, start uint64) uint64 {
+		if last > start {
+			return last - start
+		}
+		return 0
+	}
+
+	for _, lcore := range lcores {
+		last, start := pm.last[lcore], pm.start[lcore]
+
+		if f, ok := pm.minFreq[lcore]; ok && (freq == 0 || f < freq) {
+			freq = f
+		}
+
+		// The core count is shared by the hyperthreads of a core and the
+		// package count by all lcores of the package, each is counted once
+		core := lcore
+		if siblings := pktgen.cpuData.Siblings(lcore); len(siblings) > 0 {
+			core = siblings[0]
+		}
+		if !cores[core] {
+			cores[core] = true
+			throttles += delta(last.CoreThrottles, start.CoreThrottles)
+		}
+		socket, _ := pktgen.cpuData.Socket(lcore)
+		if !packages[socket] {
+			packages[socket] = true
+			throttles += delta(last.PackageThrottles, start.PackageThrottles)
+		}
+
+		// The lcores of a port usually share the governor, a change is shown
+		// as a list of the governors
+		if len(last.Governor) > 0 && !seen[last.Governor] {
+			seen[last.Governor] = true
+			governors = append(governors, last.Governor)
+		}
+
+		if len(last.Idle) == 0 {
+			continue
+		}
+		idleLcores++
+		for i, pct := range last.Residency(start, elapsed) {
+			name := last.Idle[i].Name
+			if _, ok := residency[name]; !ok {
+				names = append(names, name)
+			}
+			residency[name] += pct
+		}
+	}
+
+	r.FreqMin = float64(freq) / 1000.0
+	r.Throttles = throttles
+	r.Governor = strings.Join(governors, "/")
+	r.CStates = []CStateResidency{}
+	for _, name := range names {
+		r.CStates = append(r.CStates, CStateResidency{Name: name, Percent: residency[name] / float64(idleLcores)})
+	}
+}
+
+// results computes the statistics of the ports since the start of the run.
+// The final results use the average rates of the run, the periodic results
+// the rates of the last collection.
+func (hp *headlessProfile) results(typ string, start, now *StatsSnapshot) []*HeadlessResult {
+
+	elapsed := now.Timestamp.Sub(start.Timestamp).Seconds()
+	list := make([]*HeadlessResult, 0, len(hp.ports))
+
+	for _, port := range hp.ports {
+		s, e := &start.Ports[port], &now.Ports[port]
+
+		r := &HeadlessResult{
+			Type:       typ,
+			Time:       now.Timestamp.Format(time.RFC3339Nano),
+			Elapsed:    elapsed,
+			Port:       port,
+			TxPkts:     e.TxPkts - s.TxPkts,
+			RxPkts:     e.RxPkts - s.RxPkts,
+			TxBytes:    e.TxBytes - s.TxBytes,
+			RxBytes:    e.RxBytes - s.RxBytes,
+			TxErrors:   e.TxErrors - s.TxErrors,
+			RxErrors:   e.RxErrors - s.RxErrors,
+			TxPPS:      float64(e.TxPPS),
+			RxPPS:      float64(e.RxPPS),
+			TxMbits:    e.TxMbits,
+			RxMbits:    e.RxMbits,
+			LatencyMin: e.Latency.Min,
+			LatencyAvg: e.Latency.Avg,
+			LatencyMax: e.Latency.Max,
+		}
+
+		if typ == "final" && elapsed > 0 {
+			r.TxPPS = float64(r.TxPkts) / elapsed
+			r.RxPPS = float64(r.RxPkts) / elapsed
+			r.TxMbits = BitRate(r.TxPkts, r.TxBytes) / elapsed / float64(Million)
+			r.RxMbits = BitRate(r.RxPkts, r.RxBytes) / elapsed / float64(Million)
+		}
+		hp.power.port(r, hp.lcores[port])
+
+		if r.TxPkts > 0 && r.TxPkts > r.RxPkts {
+			r.LossPct = float64(r.TxPkts-r.RxPkts) * 100.0 / float64(r.TxPkts)
+		}
+		r.Pass = hp.check(r) == nil
+
+		list = append(list, r)
+	}
+	return list
+}
+
+// check the result against the thresholds of the profile
+func (hp *headlessProfile) check(r *HeadlessResult) error {
+
+	if hp.maxLoss != nil && r.LossPct > *hp.maxLoss {
+		return fmt.Errorf("port %d loss %.3f%% exceeds %.3f%%", r.Port, r.LossPct, *hp.maxLoss)
+	}
+	if hp.maxLatency != nil && r.LatencyAvg > *hp.maxLatency {
+		return fmt.Errorf("port %d average latency %.3f usec exceeds %.3f usec",
+			r.Port, r.LatencyAvg, *hp.maxLatency)
+	}
+	return nil
+}
+
+// allStopped returns true when none of the ports are sending traffic
+func (hp *headlessProfile) allStopped() bool {
+
+	for _, port := range hp.ports {
+		if sc, err := SingleConfig(port); err != nil || sc.TxState {
+			return false
+		}
+	}
+	return true
+}
+
+// runHeadless runs the traffic profile of the configuration and returns the
+// exit status of the process.
+func runHeadless(c *cfg.Config) int {
+
+	hp, err := newHeadlessProfile(c)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "headless: %s\n", err)
+		return exitError
+	}
+
+	out := io.Writer(os.Stdout)
+	if len(options.Output) > 0 {
+		f, err := os.Create(options.Output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "headless: %s\n", err)
+			return exitError
+		}
+		defer f.Close()
+		out = f
+	}
+
+	rw, err := newResultWriter(out, options.Format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "headless: %s\n", err)
+		return exitError
+	}
+
+	// In count mode each port stops by itself after sending the packets
+	if hp.count > 0 {
+		for _, port := range hp.ports {
+			sc, err := SingleConfig(port)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "headless: %s\n", err)
+				return exitError
+			}
+			sc.TxCount = hp.count
+			if err := SetSingleConfig(port, &sc); err != nil {
+				fmt.Fprintf(os.Stderr, "headless: %s\n", err)
+				return exitError
+			}
+		}
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	var deadline <-chan time.Time
+	if hp.duration > 0 {
+		t := time.NewTimer(hp.duration)
+		defer t.Stop()
+		deadline = t.C
+	}
+
+	var periodic <-chan time.Time
+	if hp.interval > 0 {
+		t := time.NewTicker(hp.interval)
+		defer t.Stop()
+		periodic = t.C
+	}
+
+	poll := time.NewTicker(headlessPoll)
+	defer poll.Stop()
+
+	power := time.NewTicker(headlessPower)
+	defer power.Stop()
+
+	// The counters only change on a collection, collect at the start and the
+	// end of the run to measure the elapsed time of the counters.
+	pktgen.stats.Collect(pktgen.stats.Snapshot().Ticks)
+	start := pktgen.stats.Snapshot()
+	last := start.Timestamp
+
+	tlog.Log(headlessLog, "Headless start: ports %v duration %v count %d\n",
+		hp.ports, hp.duration, hp.count)
+	if err := SetTxState(true, hp.ports...); err != nil {
+		fmt.Fprintf(os.Stderr, "headless: %s\n", err)
+		return exitError
+	}
+
+done:
+	for {
+		select {
+		case sig := <-sigs:
+			tlog.Log(headlessLog, "Headless stopped by signal %v\n", sig)
+			break done
+		case <-deadline:
+			break done
+		case <-periodic:
+			// Skip the interval when no new statistics were collected
+			snap := pktgen.stats.Snapshot()
+			if !snap.Timestamp.After(last) {
+				continue
+			}
+			last = snap.Timestamp
+			if err := rw.write(hp.results("periodic", start, snap)); err != nil {
+				fmt.Fprintf(os.Stderr, "headless: %s\n", err)
+			}
+		case <-poll.C:
+			if hp.count > 0 && hp.allStopped() {
+				break done
+			}
+		case <-power.C:
+			hp.power.sample()
+		}
+	}
+	hp.power.sample()
+
+	pktgen.stats.Collect(pktgen.stats.Snapshot().Ticks)
+	SetTxState(false, hp.ports...)
+
+	results := hp.results("final", start, pktgen.stats.Snapshot())
+	if err := rw.write(results); err != nil {
+		fmt.Fprintf(os.Stderr, "headless: %s\n", err)
+		return exitError
+	}
+
+	code := exitPass
+	for _, r := range results {
+		if err := hp.check(r); err != nil {
+			fmt.Fprintf(os.Stderr, "headless: %s\n", err)
+			code = exitThreshold
+		}
+	}
+	tlog.Log(headlessLog, "Headless done: exit status %d\n", code)
+
+	return code
+}
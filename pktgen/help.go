@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2022 Intel Corporation
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	cz "github.com/KeithWiles/go-pktgen/pkgs/colorize"
+	"github.com/KeithWiles/go-pktgen/pkgs/keybind"
+	tab "github.com/KeithWiles/go-pktgen/pkgs/taborder"
+	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
+)
+
+const (
+	helpPageName = "helpPage"
+	helpWidth    = 72
+)
+
+// newTabOrder returns the tab order of the windows of a panel, the help uses
+// it to find the window with the focus.
+func newTabOrder(panel string) *tab.Tab {
+
+	to := tab.New(panel, pktgen.app)
+
+	if pktgen.tabOrders == nil {
+		pktgen.tabOrders = make(map[string]*tab.Tab)
+	}
+	pktgen.tabOrders[panel] = to
+
+	return to
+}
+
+// panelInput returns the input capture of a panel calling the key bindings
+// of the window with the focus and of the panel.
+func panelInput(to *tab.Tab) func(event *tcell.EventKey) *tcell.EventKey {
+
+	return func(event *tcell.EventKey) *tcell.EventKey {
+		if keybind.Dispatch(event, to.Scope()) {
+			return nil
+		}
+		return event
+	}
+}
+
+// helpSection returns the help text of the key bindings of a scope
+func helpSection(scope string) string {
+
+	bindings := keybind.Bindings(scope)
+	if len(bindings) == 0 {
+		return ""
+	}
+
+	var title string
+	switch {
+	case scope == "":
+		title = "Global"
+	case keybind.Parent(scope) == "":
+		title = scope + " panel"
+	default:
+		title = scope[strings.LastIndex(scope, keybind.ScopeSeparator)+1:] + " window"
+	}
+
+	s := cz.Header(title) + "\n"
+	for _, b := range bindings {
+		s += fmt.Sprintf("  %s %s\n", cz.Label(fmt.Sprintf("%-10s", b.Name())), tview.Escape(b.Desc))
+	}
+	return s + "\n"
+}
+
+// showHelp displays the keys of the window with the focus, of the current
+// panel and the global keys until Esc, Enter or ? is pressed.
+func showHelp() {
+
+	pages := pktgen.pages
+	to := pktgen.tabOrders[pktgen.prefs.Panel]
+
+	scope := pktgen.prefs.Panel
+	if to != nil {
+		scope = to.Scope()
+	}
+
+	text := ""
+	for {
+		text += helpSection(scope)
+		if scope == "" {
+			break
+		}
+		scope = keybind.Parent(scope)
+	}
+	text = strings.TrimRight(text, "\n")
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false).
+		SetText(text)
+	view.SetBorder(true).
+		SetTitle(TitleColor("Help, Esc to close")).
+		SetTitleAlign(tview.AlignLeft)
+
+	hide := func() {
+		pages.RemovePage(helpPageName)
+		if to != nil {
+			to.SetCurrentInputFocus()
+		}
+	}
+	view.SetDoneFunc(func(key tcell.Key) {
+		hide()
+	})
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == '?' {
+			hide()
+			return nil
+		}
+		return event
+	})
+
+	pages.AddPage(helpPageName, CreateModal(view, helpWidth, strings.Count(text, "\n")+3), true, true)
+}
+
+// checkKeyBindings logs the keys bound more than once in a scope or in a
+// scope and one of its enclosing scopes and shows the number of conflicts.
+func checkKeyBindings() {
+
+	conflicts := keybind.Conflicts()
+	for _, c := range conflicts {
+		tlog.WarnPrintf("Key binding conflict %s\n", c)
+	}
+	if len(conflicts) > 0 {
+		showMessage(fmt.Sprintf("%d key binding conflicts, first %s, see the log for all",
+			len(conflicts), conflicts[0]))
+	}
+}
@@ -14,7 +14,7 @@ import (
 // TitleColor - Set the title color to the windows
 func TitleColor(msg string) string {
 
-	return fmt.Sprintf("[%s]", cz.Orange(msg))
+	return fmt.Sprintf("[%s]", cz.Title(msg))
 }
 
 // Center returns a new primitive which shows the provided primitive in its
@@ -168,3 +168,28 @@ func CreateModal(p tview.Primitive, width, height int) tview.Primitive {
         SetRows(0, height, 0).
         AddItem(p, 1, 1, 1, 1, 0, 0, true)
 }
+
+const (
+	messagePageName = "messagePage"
+)
+
+// showMessage displays a message box over the current panel until the OK
+// button is pressed, it can be called from any goroutine and before the
+// application runs as the update is queued without waiting for it.
+func showMessage(msg string) {
+
+	pages := pktgen.pages
+	if pages == nil {
+		return
+	}
+
+	go pktgen.app.QueueUpdateDraw(func() {
+		modal := tview.NewModal().
+			SetText(msg).
+			AddButtons([]string{"OK"}).
+			SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+				pages.RemovePage(messagePageName)
+			})
+		pages.AddPage(messagePageName, modal, true, true)
+	})
+}
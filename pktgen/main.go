@@ -8,12 +8,16 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"sync"
 	"syscall"
 	"time"
 
 	cz "github.com/KeithWiles/go-pktgen/pkgs/colorize"
 	"github.com/KeithWiles/go-pktgen/pkgs/cpudata"
 	"github.com/KeithWiles/go-pktgen/pkgs/cfg"
+	"github.com/KeithWiles/go-pktgen/pkgs/devbind"
+	"github.com/KeithWiles/go-pktgen/pkgs/keybind"
+	tab "github.com/KeithWiles/go-pktgen/pkgs/taborder"
 	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
 	flags "github.com/jessevdk/go-flags"
 
@@ -54,14 +58,48 @@ type Pktgen struct {
 	portCnt int
 	single  []*SinglePacketConfig
 	ModalPages []*ModalPage
+
+	singleLock sync.RWMutex // Lock for the single packet configuration
+	stats      *StatsInfo   // Port statistics collected by the timers
+
+	cfgLock    sync.Mutex     // Lock for the configuration and its path
+	config     *cfg.Config    // Loaded or last saved configuration
+	cfgPath    string         // File the configuration is saved to
+	cfgModTime time.Time      // Modification time of the file when last read or written
+	prefs      cfg.PanelPrefs // Current panel preferences
+	pages      *tview.Pages   // Pages of the panels and modals
+
+	tabOrders map[string]*tab.Tab // Tab order of the windows of each panel
+	devbind   *devbind.BindInfo   // Devices of the system, see bindInfo
 }
 
 // Options command line options
 type Options struct {
-	Config      string `short:"c" long:"config" description:"JSON configuration file"`
-	Ptty        string `short:"p" long:"ptty" description:"path to ptty /dev/pts/X"`
-	ShowVersion bool   `short:"V" long:"version" description:"Print out version and exit"`
-	Verbose     bool   `short:"v" long:"Verbose output for debugging"`
+	Config      string   `short:"c" long:"config" description:"JSON configuration file"`
+	Ptty        string   `short:"p" long:"ptty" description:"path to ptty /dev/pts/X"`
+	ShowVersion bool     `short:"V" long:"version" description:"Print out version and exit"`
+	Verbose     bool     `short:"v" long:"Verbose output for debugging"`
+	GRPCAddr    string   `short:"g" long:"grpc" description:"gRPC server listen address i.e. localhost:50051"`
+	MetricsAddr string   `short:"m" long:"metrics" description:"Prometheus metrics listen address i.e. :9100"`
+	Watch       bool     `short:"w" long:"watch" description:"Reload the configuration when the file changes"`
+	SysfsRoot   string   `long:"sysfs" default:"/sys" description:"Root of the sysfs tree used to bind the devices"`
+	ProcRoot    string   `long:"proc" default:"/proc" description:"Root of the proc tree used by the system checks"`
+	Devices     []string `long:"devices" description:"TOML device file layered on the default devices, can be repeated"`
+
+	LogFile    string `long:"log-file" description:"Log file, rotated when it grows beyond the log size"`
+	LogSize    int64  `long:"log-size" default:"10" description:"Log file size in MBytes before it is rotated, 0 to never rotate"`
+	LogBackups int    `long:"log-backups" default:"3" description:"Number of rotated log files kept"`
+	LogJSON    bool   `long:"log-json" description:"Write the log file as JSON lines"`
+	LogLevel   string `long:"log-level" choice:"debug" choice:"info" choice:"warn" choice:"error" default:"debug" description:"Lowest level of the logged messages"`
+
+	Headless   bool    `long:"headless" description:"Run the headless traffic profile without the UI and exit"`
+	Duration   string  `long:"duration" description:"Headless run time i.e. 30s"`
+	Count      uint64  `long:"count" description:"Headless number of packets to send on each port"`
+	Interval   string  `long:"interval" description:"Headless periodic statistics interval i.e. 5s"`
+	Format     string  `long:"format" choice:"json" choice:"csv" default:"json" description:"Headless output format"`
+	Output     string  `long:"output" description:"Headless output file, default is stdout"`
+	MaxLoss    float64 `long:"max-loss" default:"-1" description:"Headless maximum packet loss in percent, negative to ignore"`
+	MaxLatency float64 `long:"max-latency" default:"-1" description:"Headless maximum average latency in usec, negative to ignore"`
 }
 
 // Global to the main package for the tool
@@ -79,9 +117,9 @@ func buildPanelString(idx int) string {
 	s := ""
 	for index, p := range pktgen.panels {
 		if index == idx {
-			s += fmt.Sprintf("F%d:[orange::r]%s[white::-]", index+1, p.title)
+			s += fmt.Sprintf("F%d:%s", index+1, cz.Colorize(cz.RoleColor(cz.RoleTitle), p.title, 0, 0, "", "", "r"))
 		} else {
-			s += fmt.Sprintf("F%d:[orange::-]%s[white::-]", index+1, p.title)
+			s += fmt.Sprintf("F%d:%s", index+1, cz.Title(p.title))
 		}
 		if (index + 1) < len(pktgen.panels) {
 			s += " "
@@ -109,6 +147,7 @@ func init() {
 	pktgen.portCnt = 8
 
 	pktgen.single = make([]*SinglePacketConfig, 8)
+	pktgen.stats = newStatsInfo(pktgen.portCnt)
 }
 
 // Version number string
@@ -137,34 +176,106 @@ func main() {
 			os.Exit(1)
 		}
 	}
+	if len(options.LogFile) > 0 {
+		format := tlog.FormatText
+		if options.LogJSON {
+			format = tlog.FormatJSON
+		}
+		err = tlog.OpenFile(options.LogFile, format, options.LogSize*1024*1024, options.LogBackups)
+		if err != nil {
+			fmt.Printf("log file open failed: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if level, err := tlog.ParseLevel(options.LogLevel); err == nil {
+		tlog.SetLevel(level)
+	}
+	devbind.SetSysfsRoot(options.SysfsRoot)
+
 	if options.ShowVersion {
 		fmt.Printf("Go-Pktgen Version: %s\n", pktgen.version)
 		return
 	}
 
+	var sys *cfg.System
 	if len(options.Config) > 0 {
-		_, err = cfg.OpenWithFile(options.Config)
-        if err != nil {
-            fmt.Printf("load configuration failed: %s\n", err)
-            os.Exit(1)
-        }
-	} else {
+		sys, err = cfg.OpenWithFile(options.Config)
+		if err != nil {
+			fmt.Printf("load configuration failed: %s\n", err)
+			os.Exit(1)
+		}
+		if err = applyConfig(sys.Config()); err != nil {
+			fmt.Printf("apply configuration failed: %s\n", err)
+			os.Exit(1)
+		}
+		pktgen.config = sys.Config()
+		pktgen.cfgPath = sys.Path()
+		if info, err := os.Stat(sys.Path()); err == nil {
+			pktgen.cfgModTime = info.ModTime()
+		}
+		if prefs := pktgen.config.Prefs; prefs != nil {
+			pktgen.prefs = *prefs
+		}
+		if err := applyTheme(pktgen.config.Theme); err != nil {
+			fmt.Printf("load configuration failed: %s\n", err)
+			os.Exit(1)
+		}
+	} else if !preflightActive() {
 		fmt.Printf("No configuration file specified\n")
-        os.Exit(1)
+		os.Exit(1)
+	}
+
+	if preflightActive() {
+		os.Exit(runPreflight())
 	}
 
 	tlog.Log(mainLog, "\n===== %s =====\n", PktgenInfo(false))
-	fmt.Printf("\n===== %s =====\n", PktgenInfo(false))
+	if options.Headless {
+		// Keep stdout for the results
+		fmt.Fprintf(os.Stderr, "===== %s =====\n", PktgenInfo(false))
+	} else {
+		fmt.Printf("\n===== %s =====\n", PktgenInfo(false))
+	}
 
 	app := pktgen.app
 
 	pktgen.timers = etimers.New(time.Second/4, 4)
 	pktgen.timers.Start()
 
+	pktgen.timers.Add(statsTimerName, func(step int, ticks uint64) {
+		if step == 0 {
+			pktgen.stats.Collect(ticks)
+		}
+	})
+
+	if len(options.GRPCAddr) > 0 {
+		if _, err := startGRPCServer(options.GRPCAddr); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if len(options.MetricsAddr) > 0 {
+		if _, err := startMetricsServer(options.MetricsAddr); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if options.Headless {
+		code := runHeadless(sys.Config())
+		pktgen.timers.Stop()
+		os.Exit(code)
+	}
+
 	panels := []Panels{
 		SingleModePanelSetup,
+		GraphsPanelSetup,
 		SysInfoPanelSetup,
+		MemoryPanelSetup,
 		CPULoadPanelSetup,
+		InterruptsPanelSetup,
+		LogPanelSetup,
 	}
 
 	// The bottom row has some info on where we are.
@@ -174,9 +285,9 @@ func main() {
 		SetWrap(false)
 
 	currentPanel := 0
-	info.Highlight(strconv.Itoa(currentPanel))
 
 	pages := tview.NewPages()
+	pktgen.pages = pages
 	panel := tview.NewFlex()
 
 	previousPanel := func() {
@@ -185,6 +296,7 @@ func main() {
 			ScrollToHighlight()
 		pages.SwitchToPage(strconv.Itoa(currentPanel))
 		info.SetText(buildPanelString(currentPanel))
+		pktgen.prefs.Panel = pktgen.panels[currentPanel].title
 	}
 
 	nextPanel := func() {
@@ -193,62 +305,93 @@ func main() {
 			ScrollToHighlight()
 		pages.SwitchToPage(strconv.Itoa(currentPanel))
 		info.SetText(buildPanelString(currentPanel))
+		pktgen.prefs.Panel = pktgen.panels[currentPanel].title
 	}
 
-	for index, f := range panels {
+	for _, f := range panels {
 		title, primitive := f(pages, nextPanel)
-		pages.AddPage(strconv.Itoa(index), primitive, true, index == currentPanel)
 		pktgen.panels = append(pktgen.panels, PanelInfo{title: title, primitive: primitive})
 	}
 
+	// Start on the panel of the preferences
+	for index, p := range pktgen.panels {
+		if p.title == pktgen.prefs.Panel {
+			currentPanel = index
+		}
+	}
+	for index, p := range pktgen.panels {
+		pages.AddPage(strconv.Itoa(index), p.primitive, true, index == currentPanel)
+	}
+	info.Highlight(strconv.Itoa(currentPanel))
+	pktgen.prefs.Panel = pktgen.panels[currentPanel].title
+
 	for _, m := range pktgen.ModalPages {
 		pages.AddPage(m.title, m.modal.(tview.Primitive), false, false)
 	}
 
-	info.SetText(buildPanelString(0))
+	info.SetText(buildPanelString(currentPanel))
 
 	// Create the main panel.
 	panel.SetDirection(tview.FlexRow).
 		AddItem(pages, 0, 1, true).
 		AddItem(info, 1, 1, false)
 
+	showPanel := func(idx int) {
+		currentPanel = idx
+		info.Highlight(strconv.Itoa(currentPanel)).ScrollToHighlight()
+		pages.SwitchToPage(strconv.Itoa(currentPanel))
+		info.SetText(buildPanelString(currentPanel))
+		pktgen.prefs.Panel = pktgen.panels[currentPanel].title
+	}
+
 	// Shortcuts to navigate the panels.
+	keybind.Add("", tcell.KeyCtrlN, "Show the next panel", nextPanel)
+	keybind.Add("", tcell.KeyCtrlP, "Show the previous panel", previousPanel)
+	for index, p := range pktgen.panels {
+		idx := index
+		keybind.Add("", tcell.KeyF1+tcell.Key(index), fmt.Sprintf("Show the %s panel", p.title), func() {
+			showPanel(idx)
+		})
+	}
+	keybind.Add("", tcell.KeyCtrlS, "Save the configuration or a profile", func() {
+		showConfigForm(pages)
+	})
+	keybind.Add("", '?', "Show the keys of the panel and window", showHelp)
+	keybind.Add("", 'q', "Quit", app.Stop)
+	keybind.Add("", tcell.KeyCtrlQ, "Quit", app.Stop)
+
 	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Key() == tcell.KeyCtrlN {
-			nextPanel()
-		} else if event.Key() == tcell.KeyCtrlP {
-			previousPanel()
-		} else if event.Key() == tcell.KeyCtrlQ {
-			app.Stop()
-		} else {
-			var idx int
-
-			switch {
-			case event.Key() >= tcell.KeyF1 && event.Key() <= tcell.KeyF19:
-				idx = int(event.Key() - tcell.KeyF1)
-			case event.Rune() == 'q':
-				app.Stop()
-			default:
-				idx = -1
+		// The runes belong to the form or modal displayed over the panel and
+		// to an input field of a panel, the other global keys like Ctrl-Q
+		// work everywhere
+		if event.Key() == tcell.KeyRune {
+			if name, _ := pages.GetFrontPage(); name != strconv.Itoa(currentPanel) {
+				return event
 			}
-			if idx != -1 {
-				if idx < len(panels) {
-					currentPanel = idx
-					info.Highlight(strconv.Itoa(currentPanel)).ScrollToHighlight()
-					pages.SwitchToPage(strconv.Itoa(currentPanel))
-				}
-				info.SetText(buildPanelString(idx))
+			if _, ok := app.GetFocus().(*tview.InputField); ok {
+				return event
 			}
 		}
+
+		if keybind.Dispatch(event, "") {
+			return nil
+		}
 		return event
 	})
 
-	setupSignals(syscall.SIGINT, syscall.SIGTERM, syscall.SIGSEGV)
+	checkKeyBindings()
+
+	setupSignals(syscall.SIGINT, syscall.SIGTERM, syscall.SIGSEGV, syscall.SIGHUP)
+
+	if options.Watch {
+		watchConfig()
+	}
 
 	// Start the application.
 	if err := app.SetRoot(panel, true).EnableMouse(true).Run(); err != nil {
 		panic(err)
 	}
+	pktgen.timers.Stop()
 
 	tlog.Log(mainLog, "===== Done =====\n")
 }
@@ -260,12 +403,18 @@ func setupSignals(signals ...os.Signal) {
 
 	signal.Notify(sigs, signals...)
 	go func() {
-		sig := <-sigs
+		for sig := range sigs {
+			tlog.Log(mainLog, "Signal: %v\n", sig)
 
-		tlog.Log(mainLog, "Signal: %v\n", sig)
-		time.Sleep(time.Second)
+			if sig == syscall.SIGHUP {
+				reload("SIGHUP")
+				continue
+			}
 
-		app.Stop()
-		os.Exit(1)
+			// Stopping the application returns from app.Run() in main
+			signal.Stop(sigs)
+			app.Stop()
+			return
+		}
 	}()
 }
@@ -0,0 +1,279 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2022 Intel Corporation
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/mem"
+	psnet "github.com/shirou/gopsutil/net"
+
+	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
+)
+
+// Prometheus exporter of the port statistics and the host information, the
+// values are read from the last collected statistics at scrape time.
+
+const (
+	metricsLog       = "MetricsLogID"
+	metricsNamespace = "pktgen"
+)
+
+func init() {
+	tlog.Register(metricsLog)
+}
+
+// metricsCollector implements the prometheus.Collector interface
+type metricsCollector struct {
+	buildInfo *prometheus.Desc
+
+	portPkts    *prometheus.Desc
+	portBytes   *prometheus.Desc
+	portErrors  *prometheus.Desc
+	portPPS     *prometheus.Desc
+	portMaxPPS  *prometheus.Desc
+	portMbits   *prometheus.Desc
+	portSizes   *prometheus.Desc
+	portLatency *prometheus.Desc
+
+	cpuLoad     *prometheus.Desc
+	memory      *prometheus.Desc
+	hugepages   *prometheus.Desc
+	hostPkts    *prometheus.Desc
+	hostBytes   *prometheus.Desc
+	hostErrors  *prometheus.Desc
+	hostDropped *prometheus.Desc
+
+	// The CPU load is the busy time since the last scrape, cpu.Percent is not
+	// used as it shares its previous sample with the CPU panel.
+	cpuLock  sync.Mutex
+	cpuTimes []cpu.TimesStat
+}
+
+func newDesc(subsystem, name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, subsystem, name), help, labels, nil)
+}
+
+// newMetricsCollector creates the descriptions of all exported metrics
+func newMetricsCollector() *metricsCollector {
+
+	return &metricsCollector{
+		buildInfo: newDesc("", "build_info", "Build information of go-pktgen, the value is always 1."),
+
+		portPkts:    newDesc("port", "packets_total", "Number of packets per port and direction.", "port", "direction"),
+		portBytes:   newDesc("port", "bytes_total", "Number of bytes per port and direction.", "port", "direction"),
+		portErrors:  newDesc("port", "errors_total", "Number of errors per port and direction.", "port", "direction"),
+		portPPS:     newDesc("port", "packets_per_second", "Current packet rate per port and direction.", "port", "direction"),
+		portMaxPPS:  newDesc("port", "max_packets_per_second", "Maximum packet rate per port and direction.", "port", "direction"),
+		portMbits:   newDesc("port", "mbits_per_second", "Current bit rate in Mbits per port and direction.", "port", "direction"),
+		portSizes:   newDesc("port", "rx_size_packets_total", "Number of received packets per port and size or type bucket.", "port", "bucket"),
+		portLatency: newDesc("port", "latency_microseconds", "Latency of the received packets in microseconds.", "port"),
+
+		cpuLoad:     newDesc("host", "cpu_load_percent", "Load of each logical core in percent.", "cpu"),
+		memory:      newDesc("host", "memory_bytes", "Host memory in bytes.", "type"),
+		hugepages:   newDesc("host", "hugepages", "Number of hugepages of the default hugepage size.", "type"),
+		hostPkts:    newDesc("host", "net_packets_total", "Packets per host network interface and direction.", "interface", "direction"),
+		hostBytes:   newDesc("host", "net_bytes_total", "Bytes per host network interface and direction.", "interface", "direction"),
+		hostErrors:  newDesc("host", "net_errors_total", "Errors per host network interface and direction.", "interface", "direction"),
+		hostDropped: newDesc("host", "net_dropped_total", "Dropped packets per host network interface and direction.", "interface", "direction"),
+	}
+}
+
+// Describe all of the metrics to the registry
+func (mc *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+
+	for _, d := range []*prometheus.Desc{
+		mc.buildInfo, mc.portPkts, mc.portBytes, mc.portErrors, mc.portPPS,
+		mc.portMaxPPS, mc.portMbits, mc.portSizes, mc.portLatency, mc.cpuLoad,
+		mc.memory, mc.hugepages, mc.hostPkts, mc.hostBytes, mc.hostErrors, mc.hostDropped,
+	} {
+		ch <- d
+	}
+}
+
+// Collect the metrics at scrape time
+func (mc *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+
+	ch <- prometheus.MustNewConstMetric(mc.buildInfo, prometheus.GaugeValue, 1)
+
+	mc.collectPorts(ch)
+	mc.collectHost(ch)
+}
+
+func (mc *metricsCollector) collectPorts(ch chan<- prometheus.Metric) {
+
+	counter := func(d *prometheus.Desc, v uint64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, float64(v), labels...)
+	}
+	gauge := func(d *prometheus.Desc, v float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v, labels...)
+	}
+
+	snap := pktgen.stats.Snapshot()
+
+	for _, ps := range snap.Ports {
+		port := strconv.Itoa(ps.PortIndex)
+
+		counter(mc.portPkts, ps.RxPkts, port, "rx")
+		counter(mc.portPkts, ps.TxPkts, port, "tx")
+		counter(mc.portBytes, ps.RxBytes, port, "rx")
+		counter(mc.portBytes, ps.TxBytes, port, "tx")
+		counter(mc.portErrors, ps.RxErrors, port, "rx")
+		counter(mc.portErrors, ps.TxErrors, port, "tx")
+		gauge(mc.portPPS, float64(ps.RxPPS), port, "rx")
+		gauge(mc.portPPS, float64(ps.TxPPS), port, "tx")
+		gauge(mc.portMaxPPS, float64(ps.RxMaxPPS), port, "rx")
+		gauge(mc.portMaxPPS, float64(ps.TxMaxPPS), port, "tx")
+		gauge(mc.portMbits, ps.RxMbits, port, "rx")
+		gauge(mc.portMbits, ps.TxMbits, port, "tx")
+
+		sz := &ps.Sizes
+		for _, b := range []struct {
+			name string
+			val  uint64
+		}{
+			{"64", sz.Size64},
+			{"65-127", sz.Size65To127},
+			{"128-255", sz.Size128To255},
+			{"256-511", sz.Size256To511},
+			{"512-1023", sz.Size512To1023},
+			{"1024-1518", sz.Size1024To1518},
+			{"runt", sz.Runts},
+			{"jumbo", sz.Jumbos},
+			{"broadcast", sz.Broadcast},
+			{"multicast", sz.Multicast},
+			{"arp", sz.ARPs},
+			{"icmp", sz.ICMPs},
+		} {
+			counter(mc.portSizes, b.val, port, b.name)
+		}
+
+		// Prometheus histogram buckets are cumulative
+		buckets := make(map[float64]uint64, len(LatencyBuckets))
+		cumulative := uint64(0)
+		for i, bound := range LatencyBuckets {
+			if i < len(ps.Latency.Buckets) {
+				cumulative += ps.Latency.Buckets[i]
+			}
+			buckets[bound] = cumulative
+		}
+		ch <- prometheus.MustNewConstHistogram(mc.portLatency,
+			ps.Latency.Samples, ps.Latency.Total, buckets, port)
+	}
+}
+
+// cpuPercent returns the load of each lcore since the last scrape, the
+// first scrape returns the load since boot
+func (mc *metricsCollector) cpuPercent() ([]float64, error) {
+
+	times, err := cpu.Times(true)
+	if err != nil {
+		return nil, err
+	}
+
+	mc.cpuLock.Lock()
+	defer mc.cpuLock.Unlock()
+
+	percent := make([]float64, len(times))
+	for i, t := range times {
+		prev := cpu.TimesStat{}
+		if i < len(mc.cpuTimes) {
+			prev = mc.cpuTimes[i]
+		}
+		total := t.Total() - prev.Total()
+		busy := total - (t.Idle - prev.Idle)
+		if total > 0 && busy > 0 {
+			percent[i] = busy * 100.0 / total
+		}
+	}
+	mc.cpuTimes = times
+
+	return percent, nil
+}
+
+func (mc *metricsCollector) collectHost(ch chan<- prometheus.Metric) {
+
+	counter := func(d *prometheus.Desc, v uint64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, float64(v), labels...)
+	}
+	gauge := func(d *prometheus.Desc, v float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v, labels...)
+	}
+
+	if percent, err := mc.cpuPercent(); err != nil {
+		tlog.Log(metricsLog, "cpu percent: %v\n", err)
+	} else {
+		for i, p := range percent {
+			gauge(mc.cpuLoad, p, strconv.Itoa(i))
+		}
+	}
+
+	if v, err := mem.VirtualMemory(); err != nil {
+		tlog.Log(metricsLog, "virtual memory: %v\n", err)
+	} else {
+		gauge(mc.memory, float64(v.Total), "total")
+		gauge(mc.memory, float64(v.Free), "free")
+		gauge(mc.memory, float64(v.Used), "used")
+		gauge(mc.memory, float64(v.HugePageSize), "hugepage_size")
+		gauge(mc.hugepages, float64(v.HugePagesTotal), "total")
+		gauge(mc.hugepages, float64(v.HugePagesFree), "free")
+	}
+
+	ioCount, err := psnet.IOCounters(true)
+	if err != nil {
+		tlog.Log(metricsLog, "network IO Count: %v\n", err)
+		return
+	}
+	for _, k := range ioCount {
+		if k.Name == "lo" {
+			continue
+		}
+		counter(mc.hostPkts, k.PacketsRecv, k.Name, "rx")
+		counter(mc.hostPkts, k.PacketsSent, k.Name, "tx")
+		counter(mc.hostBytes, k.BytesRecv, k.Name, "rx")
+		counter(mc.hostBytes, k.BytesSent, k.Name, "tx")
+		counter(mc.hostErrors, k.Errin, k.Name, "rx")
+		counter(mc.hostErrors, k.Errout, k.Name, "tx")
+		counter(mc.hostDropped, k.Dropin, k.Name, "rx")
+		counter(mc.hostDropped, k.Dropout, k.Name, "tx")
+	}
+}
+
+// startMetricsServer serving /metrics on the given address i.e. :9100
+func startMetricsServer(addr string) (*http.Server, error) {
+
+	reg := prometheus.NewRegistry()
+
+	// Every metric carries the version of go-pktgen as a label
+	wrapped := prometheus.WrapRegistererWith(prometheus.Labels{"version": Version()}, reg)
+	if err := wrapped.Register(newMetricsCollector()); err != nil {
+		return nil, fmt.Errorf("metrics register failed: %w", err)
+	}
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("metrics listen on %s failed: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+
+	srv := &http.Server{Handler: mux}
+
+	go func() {
+		tlog.Log(metricsLog, "Metrics server listening on %s\n", lis.Addr())
+		if err := srv.Serve(lis); err != nil && err != http.ErrServerClosed {
+			tlog.ErrorPrintf("metrics server failed: %v\n", err)
+		}
+	}()
+
+	return srv, nil
+}
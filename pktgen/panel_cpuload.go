@@ -5,13 +5,20 @@ package main
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/rivo/tview"
-	"github.com/gdamore/tcell/v2"
 	"github.com/shirou/gopsutil/cpu"
 
+	"github.com/KeithWiles/go-pktgen/pkgs/cfg"
 	cz "github.com/KeithWiles/go-pktgen/pkgs/colorize"
+	"github.com/KeithWiles/go-pktgen/pkgs/cpudata"
+	"github.com/KeithWiles/go-pktgen/pkgs/graphdata"
+	"github.com/KeithWiles/go-pktgen/pkgs/keybind"
 	"github.com/KeithWiles/go-pktgen/pkgs/meter"
 	tab "github.com/KeithWiles/go-pktgen/pkgs/taborder"
 	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
@@ -22,17 +29,41 @@ type PageCPULoad struct {
 	topFlex   *tview.Flex
 	cpuInfo   *tview.TextView
 	cpuLayout *tview.Table
-	cpuInfo1  *tview.TextView
-	cpuInfo2  *tview.TextView
-	cpuInfo3  *tview.TextView
+	cpuLoad   *tview.TextView
 	tabOrder  *tab.Tab
-	percent   []float64
+	lock      sync.Mutex
+	percent   []float64            // Last load of each lcore
+	history   []*graphdata.Series  // Load history of each lcore
+	power     []cpudata.PowerState // Last power state of each lcore
+	prevPower []cpudata.PowerState // Power state of the sample before
+	elapsed   time.Duration        // Time between the power states
+	sampled   time.Time            // Time of the last sample
 	meter     *meter.Meter
+	colWidth  int           // Width of a column of the load window
+	sortBy    int           // Index into cpuSorts
+	filter    int           // Index into cpuFilters
+	plan      *cpudata.Plan // Proposed lcores of the ports, nil if none
+	planErr   error         // Reason there is no plan
 }
 
 const (
 	cpuPanelName string = "CPU"
-	labelWidth int = 14
+
+	// cpuHistory is the number of load samples kept for each lcore, the
+	// load is sampled once a second.
+	cpuHistory = 60
+
+	sparkWidth   = 16   // Samples in the sparkline of an lcore
+	roleWidth    = 12   // Width of the role of an lcore
+	powerWidth   = 24   // Width of the frequency, governor, C-state and throttles
+	loadMinWidth = 78   // Minimum width of a column of the load window
+	loadMaxWidth = 120  // Maximum width of a column of the load window
+	cpuBusyLoad  = 10.0 // Load in percent of a busy lcore
+)
+
+var (
+	cpuSorts   = []string{"lcore", "load", "role"}
+	cpuFilters = []string{"all", "assigned", "busy"}
 )
 
 func init() {
@@ -49,6 +80,10 @@ func setupCPULoad() *PageCPULoad {
 
 	pg := &PageCPULoad{}
 
+	for i := 0; i < int(pktgen.cpuData.NumLogicalCores()); i++ {
+		pg.history = append(pg.history, graphdata.NewSeries(cpuHistory))
+	}
+
 	return pg
 }
 
@@ -57,7 +92,7 @@ func CPULoadPanelSetup(pages *tview.Pages, nextSlide func()) (pageName string, c
 
 	pg := setupCPULoad()
 
-	to := tab.New(cpuPanelName, pktgen.app)
+	to := newTabOrder(cpuPanelName)
 	pg.tabOrder = to
 
 	flex0 := tview.NewFlex().SetDirection(tview.FlexRow)
@@ -68,35 +103,46 @@ func CPULoadPanelSetup(pages *tview.Pages, nextSlide func()) (pageName string, c
 
 	pg.cpuInfo = CreateTextView(flex1, "CPU (c)", tview.AlignLeft, 0, 2, true)
 	pg.cpuLayout = CreateTableView(flex1, "CPU Layout (l)", tview.AlignLeft, 0, 1, false)
-	flex0.AddItem(flex1, 0, 1, true)
+	flex0.AddItem(flex1, 0, 2, true)
 
-	pg.cpuInfo1 = CreateTextView(flex2, "CPU Load (1)", tview.AlignLeft, 0, 1, true)
-	pg.cpuInfo2 = CreateTextView(flex2, "CPU Load (2)", tview.AlignLeft, 0, 1, false)
-	pg.cpuInfo3 = CreateTextView(flex2, "CPU Load (3)", tview.AlignLeft, 0, 1, false)
-	flex0.AddItem(flex2, 0, 4, true)
+	pg.cpuLoad = CreateTextView(flex2, "CPU Load (1)", tview.AlignLeft, 0, 1, true).
+		SetWrap(false)
+	flex0.AddItem(flex2, 0, 5, true)
 
-	to.Add("cpuInfo", pg.cpuInfo, 'c')
-	to.Add("cpuLayout", pg.cpuLayout, 'l')
+	to.Add("cpuInfo", pg.cpuInfo, 'c', "Select the CPU window")
+	to.Add("cpuLayout", pg.cpuLayout, 'l', "Select the CPU Layout window")
+	to.Add("cpuLoad", pg.cpuLoad, '1', "Select the CPU Load window")
 
-	to.Add("cpuInfo1", pg.cpuInfo1, '1')
-	to.Add("cpuInfo2", pg.cpuInfo2, '2')
-	to.Add("cpuInfo3", pg.cpuInfo3, '3')
+	keybind.Add(cpuPanelName, 'p', "Write the lcore plan into the configuration", func() {
+		pg.savePlan()
+	})
+	keybind.Add(cpuPanelName, 's', "Sort the lcores by lcore, load or role", func() {
+		pg.sortBy = (pg.sortBy + 1) % len(cpuSorts)
+		pg.displayLoad(pg.cpuLoad)
+	})
+	keybind.Add(cpuPanelName, 'f', "Show all, assigned or busy lcores", func() {
+		pg.filter = (pg.filter + 1) % len(cpuFilters)
+		pg.displayLoad(pg.cpuLoad)
+	})
 
 	to.SetInputDone()
 
 	pg.topFlex = flex0
 
 	// Setup static pages
+	pg.plan, pg.planErr = lcorePlan()
 	pg.displayCPU(pg.cpuInfo)
 	pg.displayLayout(pg.cpuLayout)
 
-	percent, err := cpu.Percent(0, true)
-	if err != nil {
+	if _, err := cpu.Percent(0, true); err != nil {
 		tlog.DoPrintf("Percent: %v\n", err)
 	}
-	pg.percent = percent
 
+	// The history is kept while the panel is hidden
 	pktgen.timers.Add(cpuPanelName, func(step int, ticks uint64) {
+		if step == 0 {
+			pg.sample()
+		}
 		if pg.topFlex.HasFocus() {
 			pktgen.app.QueueUpdateDraw(func() {
 				pg.displayCPULoad(step, ticks)
@@ -104,29 +150,11 @@ func CPULoadPanelSetup(pages *tview.Pages, nextSlide func()) (pageName string, c
 		}
 	})
 
-	modal := tview.NewModal().
-		SetText("This is the Help Box: cpuInfoHelp Thank you for asking for help! Press Esc to close.").
-		AddButtons([]string{"Got it"}).
-		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-			pages.HidePage("cpuInfoHelp")
-		})
-	AddModalPage("cpuInfoHelp", modal)
-
-	flex0.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		k := event.Rune()
-		switch k {
-        case '?':
-            tlog.DoPrintf("Question Mark! HasPage(%v)\n", pages.HasPage("cpuInfoHelp"))
-			pages.ShowPage("cpuInfoHelp")
-		}
-		return event
-	})
+	flex0.SetInputCapture(panelInput(to))
 
 	pg.meter = meter.New().
 		SetWidth(func() int {
-			_, _, width, _ := pg.cpuInfo1.GetInnerRect()
-
-			return width
+			return pg.colWidth
 		}).
 		SetDraw(func(mi *meter.Info) string {
 			var str string = ""
@@ -138,12 +166,11 @@ func CPULoadPanelSetup(pages *tview.Pages, nextSlide func()) (pageName string, c
 				}
 				str += l.Fn(l.Val)
 			}
-			str += fmt.Sprintf("[%s]\n", mi.Bar.Fn(mi.Bar.Val))
+			str += fmt.Sprintf("[%s]", mi.Bar.Fn(mi.Bar.Val))
 			return str
 		}).
 		SetRateLimits(0.0, 100.0)
 
-
 	return cpuPanelName, pg.topFlex
 }
 
@@ -151,18 +178,122 @@ func CPULoadPanelSetup(pages *tview.Pages, nextSlide func()) (pageName string, c
 func (pg *PageCPULoad) displayCPULoad(step int, ticks uint64) {
 
 	switch step {
-	case 0:
-		percent, err := cpu.Percent(0, true)
-		if err != nil {
-			tlog.DoPrintf("Percent: %v\n", err)
+	case 2:
+		pg.displayLoad(pg.cpuLoad)
+	}
+}
+
+// sample adds the load of each lcore since the last sample to the history
+func (pg *PageCPULoad) sample() {
+
+	percent, err := cpu.Percent(0, true)
+	if err != nil {
+		tlog.DoPrintf("Percent: %v\n", err)
+		return
+	}
+	now := time.Now()
+
+	power := make([]cpudata.PowerState, len(percent))
+	for i := range percent {
+		power[i] = cpudata.ReadPowerState(options.SysfsRoot, uint16(i))
+	}
+
+	pg.lock.Lock()
+	defer pg.lock.Unlock()
+
+	pg.percent = percent
+	for i, p := range percent {
+		if i < len(pg.history) {
+			pg.history[i].Add(now, p)
 		}
-		pg.percent = percent
+	}
 
-	case 2:
-		pg.displayLoadData(pg.cpuInfo1, 1)
-		pg.displayLoadData(pg.cpuInfo2, 2)
-		pg.displayLoadData(pg.cpuInfo3, 3)
+	pg.prevPower, pg.power = pg.power, power
+	if !pg.sampled.IsZero() {
+		pg.elapsed = now.Sub(pg.sampled)
+	}
+	pg.sampled = now
+}
+
+// powerStr returns the frequency, governor, most resident C-state and the
+// thermal throttle events of the lcore, a busy lcore below 90% of its
+// maximum frequency is a warning.
+func powerStr(ps, prev cpudata.PowerState, elapsed time.Duration, percent float64) string {
+
+	freq := cz.Value(fmt.Sprintf("%5s", "-"))
+	if ps.FreqKHz > 0 {
+		f := fmt.Sprintf("%4.2fG", float64(ps.FreqKHz)/1e6)
+		if percent >= cpuBusyLoad && ps.FreqKHz*10 < ps.MaxFreqKHz*9 {
+			freq = cz.Warning(f)
+		} else {
+			freq = cz.Value(f)
+		}
+	}
+
+	gov := "-"
+	if len(ps.Governor) > 0 {
+		gov = ps.Governor
+	}
+
+	state := fmt.Sprintf("%-8s", "-")
+	residency := ps.Residency(prev, elapsed)
+	best := -1
+	for i, r := range residency {
+		if r > 0 && (best < 0 || r > residency[best]) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		state = fmt.Sprintf("%-4.4s%3.0f%%", ps.Idle[best].Name, residency[best])
+	}
+
+	throttles := cz.Value(fmt.Sprintf("%4d", ps.Throttles()))
+	if ps.Throttles() > prev.Throttles() {
+		throttles = cz.Error(fmt.Sprintf("%4d", ps.Throttles()))
+	} else if ps.Throttles() > 0 {
+		throttles = cz.Warning(fmt.Sprintf("%4d", ps.Throttles()))
+	}
+
+	return fmt.Sprintf("%s %s %s %s", freq, cz.Label(fmt.Sprintf("%-4.4s", gov)), cz.Value(state), throttles)
+}
+
+// lcoreRoles returns the role of each lcore in the configuration, the main
+// lcore is the first of the lcores unless it is set.
+func lcoreRoles(c *cfg.Config) map[uint16]string {
+
+	roles := make(map[uint16]string)
+	if c == nil {
+		return roles
+	}
+
+	if c.MainLcore != nil {
+		roles[uint16(*c.MainLcore)] = "main"
+	} else if l, err := cpudata.ParseList(c.Lcores); err == nil && len(l) > 0 {
+		roles[l[0]] = "main"
 	}
+
+	add := func(lcores, role string) {
+		l, err := cpudata.ParseList(lcores)
+		if err != nil {
+			return
+		}
+		for _, lcore := range l {
+			if r, ok := roles[lcore]; ok {
+				roles[lcore] = r + "," + role
+			} else {
+				roles[lcore] = role
+			}
+		}
+	}
+	for _, p := range c.Ports {
+		if p.RxLcores == p.TxLcores {
+			add(p.RxLcores, fmt.Sprintf("RX/TX port %d", p.Port))
+			continue
+		}
+		add(p.RxLcores, fmt.Sprintf("RX port %d", p.Port))
+		add(p.TxLcores, fmt.Sprintf("TX port %d", p.Port))
+	}
+	return roles
 }
 
 // Display the CPU information
@@ -170,30 +301,134 @@ func (pg *PageCPULoad) displayCPU(view *tview.TextView) {
 	str := ""
 
 	cd := pktgen.cpuData
-	str += fmt.Sprintf("CPU   Vendor   : %s\n", cz.GoldenRod(cd.CpuInfo(0).VendorID, -14))
-	str += fmt.Sprintf("      Model    : %s\n\n", cz.MediumSpringGreen(cd.CpuInfo(0).ModelName))
-	str += fmt.Sprintf("Cores Logical  : %s\n", cz.Yellow(cd.NumLogicalCores(), -6))
-	str += fmt.Sprintf("      Physical : %s\n", cz.Yellow(cd.NumPhysicalCores(), -6))
-	str += fmt.Sprintf("      Threads  : %s\n", cz.Yellow(cd.NumHyperThreads(), -6))
-	str += fmt.Sprintf("      Sockets  : %s\n", cz.Yellow(cd.NumSockets()))
+	str += fmt.Sprintf("CPU   Vendor   : %s\n", cz.Value(cd.CpuInfo(0).VendorID, -14))
+	str += fmt.Sprintf("      Model    : %s\n\n", cz.Value(cd.CpuInfo(0).ModelName))
+	str += fmt.Sprintf("Cores Logical  : %s\n", cz.Value(cd.NumLogicalCores(), -6))
+	str += fmt.Sprintf("      Physical : %s\n", cz.Value(cd.NumPhysicalCores(), -6))
+	str += fmt.Sprintf("      Threads  : %s\n", cz.Value(cd.NumHyperThreads(), -6))
+	str += fmt.Sprintf("      Sockets  : %s\n\n", cz.Value(cd.NumSockets()))
+
+	// The plan is highlighted in the CPU Layout window
+	if pg.plan == nil {
+		str += fmt.Sprintf("Plan  %s\n", cz.Error(fmt.Sprintf("no lcore plan, %v", pg.planErr)))
+	} else {
+		str += fmt.Sprintf("Plan  Lcores   : %s main %s\n", cz.Value(cpudata.FormatList(pg.plan.Lcores())),
+			cz.Value(pg.plan.Main))
+		str += fmt.Sprintf("      Mapping  : %s, p to save\n", cz.Value(tview.Escape(planMapping(pg.plan))))
+		for _, w := range pg.plan.Warnings {
+			str += fmt.Sprintf("      %s\n", cz.Warning(w))
+		}
+	}
 
 	view.SetText(str)
 	view.ScrollToBeginning()
 }
 
-// Build up a string for displaying the CPU layout window
-func buildStr(a []uint16, width int) string {
+// nodeSocket returns the socket of the lcores of the NUMA node
+func nodeSocket(nodes cpudata.NodeMap, node int) (int, bool) {
+
+	for _, l := range nodes[node] {
+		if s, ok := pktgen.cpuData.Socket(l); ok {
+			return int(s), true
+		}
+	}
+	return -1, false
+}
+
+// lcorePlan plans the lcores of the ports with a device or a configuration,
+// the socket of the port is the socket of the NUMA node of the device.
+func lcorePlan() (*cpudata.Plan, error) {
+
+	c := loadedConfig()
+	devs := portDevices(bindInfo())
+	nodes := cpudata.ReadNodes(options.SysfsRoot)
+
+	ports := []cpudata.PortLcores{}
+	for port := 0; port < pktgen.portCnt; port++ {
+		dev, ok := devs[port]
+		if !ok && (c == nil || c.Port(port) == nil) {
+			continue
+		}
+
+		p := &cfg.PortInfo{Port: port}
+		portStartup(c, p)
+
+		// A port has at least one queue of each kind
+		pl := cpudata.PortLcores{Port: port, Socket: -1, RxQueues: 1, TxQueues: 1}
+		if p.RxQueues > 1 {
+			pl.RxQueues = int(p.RxQueues)
+		}
+		if p.TxQueues > 1 {
+			pl.TxQueues = int(p.TxQueues)
+		}
+		if ok {
+			if node, err := strconv.Atoi(dev.NumaNode); err == nil {
+				pl.Socket, _ = nodeSocket(nodes, node)
+			}
+		}
+		ports = append(ports, pl)
+	}
+
+	return pktgen.cpuData.Plan(ports)
+}
+
+// savePlan writes the plan into the configuration file
+func (pg *PageCPULoad) savePlan() {
+
+	if pg.plan == nil {
+		showMessage(fmt.Sprintf("No lcore plan: %v", pg.planErr))
+		return
+	}
+	if err := savePlan(pg.plan); err != nil {
+		showMessage(fmt.Sprintf("Save of the lcore plan failed: %v", err))
+		return
+	}
+	showMessage(fmt.Sprintf("Lcores %s and port mapping %s saved to %s",
+		cpudata.FormatList(pg.plan.Lcores()), planMapping(pg.plan), pktgen.cfgPath))
+}
+
+// planMapping returns the port mapping of the plan
+func planMapping(plan *cpudata.Plan) string {
+
+	if m := plan.String(); len(m) > 0 {
+		return m
+	}
+	return "no ports"
+}
+
+// Build up a string for displaying the CPU layout window, the lcores of the
+// plan are highlighted and followed by their assignment.
+func buildStr(a []uint16, width int, plan *cpudata.Plan) string {
 
 	str := "{"
+	labels := []string{}
 
 	for k, v := range a {
-		str += cz.Green(v, width)
+		if as, ok := lookupPlan(plan, v); ok {
+			str += cz.Colorize(cz.RoleColor(cz.RoleRate), v, width, 0, "", "", "r")
+			labels = append(labels, as.Label())
+		} else {
+			str += cz.Value(v, width)
+		}
 		if k < (len(a) - 1) {
 			str += " /"
 		}
 	}
+	str += " }"
 
-	return str + " }"
+	if len(labels) > 0 {
+		str += " " + cz.Rate(strings.Join(labels, ","))
+	}
+	return str
+}
+
+// lookupPlan returns the assignment of the lcore in the plan
+func lookupPlan(plan *cpudata.Plan, lcore uint16) (cpudata.Assignment, bool) {
+
+	if plan == nil {
+		return cpudata.Assignment{}, false
+	}
+	return plan.Lookup(lcore)
 }
 
 // Display the CPU layout data
@@ -201,15 +436,15 @@ func (pg *PageCPULoad) displayLayout(view *tview.Table) {
 
 	cd := pktgen.cpuData
 
-	str := cz.LightBlue(" Core", -5)
-	tableCell := tview.NewTableCell(cz.YellowGreen(str)).
+	str := cz.Header(" Core", -5)
+	tableCell := tview.NewTableCell(str).
 		SetAlign(tview.AlignLeft).
 		SetSelectable(false)
 	view.SetCell(0, 0, tableCell)
 
 	for k, s := range cd.Sockets() {
-		str = cz.LightBlue(fmt.Sprintf("Socket %d", s))
-		tableCell := tview.NewTableCell(cz.YellowGreen(str)).
+		str = cz.Header(fmt.Sprintf("Socket %d", s))
+		tableCell := tview.NewTableCell(str).
 			SetAlign(tview.AlignCenter).
 			SetSelectable(false)
 		view.SetCell(0, k+1, tableCell)
@@ -222,7 +457,7 @@ func (pg *PageCPULoad) displayLayout(view *tview.Table) {
 	for _, cid := range cd.Cores() {
 		col := int16(0)
 
-		tableCell := tview.NewTableCell(cz.Red(cid, 4)).
+		tableCell := tview.NewTableCell(cz.Label(cid, 4)).
 			SetAlign(tview.AlignLeft).
 			SetSelectable(false)
 		view.SetCell(int(row), int(col), tableCell)
@@ -233,11 +468,11 @@ func (pg *PageCPULoad) displayLayout(view *tview.Table) {
 			key := uint16(sid<<uint16(8)) | cid
 			v, ok := cd.CoreMapItem(key)
 			if ok {
-				str = fmt.Sprintf(" %s", buildStr(v, 3))
+				str = fmt.Sprintf(" %s", buildStr(v, 3, pg.plan))
 			} else {
-				str = fmt.Sprintf(" %s", strings.Repeat(".", 10))
+				str = fmt.Sprintf(" %s", cz.Value(strings.Repeat(".", 10)))
 			}
-			tableCell := tview.NewTableCell(cz.YellowGreen(str)).
+			tableCell := tview.NewTableCell(str).
 				SetAlign(tview.AlignLeft).
 				SetSelectable(false)
 			view.SetCell(int(row), int(col+1), tableCell)
@@ -248,48 +483,138 @@ func (pg *PageCPULoad) displayLayout(view *tview.Table) {
 	view.ScrollToBeginning()
 }
 
-// Grab the percent load data and display the meters
-func (pg *PageCPULoad) displayLoadData(view *tview.TextView, flg int) {
+// lcoreLoad is the load of an lcore in the load window
+type lcoreLoad struct {
+	lcore   uint16
+	role    string
+	percent float64
+	history []float64
+	power   string // Frequency, governor, C-state and throttles
+}
 
-	cd := pktgen.cpuData
-	num := int16(cd.NumLogicalCores()/3) + 1
+// loadColumns returns the number of columns and their width of the load
+// window, the lcores fill the height of the window before a column is added.
+func loadColumns(n, width, height int) (int, int) {
 
-	switch flg {
-	case 1:
-		pg.displayLoad(pg.percent, 0, num, view)
-	case 2:
-		pg.displayLoad(pg.percent, num, num*int16(2), view)
-	case 3:
-		pg.displayLoad(pg.percent, num*int16(2), cd.NumLogicalCores(), view)
+	cols := (width + 2) / (loadMinWidth + 2)
+	if height > 0 {
+		if need := (n + height - 1) / height; need < cols {
+			cols = need
+		}
+	}
+	if cols < 1 {
+		cols = 1
+	}
+	colWidth := (width - 2*(cols-1)) / cols
+	if colWidth > loadMaxWidth {
+		colWidth = loadMaxWidth
 	}
+	return cols, colWidth
 }
 
-// Display the load meters
-func (pg *PageCPULoad) displayLoad(percent []float64, start, end int16, view *tview.TextView) {
+// loads returns the sorted and filtered loads of the lcores
+func (pg *PageCPULoad) loads() []lcoreLoad {
 
-	_, _, width, _ := view.GetInnerRect()
+	roles := lcoreRoles(loadedConfig())
 
-	width -= labelWidth
-	if width <= 0 {
-		return
+	pg.lock.Lock()
+	defer pg.lock.Unlock()
+
+	loads := []lcoreLoad{}
+	for i, p := range pg.percent {
+		l := lcoreLoad{lcore: uint16(i), role: roles[uint16(i)], percent: p}
+		if i < len(pg.power) && i < len(pg.prevPower) {
+			l.power = powerStr(pg.power[i], pg.prevPower[i], pg.elapsed, p)
+		} else {
+			l.power = fmt.Sprintf("%-*s", powerWidth, "")
+		}
+		if i < len(pg.history) {
+			for _, smp := range pg.history[i].Samples() {
+				l.history = append(l.history, smp.Value)
+			}
+		}
+
+		switch cpuFilters[pg.filter] {
+		case "assigned":
+			if len(l.role) == 0 {
+				continue
+			}
+		case "busy":
+			if p < cpuBusyLoad {
+				continue
+			}
+		}
+		loads = append(loads, l)
 	}
-	str := ""
 
-	str += fmt.Sprintf("%s\n", cz.Orange("Core Percent          Load Meter"))
-
-	for i := start; i < end; i++ {
-		str += pg.meter.Draw(percent[i], &meter.Info{
-			Labels: []*meter.LabelInfo{
-				{Val: fmt.Sprintf("%3d", i), Fn: nil},
-				{Val: ":", Fn: nil},
-				{Val: fmt.Sprintf("%5.1f", percent[i]), Fn: cz.Red},
-				{Val: "%", Fn: nil},
-			},
-			Bar: &meter.LabelInfo{Val: "", Fn: cz.MediumSpringGreen},
+	switch cpuSorts[pg.sortBy] {
+	case "load":
+		sort.SliceStable(loads, func(i, j int) bool { return loads[i].percent > loads[j].percent })
+	case "role":
+		// The assigned lcores first in the order of their roles
+		sort.SliceStable(loads, func(i, j int) bool {
+			ri, rj := loads[i].role, loads[j].role
+			if len(ri) == 0 || len(rj) == 0 {
+				return len(ri) > len(rj)
+			}
+			return ri < rj
 		})
 	}
-	str = str[:len(str) - 1]	// Strip the last newline character
+	return loads
+}
 
-	view.SetText(str)
-	view.ScrollToBeginning()
+// Display the load of the lcores with their role, load history and meter
+func (pg *PageCPULoad) displayLoad(view *tview.TextView) {
+
+	loads := pg.loads()
+
+	view.SetTitle(TitleColor(fmt.Sprintf("CPU Load (1) %d of %d lcores, sort %s, filter %s, s-Sort f-Filter",
+		len(loads), len(pg.percent), cpuSorts[pg.sortBy], cpuFilters[pg.filter])))
+
+	_, _, width, height := view.GetInnerRect()
+	height-- // The header line
+
+	cols, colWidth := loadColumns(len(loads), width, height)
+
+	// The meter is the rest of the column after the lcore, role and sparkline
+	prefixWidth := 3 + 1 + roleWidth + 1 + powerWidth + 1 + sparkWidth + 1
+	pg.colWidth = colWidth - prefixWidth
+	if pg.colWidth <= 8 {
+		return
+	}
+
+	header := fmt.Sprintf("%-*s", colWidth, fmt.Sprintf("%3s %-*s %-*s %-*s %s",
+		"Cpu", roleWidth, "Role", powerWidth, " Freq Gov  C-state  Thr", sparkWidth, "History", "Load"))
+	str := cz.Header(strings.TrimRight(strings.Repeat(header+"  ", cols), " ")) + "\n"
+
+	rows := (len(loads) + cols - 1) / cols
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			i := c*rows + r
+			if i >= len(loads) {
+				break
+			}
+			l := loads[i]
+
+			role := cz.Label(fmt.Sprintf("%-*s", roleWidth, "idle"))
+			if len(l.role) > 0 {
+				role = cz.Rate(fmt.Sprintf("%-*.*s", roleWidth, roleWidth, l.role))
+			}
+			if c > 0 {
+				str += "  "
+			}
+			str += fmt.Sprintf("%3d %s %s %s ", l.lcore, role, l.power,
+				cz.MediumSpringGreen(meter.Sparkline(l.history, 0, 100, sparkWidth)))
+			str += pg.meter.Draw(l.percent, &meter.Info{
+				Labels: []*meter.LabelInfo{
+					{Val: fmt.Sprintf("%5.1f", l.percent), Fn: cz.Red},
+					{Val: "%", Fn: nil},
+				},
+				Bar: &meter.LabelInfo{Val: "", Fn: cz.MediumSpringGreen},
+			})
+		}
+		str += "\n"
+	}
+
+	view.SetText(strings.TrimSuffix(str, "\n"))
 }
@@ -0,0 +1,272 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2022 Intel Corporation
+
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/KeithWiles/go-pktgen/pkgs/asciichart"
+	cz "github.com/KeithWiles/go-pktgen/pkgs/colorize"
+	"github.com/KeithWiles/go-pktgen/pkgs/graphdata"
+	"github.com/KeithWiles/go-pktgen/pkgs/keybind"
+	tab "github.com/KeithWiles/go-pktgen/pkgs/taborder"
+	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
+)
+
+// PageGraphs - Data for the throughput graphs page
+type PageGraphs struct {
+	topFlex   *tview.Flex
+	portTable *tview.Table
+	chart     *tview.TextView
+	to        *tab.Tab
+	selected  []bool // Ports displayed in the chart
+	metric    int    // Index into graphMetrics
+	window    int    // Index into graphWindows
+	style     int    // Index into graphStyles
+	reduce    int    // Index into graphReduces
+	lock      sync.Mutex
+	last      time.Time     // Timestamp of the last sampled statistics
+	history   []portHistory // Rates of each port
+	graphs    *graphdata.GraphInfo
+}
+
+// portHistory is the rates of a port at each statistics collection
+type portHistory struct {
+	rxPPS, txPPS     *graphdata.Series
+	rxMbits, txMbits *graphdata.Series
+}
+
+const (
+	graphsPanelName string = "Graphs"
+	graphsLog       string = "GraphsLogID"
+
+	// graphHistory is the number of samples kept for each port, the
+	// statistics are collected once a second.
+	graphHistory = 60 * 60
+)
+
+var (
+	graphMetrics = []string{"pps", "Mbit/s"}
+	graphStyles  = []string{"line", "braille", "filled"}
+	graphReduces = []string{"avg", "min", "max"}
+	graphColors  = []string{"lightgreen", "deeppink", "lightblue", "orange", "yellow", "cyan", "lightcoral", "wheat"}
+	graphWindows = []time.Duration{30 * time.Second, time.Minute, 5 * time.Minute, 15 * time.Minute, time.Hour}
+)
+
+func init() {
+	tlog.Register(graphsLog)
+}
+
+// Printf - send message to the ttylog interface
+func (pg *PageGraphs) Printf(format string, a ...interface{}) {
+	tlog.Log(graphsLog, fmt.Sprintf("%T.", pg)+format, a...)
+}
+
+// setupGraphs - setup and init the graphs page
+func setupGraphs() *PageGraphs {
+
+	pg := &PageGraphs{
+		selected: make([]bool, pktgen.portCnt),
+		history:  make([]portHistory, pktgen.portCnt),
+	}
+	pg.selected[0] = true
+
+	for i := range pg.history {
+		pg.history[i] = portHistory{
+			rxPPS:   graphdata.NewSeries(graphHistory),
+			txPPS:   graphdata.NewSeries(graphHistory),
+			rxMbits: graphdata.NewSeries(graphHistory),
+			txMbits: graphdata.NewSeries(graphHistory),
+		}
+	}
+
+	return pg
+}
+
+// GraphsPanelSetup setup
+func GraphsPanelSetup(pages *tview.Pages, nextSlide func()) (pageName string, content tview.Primitive) {
+
+	pg := setupGraphs()
+
+	pg.to = newTabOrder(graphsPanelName)
+
+	flex0 := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex1 := tview.NewFlex().SetDirection(tview.FlexColumn)
+
+	TitleBox(flex0)
+
+	pg.portTable = CreateTableView(flex1, "Ports (p)", tview.AlignLeft, 14, 0, true).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+
+	pg.chart = CreateTextView(flex1, "Graph (g)", tview.AlignLeft, 0, 1, false)
+
+	flex0.AddItem(flex1, 0, 1, true)
+
+	pg.to.Add("graphPorts", pg.portTable, 'p', "Select the Ports window")
+	pg.to.Add("graphChart", pg.chart, 'g', "Select the Graph window")
+	pg.to.SetInputDone()
+
+	pg.topFlex = flex0
+
+	pg.displayPorts()
+
+	pktgen.timers.Add(graphsPanelName, func(step int, ticks uint64) {
+		pg.sample()
+
+		if step == 2 && pg.topFlex.HasFocus() {
+			pktgen.app.QueueUpdateDraw(func() {
+				pg.displayPorts()
+				pg.displayChart()
+			})
+		}
+	})
+
+	togglePort := func() {
+		if row, _ := pg.portTable.GetSelection(); row > 0 {
+			pg.selected[row-1] = !pg.selected[row-1]
+			pg.displayPorts()
+			pg.displayChart()
+		}
+	}
+	scope := keybind.Scope(graphsPanelName, "graphPorts")
+	keybind.Add(scope, ' ', "Show or hide the selected port in the graph", togglePort)
+	keybind.Add(scope, tcell.KeyEnter, "Show or hide the selected port in the graph", togglePort)
+
+	keybind.Add(graphsPanelName, 'm', "Change the metric, packets or Mbits per second", func() {
+		pg.metric = (pg.metric + 1) % len(graphMetrics)
+		pg.displayChart()
+	})
+	keybind.Add(graphsPanelName, 'w', "Increase the time window", func() {
+		pg.window = (pg.window + 1) % len(graphWindows)
+		pg.displayChart()
+	})
+	keybind.Add(graphsPanelName, 'W', "Decrease the time window", func() {
+		pg.window = (pg.window - 1 + len(graphWindows)) % len(graphWindows)
+		pg.displayChart()
+	})
+	keybind.Add(graphsPanelName, 'b', "Change the chart style", func() {
+		pg.style = (pg.style + 1) % len(graphStyles)
+		pg.displayChart()
+	})
+	keybind.Add(graphsPanelName, 'r', "Show the average, minimum or maximum of zoomed out samples", func() {
+		pg.reduce = (pg.reduce + 1) % len(graphReduces)
+		pg.displayChart()
+	})
+	flex0.SetInputCapture(panelInput(pg.to))
+
+	return graphsPanelName, pg.topFlex
+}
+
+// sample adds the rates of the last statistics collection to the history,
+// the timer calls it on every tick and a new collection is added once.
+func (pg *PageGraphs) sample() {
+
+	snap := pktgen.stats.Snapshot()
+
+	pg.lock.Lock()
+	defer pg.lock.Unlock()
+
+	if snap.Timestamp.IsZero() || !snap.Timestamp.After(pg.last) {
+		return
+	}
+	pg.last = snap.Timestamp
+
+	for port, h := range pg.history {
+		ps := &snap.Ports[port]
+
+		h.rxPPS.Add(snap.Timestamp, float64(ps.RxPPS))
+		h.txPPS.Add(snap.Timestamp, float64(ps.TxPPS))
+		h.rxMbits.Add(snap.Timestamp, ps.RxMbits)
+		h.txMbits.Add(snap.Timestamp, ps.TxMbits)
+	}
+}
+
+// displayPorts shows the ports with a mark for the ports in the chart
+func (pg *PageGraphs) displayPorts() {
+
+	table := pg.portTable
+
+	row := TableSetHeaders(table, 0, 0, []string{cz.Header("Port", 4), cz.Header("Show", 5)})
+
+	for port := 0; port < pktgen.portCnt; port++ {
+		mark := cz.LightBlue("off", 5)
+		if pg.selected[port] {
+			mark = cz.DeepPink("on", 5)
+		}
+		col := TableCellSelect(table, row, 0, cz.Label(port, 4))
+		TableCellSet(table, row, col, mark)
+		row++
+	}
+}
+
+// series returns the history of the metric of a port
+func (pg *PageGraphs) series(port int, rx bool) *graphdata.Series {
+
+	h := pg.history[port]
+	switch {
+	case pg.metric == 0 && rx:
+		return h.rxPPS
+	case pg.metric == 0:
+		return h.txPPS
+	case rx:
+		return h.rxMbits
+	default:
+		return h.txMbits
+	}
+}
+
+// displayChart draws the RX and TX history of the selected ports in one chart
+func (pg *PageGraphs) displayChart() {
+
+	view := pg.chart
+
+	view.SetTitle(TitleColor(fmt.Sprintf("Graph (g) %s %s, last %v, m-Metric w/W-Window b-Style r-Reduce",
+		graphReduces[pg.reduce], graphMetrics[pg.metric], graphWindows[pg.window])))
+
+	var names, colors []string
+	var ports []int
+	var rx []bool
+	for port, sel := range pg.selected {
+		if !sel {
+			continue
+		}
+		for _, dir := range []string{"RX", "TX"} {
+			names = append(names, fmt.Sprintf("Port %d %s", port, dir))
+			colors = append(colors, graphColors[len(colors)%len(graphColors)])
+			ports = append(ports, port)
+			rx = append(rx, dir == "RX")
+		}
+	}
+	if len(names) == 0 {
+		view.SetText(cz.Warning("No ports selected"))
+		return
+	}
+
+	if pg.graphs == nil || pg.graphs.NumGraphs() != len(names) {
+		pg.graphs = graphdata.NewGraph(len(names)).
+			SetFieldWidth(12).
+			SetCombined(true)
+	}
+
+	mode := asciichart.PlotLine
+	if pg.style > 0 {
+		mode = asciichart.PlotBraille
+	}
+	pg.graphs.SetMode(mode).SetFill(graphStyles[pg.style] == "filled")
+
+	for i, gd := range pg.graphs.Graphs() {
+		gd.SetName(names[i]).SetColor(colors[i]).
+			SetSeries(pg.series(ports[i], rx[i])).
+			SetWindow(graphWindows[pg.window]).
+			SetReduce(graphdata.Reduce(pg.reduce))
+	}
+
+	view.SetText(pg.graphs.MakeChart(view))
+}
@@ -0,0 +1,287 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2022 Intel Corporation
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/rivo/tview"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	cz "github.com/KeithWiles/go-pktgen/pkgs/colorize"
+	"github.com/KeithWiles/go-pktgen/pkgs/cpudata"
+	"github.com/KeithWiles/go-pktgen/pkgs/devbind"
+	"github.com/KeithWiles/go-pktgen/pkgs/irqs"
+	tab "github.com/KeithWiles/go-pktgen/pkgs/taborder"
+	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
+)
+
+// PageInterrupts - Data for the NIC interrupt page
+type PageInterrupts struct {
+	topFlex *tview.Flex
+	to      *tab.Tab
+	table   *tview.Table
+	summary *tview.TextView
+	info    *irqs.Info
+	nodes   cpudata.NodeMap // Lcores of each NUMA node
+	prev    map[int]uint64  // Total count of each interrupt at the last display
+	last    time.Time       // Time of the last display
+}
+
+// nicIRQs is the interrupts of a network device
+type nicIRQs struct {
+	dev    *devbind.DeviceClass
+	node   int // NUMA node of the device, -1 if unknown
+	irqs   []*irqs.IRQ
+	remote map[int][]uint16 // Lcores of the affinity of each IRQ on another node
+}
+
+const (
+	interruptsPanelName string = "Interrupts"
+)
+
+func init() {
+	tlog.Register("InterruptsLogID")
+}
+
+// Printf - send message to the ttylog interface
+func (pi *PageInterrupts) Printf(format string, a ...interface{}) {
+	tlog.Log("InterruptsLogID", fmt.Sprintf("%T.", pi)+format, a...)
+}
+
+// setupInterrupts - setup and init the interrupts page
+func setupInterrupts() *PageInterrupts {
+
+	pi := &PageInterrupts{
+		info:  irqs.New(options.SysfsRoot, options.ProcRoot),
+		nodes: cpudata.ReadNodes(options.SysfsRoot),
+		prev:  make(map[int]uint64),
+	}
+
+	return pi
+}
+
+// InterruptsPanelSetup setup the NIC interrupt page
+func InterruptsPanelSetup(pages *tview.Pages, nextSlide func()) (pageName string, content tview.Primitive) {
+
+	pi := setupInterrupts()
+
+	to := newTabOrder(interruptsPanelName)
+	pi.to = to
+
+	flex0 := tview.NewFlex().SetDirection(tview.FlexRow)
+
+	TitleBox(flex0)
+
+	pi.table = CreateTableView(flex0, "NIC Interrupts (i)", tview.AlignLeft, 0, 3, true).
+		SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetSeparator(tview.Borders.Vertical)
+	pi.summary = CreateTextView(flex0, "Placement (p)", tview.AlignLeft, 0, 1, false)
+
+	to.Add("nicInterrupts", pi.table, 'i', "Select the NIC Interrupts window")
+	to.Add("placement", pi.summary, 'p', "Select the Placement window")
+
+	to.SetInputDone()
+
+	pi.topFlex = flex0
+
+	flex0.SetInputCapture(panelInput(to))
+
+	pi.displayInterrupts()
+
+	pktgen.timers.Add(interruptsPanelName, func(step int, ticks uint64) {
+		if step == 0 && pi.topFlex.HasFocus() {
+			pktgen.app.QueueUpdateDraw(func() {
+				pi.displayInterrupts()
+			})
+		}
+	})
+
+	return interruptsPanelName, pi.topFlex
+}
+
+// collectInterrupts returns the interrupts of the network devices in PCI
+// order with the lcores of each interrupt on another node than the device,
+// the error of the devices skipped is returned with the other devices
+func (pi *PageInterrupts) collectInterrupts() ([]*nicIRQs, error) {
+
+	db := bindInfo()
+
+	devs := db.FindDevicesByDeviceClass("Network", db.Groups[devbind.NetworkGroup])
+	slots := make([]string, 0, len(devs))
+	for slot := range devs {
+		slots = append(slots, slot)
+	}
+	sort.Strings(slots)
+
+	devIRQs, err := pi.info.Devices(slots)
+	if devIRQs == nil {
+		return nil, err
+	}
+
+	nics := []*nicIRQs{}
+	for _, slot := range slots {
+		if _, ok := devIRQs[slot]; !ok {
+			continue
+		}
+		n := &nicIRQs{dev: devs[slot], node: -1, irqs: devIRQs[slot], remote: make(map[int][]uint16)}
+
+		if node, err := strconv.Atoi(n.dev.NumaNode); err == nil {
+			n.node = node
+		}
+		if n.node >= 0 {
+			for _, irq := range n.irqs {
+				if r := irq.Remote(n.node, pi.nodes); len(r) > 0 {
+					n.remote[irq.Number] = r
+				}
+			}
+		}
+		nics = append(nics, n)
+	}
+	return nics, err
+}
+
+// displayInterrupts displays the counts, rates and affinity of the interrupts
+// of each network device
+func (pi *PageInterrupts) displayInterrupts() {
+
+	nics, err := pi.collectInterrupts()
+	if nics == nil {
+		pi.Printf("Interrupts: %v\n", err)
+		pi.summary.SetText(cz.Error(err.Error()))
+		return
+	}
+
+	now := time.Now()
+	secs := now.Sub(pi.last).Seconds()
+	if pi.last.IsZero() {
+		secs = 0
+	}
+
+	table := pi.table
+
+	titles := []string{
+		cz.Header("Device"),
+		cz.Header("Interface"),
+		cz.Header("Node"),
+		cz.Header("IRQ"),
+		cz.Header("Name"),
+		cz.Header("Count"),
+		cz.Header("Rate/s"),
+		cz.Header("Affinity"),
+		cz.Header("Remote Lcores"),
+	}
+	row := TableSetHeaders(table, 0, 0, titles)
+
+	p := message.NewPrinter(language.English)
+	prev := make(map[int]uint64)
+
+	for _, n := range nics {
+		for i, irq := range n.irqs {
+			total := irq.Total()
+			prev[irq.Number] = total
+
+			slot, iface, node := "", "", ""
+			if i == 0 {
+				slot, iface, node = n.dev.Slot, n.dev.Interface, "-"
+				if n.node >= 0 {
+					node = strconv.Itoa(n.node)
+				}
+			}
+
+			rate := ""
+			if last, ok := pi.prev[irq.Number]; ok && secs > 0 && total >= last {
+				rate = p.Sprintf("%.0f", float64(total-last)/secs)
+			}
+
+			remote := ""
+			if r, ok := n.remote[irq.Number]; ok {
+				remote = cz.Warning(cpudata.FormatList(r))
+			}
+
+			SetCell(table, row, 0, cz.Address(slot), tview.AlignLeft, true)
+			SetCell(table, row, 1, cz.Label(iface), tview.AlignLeft, true)
+			SetCell(table, row, 2, cz.Value(node), tview.AlignRight, true)
+			SetCell(table, row, 3, cz.Label(irq.Number), tview.AlignRight, true)
+			SetCell(table, row, 4, cz.Value(tview.Escape(irq.Name)), tview.AlignLeft, true)
+			SetCell(table, row, 5, cz.Value(p.Sprintf("%d", total)), tview.AlignRight, true)
+			SetCell(table, row, 6, cz.Rate(rate), tview.AlignRight, true)
+			SetCell(table, row, 7, cz.Value(cpudata.FormatList(irq.Affinity)), tview.AlignLeft, true)
+			SetCell(table, row, 8, remote, tview.AlignLeft, true)
+			row++
+		}
+	}
+	for table.GetRowCount() > row {
+		table.RemoveRow(table.GetRowCount() - 1)
+	}
+
+	pi.prev = prev
+	pi.last = now
+
+	pi.displayPlacement(nics, err)
+}
+
+// displayPlacement displays the interrupts of each device on another node
+// and how to move them to the node of the device, followed by the error of
+// the devices skipped
+func (pi *PageInterrupts) displayPlacement(nics []*nicIRQs, skipped error) {
+
+	str := ""
+	for _, n := range nics {
+		name := n.dev.Slot
+		if len(n.dev.Interface) > 0 {
+			name += " " + n.dev.Interface
+		}
+
+		switch {
+		case len(n.irqs) == 0:
+			str += fmt.Sprintf("%s: %s\n", cz.Label(name), cz.Value("no interrupts"))
+		case n.node < 0:
+			str += fmt.Sprintf("%s: %s\n", cz.Label(name),
+				cz.Value(fmt.Sprintf("%d interrupts, the NUMA node of the device is unknown", len(n.irqs))))
+		case len(n.remote) == 0:
+			str += fmt.Sprintf("%s: %s\n", cz.Label(name),
+				cz.Rate(fmt.Sprintf("%d interrupts on node %d", len(n.irqs), n.node)))
+		default:
+			str += fmt.Sprintf("%s: %s\n", cz.Label(name),
+				cz.Warning(fmt.Sprintf("%d of %d interrupts may run on lcores of another node than node %d",
+					len(n.remote), len(n.irqs), n.node)))
+
+			numbers := []int{}
+			for irq := range n.remote {
+				numbers = append(numbers, irq)
+			}
+			sort.Ints(numbers)
+			str += fmt.Sprintf("     %s\n", cz.Value(fmt.Sprintf(
+				"fix: stop irqbalance and echo %s > /proc/irq/<irq>/smp_affinity_list for IRQs %s",
+				cpudata.FormatList(pi.nodes[n.node]), formatInts(numbers))))
+		}
+	}
+	if len(nics) == 0 {
+		str = cz.Warning("No network devices found") + "\n"
+	}
+	if skipped != nil {
+		pi.Printf("Interrupts: %v\n", skipped)
+		str += cz.Warning("Skipped " + skipped.Error())
+	}
+	pi.summary.SetText(str)
+}
+
+// formatInts returns the numbers as a comma separated list
+func formatInts(numbers []int) string {
+
+	str := ""
+	for i, n := range numbers {
+		if i > 0 {
+			str += ","
+		}
+		str += strconv.Itoa(n)
+	}
+	return str
+}
@@ -0,0 +1,264 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2022 Intel Corporation
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	cz "github.com/KeithWiles/go-pktgen/pkgs/colorize"
+	"github.com/KeithWiles/go-pktgen/pkgs/keybind"
+	tab "github.com/KeithWiles/go-pktgen/pkgs/taborder"
+	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
+)
+
+// PageLog - Data for the log viewer page
+type PageLog struct {
+	topFlex *tview.Flex
+	idTable *tview.Table
+	filter  *tview.Form
+	logView *tview.TextView
+	to      *tab.Tab
+	lock    sync.Mutex
+	ids     []string // Registered log ids shown in the id table
+	id      string   // Log id shown in the log view, empty for all ids
+	level   tlog.Level
+	search  string
+	seq     uint64 // Log sequence of the displayed entries
+}
+
+const (
+	logPanelName string = "Log"
+	logAllIDs    string = "All"
+)
+
+// setupLog - setup and init the log page
+func setupLog() *PageLog {
+
+	pg := &PageLog{level: tlog.LevelDebug}
+
+	return pg
+}
+
+// LogPanelSetup setup
+func LogPanelSetup(pages *tview.Pages, nextSlide func()) (pageName string, content tview.Primitive) {
+
+	pg := setupLog()
+
+	pg.to = newTabOrder(logPanelName)
+
+	flex0 := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex1 := tview.NewFlex().SetDirection(tview.FlexColumn)
+	flex2 := tview.NewFlex().SetDirection(tview.FlexRow)
+
+	TitleBox(flex0)
+
+	pg.idTable = CreateTableView(flex1, "Log IDs (i)", tview.AlignLeft, 28, 0, true).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+
+	pg.filter = CreateForm(flex2, "Filter (f)", tview.AlignLeft, 3, 0, false).
+		SetHorizontal(true)
+	pg.filter.SetBorderPadding(0, 0, 1, 1)
+	pg.logView = CreateTextView(flex2, "Log (l)", tview.AlignLeft, 0, 1, false)
+	pg.logView.SetWrap(false)
+
+	flex1.AddItem(flex2, 0, 1, false)
+	flex0.AddItem(flex1, 0, 1, true)
+
+	pg.setupFilter()
+
+	pg.to.Add("logIDs", pg.idTable, 'i', "Select the Log IDs window")
+	pg.to.Add("logFilter", pg.filter, 'f', "Select the Filter window")
+	pg.to.Add("logView", pg.logView, 'l', "Select the Log window")
+	pg.to.SetInputDone()
+
+	// The filter form gets all keys, the tab order keys are valid search text
+	keybind.Add(keybind.Scope(logPanelName, "logFilter"), tcell.KeyEscape, "Leave the filter")
+	pg.filter.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			pg.to.SetInputFocus('l')
+			return nil
+		}
+		return event
+	})
+
+	pg.topFlex = flex0
+
+	pg.displayIDs()
+
+	pktgen.timers.Add(logPanelName, func(step int, ticks uint64) {
+		if pg.topFlex.HasFocus() && tlog.Sequence() != pg.seq {
+			pktgen.app.QueueUpdateDraw(func() {
+				pg.displayIDs()
+				pg.displayLog()
+			})
+		}
+	})
+
+	toggleID := func() {
+		if row, _ := pg.idTable.GetSelection(); row > 0 && row <= len(pg.ids) {
+			id := pg.ids[row-1]
+			if state, err := tlog.State(id); err == nil {
+				tlog.SetState(id, !state)
+			}
+			pg.displayIDs()
+			pg.displayLog()
+		}
+	}
+	scope := keybind.Scope(logPanelName, "logIDs")
+	keybind.Add(scope, ' ', "Turn the selected log id on or off", toggleID)
+	keybind.Add(scope, tcell.KeyEnter, "Turn the selected log id on or off", toggleID)
+
+	keybind.Add(logPanelName, 'c', "Clear the log", func() {
+		tlog.ClearEntries()
+		pg.displayLog()
+	})
+
+	input := panelInput(pg.to)
+	flex0.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if pg.filter.HasFocus() {
+			return event
+		}
+		return input(event)
+	})
+
+	return logPanelName, pg.topFlex
+}
+
+// setupFilter adds the id, level and search fields to the filter form
+func (pg *PageLog) setupFilter() {
+
+	levels := []string{}
+	for _, l := range tlog.Levels() {
+		levels = append(levels, l.String())
+	}
+
+	pg.filter.AddDropDown("ID", []string{logAllIDs}, 0, func(option string, index int) {
+		pg.setFilter(func() {
+			pg.id = ""
+			if index > 0 {
+				pg.id = option
+			}
+		})
+	})
+	pg.filter.AddDropDown("Level", levels, 0, func(option string, index int) {
+		pg.setFilter(func() {
+			pg.level = tlog.Levels()[index]
+		})
+	})
+	pg.filter.AddInputField("Search", "", 30, nil, func(text string) {
+		pg.setFilter(func() {
+			pg.search = text
+		})
+	})
+}
+
+// setFilter changes the filter and displays the log again
+func (pg *PageLog) setFilter(change func()) {
+
+	pg.lock.Lock()
+	change()
+	pg.lock.Unlock()
+
+	if pg.logView != nil {
+		pg.displayLog()
+	}
+}
+
+// displayIDs shows the registered log ids and their state, the ids are also
+// the options of the id filter.
+func (pg *PageLog) displayIDs() {
+
+	table := pg.idTable
+
+	ids := tlog.IDs()
+	if len(ids) != len(pg.ids) {
+		table.Clear()
+
+		dd := pg.filter.GetFormItemByLabel("ID").(*tview.DropDown)
+		dd.SetOptions(append([]string{logAllIDs}, ids...), nil)
+
+		pg.lock.Lock()
+		current := 0
+		for i, id := range ids {
+			if id == pg.id {
+				current = i + 1
+			}
+		}
+		pg.lock.Unlock()
+		dd.SetCurrentOption(current)
+	}
+	pg.ids = ids
+
+	row := TableSetHeaders(table, 0, 0, []string{cz.Header("Log ID", -20), cz.Header("State", 5)})
+
+	for _, id := range ids {
+		mark := cz.LightBlue("off", 5)
+		if tlog.IsActive(id) {
+			mark = cz.DeepPink("on", 5)
+		}
+		col := TableCellSelect(table, row, 0, cz.Label(id, -20))
+		TableCellSet(table, row, col, mark)
+		row++
+	}
+}
+
+// levelColor returns the color of the level text of a log entry
+func levelColor(level tlog.Level) func(a interface{}, w ...interface{}) string {
+
+	switch level {
+	case tlog.LevelFatal, tlog.LevelError:
+		return cz.Error
+	case tlog.LevelWarn:
+		return cz.Warning
+	case tlog.LevelDebug:
+		return cz.LightBlue
+	}
+	return cz.Green
+}
+
+// displayLog shows the log entries matching the filter, newest last
+func (pg *PageLog) displayLog() {
+
+	pg.lock.Lock()
+	id, level, search := pg.id, pg.level, strings.ToLower(pg.search)
+	pg.lock.Unlock()
+
+	pg.seq = tlog.Sequence()
+	entries := tlog.Entries()
+
+	title := "Log (l)"
+	if len(id) > 0 {
+		title += " " + id
+	}
+	pg.logView.SetTitle(TitleColor(fmt.Sprintf("%s, %d entries, c-Clear", title, len(entries))))
+
+	var b strings.Builder
+	for _, e := range entries {
+		// The entries of the log ids turned off are kept but not shown
+		if e.Level < level || (len(id) > 0 && e.ID != id) || !tlog.IsActive(e.ID) {
+			continue
+		}
+		if len(search) > 0 && !strings.Contains(strings.ToLower(e.Text), search) &&
+			!strings.Contains(strings.ToLower(e.ID), search) {
+			continue
+		}
+
+		for _, line := range strings.Split(e.Text, "\n") {
+			fmt.Fprintf(&b, "%s %s %s %s\n",
+				cz.Wheat(e.Time.Format("15:04:05.000")),
+				levelColor(e.Level)(e.Level.String(), -5),
+				cz.Label(e.ID, -16),
+				tview.Escape(line))
+		}
+	}
+
+	pg.logView.SetText(b.String())
+	pg.logView.ScrollToEnd()
+}
@@ -0,0 +1,412 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2022 Intel Corporation
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/tview"
+
+	"github.com/KeithWiles/go-pktgen/pkgs/cfg"
+	cz "github.com/KeithWiles/go-pktgen/pkgs/colorize"
+	"github.com/KeithWiles/go-pktgen/pkgs/devbind"
+	"github.com/KeithWiles/go-pktgen/pkgs/hugepages"
+	tab "github.com/KeithWiles/go-pktgen/pkgs/taborder"
+	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
+)
+
+// PageMemory - Data for the hugepage and mempool page
+type PageMemory struct {
+	topFlex  *tview.Flex
+	pools    *tview.Table
+	mounts   *tview.Table
+	mempools *tview.Table
+	warnings *tview.TextView
+	to       *tab.Tab
+	huge     *hugepages.Info
+}
+
+// portMemory is the mempool memory a port needs on the NUMA node of its
+// PCI device
+type portMemory struct {
+	port   int
+	dev    *devbind.DeviceClass // PCI device of the port, nil if unknown
+	node   int                  // NUMA node of the device, hugepages.AllNodes if unknown
+	queues int                  // Number of RX and TX queues, each has a mempool
+	mbufs  uint64               // Number of mbufs of each mempool
+	bytes  uint64               // Memory of all mempools of the port
+}
+
+const (
+	memoryPanelName string = "Memory"
+
+	// defaultMbufs is the number of mbufs of a queue mempool
+	defaultMbufs = 8192
+)
+
+func init() {
+	tlog.Register("MemoryLogID")
+}
+
+// Printf - send message to the ttylog interface
+func (pm *PageMemory) Printf(format string, a ...interface{}) {
+	tlog.Log("MemoryLogID", fmt.Sprintf("%T.", pm)+format, a...)
+}
+
+// setupMemory - setup and init the memory page
+func setupMemory() *PageMemory {
+
+	pm := &PageMemory{
+		huge: hugepages.New(options.SysfsRoot, options.ProcRoot),
+	}
+
+	return pm
+}
+
+// MemoryPanelSetup setup the hugepage and mempool page
+func MemoryPanelSetup(pages *tview.Pages, nextSlide func()) (pageName string, content tview.Primitive) {
+
+	pm := setupMemory()
+
+	to := newTabOrder(memoryPanelName)
+	pm.to = to
+
+	flex0 := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex1 := tview.NewFlex().SetDirection(tview.FlexColumn)
+
+	TitleBox(flex0)
+
+	pm.pools = CreateTableView(flex1, "Hugepage Pools (h)", tview.AlignLeft, 0, 2, true).
+		SetFixed(1, 0).
+		SetSeparator(tview.Borders.Vertical)
+	pm.mounts = CreateTableView(flex1, "Hugetlbfs Mounts (f)", tview.AlignLeft, 0, 1, false).
+		SetFixed(1, 0).
+		SetSeparator(tview.Borders.Vertical)
+	flex0.AddItem(flex1, 0, 1, true)
+
+	pm.mempools = CreateTableView(flex0, "Port Mempools (p)", tview.AlignLeft, 0, 2, false).
+		SetFixed(1, 0).
+		SetSeparator(tview.Borders.Vertical)
+	pm.warnings = CreateTextView(flex0, "Readiness (r)", tview.AlignLeft, 0, 1, false)
+
+	to.Add("pools", pm.pools, 'h', "Select the Hugepage Pools window")
+	to.Add("mounts", pm.mounts, 'f', "Select the Hugetlbfs Mounts window")
+	to.Add("mempools", pm.mempools, 'p', "Select the Port Mempools window")
+	to.Add("readiness", pm.warnings, 'r', "Select the Readiness window")
+
+	to.SetInputDone()
+
+	pm.topFlex = flex0
+
+	flex0.SetInputCapture(panelInput(to))
+
+	pm.displayMemory()
+
+	pktgen.timers.Add(memoryPanelName, func(step int, ticks uint64) {
+		if step == 0 && pm.topFlex.HasFocus() {
+			pktgen.app.QueueUpdateDraw(func() {
+				pm.displayMemory()
+			})
+		}
+	})
+
+	return memoryPanelName, pm.topFlex
+}
+
+// nodeName returns the name of a NUMA node
+func nodeName(node int) string {
+
+	if node == hugepages.AllNodes {
+		return "all"
+	}
+	return strconv.Itoa(node)
+}
+
+// portDevices returns the PCI device of each port, the device is the PCI
+// device of the port in the configuration or the network device bound to a
+// DPDK driver in PCI order like DPDK probes the ports.
+func portDevices(db *devbind.BindInfo) map[int]*devbind.DeviceClass {
+
+	bound := []*devbind.DeviceClass{}
+	for _, dev := range db.FindDevicesByDeviceClass("ports", db.Groups[devbind.NetworkGroup]) {
+		for _, m := range devbind.UioModules {
+			if dev.Driver == m {
+				bound = append(bound, dev)
+			}
+		}
+	}
+	sort.Slice(bound, func(i, j int) bool { return bound[i].Slot < bound[j].Slot })
+
+	c := loadedConfig()
+
+	devs := make(map[int]*devbind.DeviceClass)
+	for port := 0; port < pktgen.portCnt; port++ {
+		p := &cfg.PortInfo{Port: port}
+		portStartup(c, p)
+
+		switch {
+		case len(p.PCI) > 0:
+			// The configuration may leave out the PCI domain
+			slot := p.PCI
+			if strings.Count(slot, ":") == 1 {
+				slot = "0000:" + slot
+			}
+			if dev, ok := db.Devices[strings.ToLower(slot)]; ok {
+				devs[port] = dev
+			}
+		case port < len(bound):
+			devs[port] = bound[port]
+		}
+	}
+	return devs
+}
+
+// portMemories returns the mempool memory of each port
+func portMemories(devs map[int]*devbind.DeviceClass) []*portMemory {
+
+	c := loadedConfig()
+
+	mbufs, mbufSize := uint64(defaultMbufs), uint64(hugepages.DefaultMbufSize)
+	if c != nil && c.Memory != nil {
+		m := c.Memory
+		if m.Mbufs > 0 {
+			mbufs = m.Mbufs
+		}
+		if m.MbufSize > 0 {
+			mbufSize = m.MbufSize
+		}
+	}
+
+	pms := []*portMemory{}
+	for port := 0; port < pktgen.portCnt; port++ {
+		p := &cfg.PortInfo{Port: port}
+		portStartup(c, p)
+
+		pm := &portMemory{port: port, dev: devs[port], node: hugepages.AllNodes, mbufs: mbufs}
+
+		if pm.dev != nil && len(pm.dev.NumaNode) > 0 {
+			if node, err := strconv.Atoi(pm.dev.NumaNode); err == nil {
+				pm.node = node
+			}
+		}
+
+		// A port has at least one queue in each direction
+		pm.queues = int(p.RxQueues) + int(p.TxQueues)
+		if p.RxQueues == 0 {
+			pm.queues++
+		}
+		if p.TxQueues == 0 {
+			pm.queues++
+		}
+		pm.bytes = uint64(pm.queues) * hugepages.MempoolBytes(mbufs, mbufSize)
+
+		pms = append(pms, pm)
+	}
+	return pms
+}
+
+// readinessWarnings returns the problems of the hugepage setup for the
+// mempools of the ports
+func readinessWarnings(pools []hugepages.Pool, mounts []hugepages.Mount, pms []*portMemory) []string {
+
+	warnings := []string{}
+
+	total := uint64(0)
+	for _, p := range pools {
+		if p.Node == hugepages.AllNodes {
+			total += p.Total
+		}
+	}
+	if total == 0 {
+		warnings = append(warnings,
+			"No hugepages reserved, set vm.nr_hugepages or hugepages= on the kernel command line")
+	}
+	if len(mounts) == 0 {
+		warnings = append(warnings,
+			"No hugetlbfs mounted, mount -t hugetlbfs nodev /dev/hugepages")
+	}
+
+	// The system wide pools hold the pages of all nodes
+	systemFree, totalNeed := uint64(0), uint64(0)
+	for _, p := range pools {
+		if p.Node == hugepages.AllNodes {
+			systemFree += p.FreeBytes()
+		}
+	}
+
+	// Sum the needs of the ports on each NUMA node
+	need := make(map[int]uint64)
+	ports := make(map[int][]string)
+	all := []string{}
+	unknown := []string{}
+	for _, pm := range pms {
+		totalNeed += pm.bytes
+		all = append(all, strconv.Itoa(pm.port))
+		if pm.dev == nil {
+			unknown = append(unknown, strconv.Itoa(pm.port))
+		}
+		if pm.node != hugepages.AllNodes {
+			need[pm.node] += pm.bytes
+			ports[pm.node] = append(ports[pm.node], strconv.Itoa(pm.port))
+		}
+	}
+
+	if totalNeed > systemFree {
+		warnings = append(warnings, fmt.Sprintf("System: ports %s need %s, only %s of hugepages free",
+			strings.Join(all, ","), FormatBytes(totalNeed, uint64(1)), FormatBytes(systemFree, uint64(1))))
+	}
+
+	// Without the pools of the nodes only the system wide pools are checked
+	free := hugepages.FreeBytes(pools)
+	if _, ok := free[hugepages.AllNodes]; ok {
+		need = nil
+	}
+
+	nodes := []int{}
+	for node := range need {
+		nodes = append(nodes, node)
+	}
+	sort.Ints(nodes)
+
+	for _, node := range nodes {
+		if need[node] <= free[node] {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("NUMA node %d: ports %s need %s, only %s of hugepages free on the node",
+			node, strings.Join(ports[node], ","), FormatBytes(need[node], uint64(1)),
+			FormatBytes(free[node], uint64(1))))
+	}
+
+	if len(unknown) > 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"Ports %s have no PCI device, set pci in the configuration or bind a device to a DPDK driver",
+			strings.Join(unknown, ",")))
+	}
+	return warnings
+}
+
+// displayMemory displays the hugepage pools, the mounts, the mempools of the
+// ports and the readiness warnings
+func (pm *PageMemory) displayMemory() {
+
+	pools, err := pm.huge.Pools()
+	if err != nil {
+		pm.Printf("hugepage pools: %v\n", err)
+	}
+	mounts, err := pm.huge.Mounts()
+	if err != nil {
+		pm.Printf("hugetlbfs mounts: %v\n", err)
+	}
+	pms := portMemories(portDevices(bindInfo()))
+
+	pm.displayPools(pools)
+	pm.displayMounts(mounts)
+	pm.displayMempools(pms)
+
+	str := ""
+	for _, w := range readinessWarnings(pools, mounts, pms) {
+		str += cz.Warning(tview.Escape(w)) + "\n"
+	}
+	if len(str) == 0 {
+		str = cz.Value("Hugepages ready for the port mempools")
+	}
+	pm.warnings.SetText(strings.TrimRight(str, "\n"))
+}
+
+// displayPools displays the hugepage pools of each page size and NUMA node
+func (pm *PageMemory) displayPools(pools []hugepages.Pool) {
+
+	view := pm.pools
+	view.Clear()
+
+	titles := []string{
+		cz.Header("Node"),
+		cz.Header("Page Size"),
+		cz.Header("Total"),
+		cz.Header("Free"),
+		cz.Header("Reserved"),
+		cz.Header("Surplus"),
+		cz.Header("Free Memory"),
+	}
+	row := TableSetHeaders(view, 0, 0, titles)
+
+	for _, p := range pools {
+		SetCell(view, row, 0, cz.Label(nodeName(p.Node)))
+		SetCell(view, row, 1, cz.Value(FormatBytes(p.Size)))
+		SetCell(view, row, 2, cz.Value(p.Total))
+		SetCell(view, row, 3, cz.Value(p.Free))
+
+		// The nodes have no reserved pages
+		resv := "-"
+		if p.Node == hugepages.AllNodes {
+			resv = strconv.FormatUint(p.Reserved, 10)
+		}
+		SetCell(view, row, 4, cz.Value(resv))
+		SetCell(view, row, 5, cz.Value(p.Surplus))
+		SetCell(view, row, 6, cz.Value(FormatBytes(p.FreeBytes(), uint64(1))))
+		row++
+	}
+}
+
+// displayMounts displays the mounted hugetlbfs file systems
+func (pm *PageMemory) displayMounts(mounts []hugepages.Mount) {
+
+	view := pm.mounts
+	view.Clear()
+
+	titles := []string{
+		cz.Header("Mount Point"),
+		cz.Header("Page Size"),
+	}
+	row := TableSetHeaders(view, 0, 0, titles)
+
+	for _, m := range mounts {
+		size := "default"
+		if m.PageSize > 0 {
+			size = FormatBytes(m.PageSize)
+		}
+		SetCell(view, row, 0, cz.Label(m.Path), tview.AlignLeft)
+		SetCell(view, row, 1, cz.Value(size))
+		row++
+	}
+}
+
+// displayMempools displays the mempool memory of each port
+func (pm *PageMemory) displayMempools(pms []*portMemory) {
+
+	view := pm.mempools
+	view.Clear()
+
+	titles := []string{
+		cz.Header("Port"),
+		cz.Header("PCI Device"),
+		cz.Header("Driver"),
+		cz.Header("NUMA"),
+		cz.Header("Queues"),
+		cz.Header("Mbufs/Queue"),
+		cz.Header("Memory"),
+	}
+	row := TableSetHeaders(view, 0, 0, titles)
+
+	for _, p := range pms {
+		slot, driver, node := "-", "-", "-"
+		if p.dev != nil {
+			slot, driver = p.dev.Slot, p.dev.Driver
+			if p.node != hugepages.AllNodes {
+				node = nodeName(p.node)
+			}
+		}
+		SetCell(view, row, 0, cz.Label(p.port))
+		SetCell(view, row, 1, cz.Address(slot), tview.AlignLeft)
+		SetCell(view, row, 2, cz.Value(driver), tview.AlignLeft)
+		SetCell(view, row, 3, cz.Value(node))
+		SetCell(view, row, 4, cz.Value(p.queues))
+		SetCell(view, row, 5, cz.Value(p.mbufs))
+		SetCell(view, row, 6, cz.Value(FormatBytes(p.bytes, uint64(1))))
+		row++
+	}
+}
@@ -5,7 +5,6 @@ package main
 
 import (
 	"fmt"
-	"math/rand"
 	"net"
 	"strconv"
 	"sync"
@@ -17,6 +16,7 @@ import (
 	"github.com/rivo/tview"
 
 	cz "github.com/KeithWiles/go-pktgen/pkgs/colorize"
+	"github.com/KeithWiles/go-pktgen/pkgs/keybind"
 	"github.com/KeithWiles/go-pktgen/pkgs/meter"
 	tab "github.com/KeithWiles/go-pktgen/pkgs/taborder"
 	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
@@ -42,7 +42,6 @@ type PageSingleMode struct {
 
 const (
 	singlePanelName  string = "Single"
-	singleInfoHelp   string = "singleInfoHelp"
 	singlePortConfig string = "singlePortConfig"
 )
 
@@ -96,7 +95,7 @@ func (ps *PageSingleMode) setupConfigForm(pages *tview.Pages, port int) *tview.F
 
 	form.SetTitleAlign(tview.AlignLeft).SetRect(0, 0, 35, 21)
 
-	sc := *pktgen.single[port]
+	sc, _ := SingleConfig(port)
 
 	form.AddInputField("Port ID  :", strconv.Itoa(int(sc.PortIndex)), 2,
 		func(textToCheck string, lastChar rune) bool {
@@ -234,7 +233,7 @@ func (ps *PageSingleMode) setupConfigForm(pages *tview.Pages, port int) *tview.F
 		})
 
 	form.AddButton("Save", func() {
-		pktgen.single[port] = &sc
+		SetSingleConfig(port, &sc)
 		pages.HidePage(pg)
 		ps.to.SetInputFocus('c')
 	}).SetButtonTextColor(tcell.ColorBlack)
@@ -261,14 +260,14 @@ func SingleModePanelSetup(pages *tview.Pages, nextSlide func()) (pageName string
 
 	ps := setupSingleMode()
 
-	ps.to = tab.New(singlePanelName, pktgen.app)
+	ps.to = newTabOrder(singlePanelName)
 
 	flex0 := tview.NewFlex().SetDirection(tview.FlexRow)
 	flex1 := tview.NewFlex().SetDirection(tview.FlexRow)
 
 	TitleBox(flex0)
 
-	ps.singleConfig = CreateTableView(flex1, "Configuration (c) Start/Stop-r/s, Start/Stop All-R/S, Edit-e",
+	ps.singleConfig = CreateTableView(flex1, "Configuration (c) Start/Stop-r/s, Start/Stop All-R/S, Edit-e, Save Config-Ctrl-S",
 		tview.AlignLeft, 11, 0, true).
 		SetSelectable(true, false).
 		SetFixed(1, 1).
@@ -278,9 +277,15 @@ func SingleModePanelSetup(pages *tview.Pages, nextSlide func()) (pageName string
 			if col > 0 {
 				ps.singleConfig.Select(row, 0)
 			}
+			if row > 0 {
+				pktgen.prefs.Port = row - 1
+			}
 		}).
 		SetSeparator(tview.Borders.Vertical)
 
+	// Select the port of the preferences, the first row is the header
+	ps.singleConfig.Select(pktgen.prefs.Port+1, 0)
+
 	ps.singleStats = CreateTableView(flex1, "Stats (1)", tview.AlignLeft, 0, 1, true).
 		SetSelectable(false, false).
 		SetFixed(1, 1).
@@ -298,10 +303,10 @@ func SingleModePanelSetup(pages *tview.Pages, nextSlide func()) (pageName string
 
 	flex0.AddItem(flex1, 0, 1, true)
 
-	ps.to.Add("singleConfig", ps.singleConfig, 'c')
-	ps.to.Add("singleStats", ps.singleStats, '1')
-	ps.to.Add("singleSizes", ps.singleSizes, '2')
-	ps.to.Add("singlePerf", ps.singlePerf, 'p')
+	ps.to.Add("singleConfig", ps.singleConfig, 'c', "Select the Configuration window")
+	ps.to.Add("singleStats", ps.singleStats, '1', "Select the Stats window")
+	ps.to.Add("singleSizes", ps.singleSizes, '2', "Select the Size Stats window")
+	ps.to.Add("singlePerf", ps.singlePerf, 'p', "Select the Performance window")
 	ps.to.SetInputDone()
 
 	ps.topFlex = flex0
@@ -314,55 +319,35 @@ func SingleModePanelSetup(pages *tview.Pages, nextSlide func()) (pageName string
 		}
 	})
 
-	modal := tview.NewModal().
-		SetText("This is the Help Box: singleInfoHelp Thank you for asking for help! Press Esc to close.").
-		AddButtons([]string{"Got it"}).
-		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-			pages.HidePage(singleInfoHelp)
-		})
-	AddModalPage(singleInfoHelp, modal)
-
 	for port := 0; port < pktgen.portCnt; port++ {
 		f := ps.setupConfigForm(pages, port)
 		ps.configForms = append(ps.configForms, f)
 	}
 
-	ps.singleConfig.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+	// The port keys act on the port selected in the configuration window
+	selectPort := func() int {
 		ps.currentPort, _ = ps.singleConfig.GetSelection()
 		ps.currentPort--
 
-		sc := pktgen.single[ps.currentPort]
-
-		k := event.Rune()
-		switch k {
-		case 'e':
-			pages.ShowPage(fmt.Sprintf("%v-%v", singlePortConfig, ps.currentPort))
-		case 'r':
-			sc.TxState = true
-		case 'R':
-			for i := 0; i < pktgen.portCnt; i++ {
-				pktgen.single[i].TxState = true
-			}
-		case 's':
-			sc.TxState = false
-		case 'S':
-			for i := 0; i < pktgen.portCnt; i++ {
-				pktgen.single[i].TxState = false
-			}
-		default:
-			ps.to.SetInputFocus(k)
-		}
-		return event
+		return ps.currentPort
+	}
+	scope := keybind.Scope(singlePanelName, "singleConfig")
+	keybind.Add(scope, 'e', "Edit the selected port", func() {
+		pages.ShowPage(fmt.Sprintf("%v-%v", singlePortConfig, selectPort()))
 	})
-	flex0.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		k := event.Rune()
-		switch k {
-		case '?':
-			pages.ShowPage(singleInfoHelp)
-		default:
-		}
-		return event
+	keybind.Add(scope, 'r', "Start the selected port", func() {
+		SetTxState(true, selectPort())
 	})
+	keybind.Add(scope, 'R', "Start all ports", func() {
+		SetTxState(true)
+	})
+	keybind.Add(scope, 's', "Stop the selected port", func() {
+		SetTxState(false, selectPort())
+	})
+	keybind.Add(scope, 'S', "Stop all ports", func() {
+		SetTxState(false)
+	})
+	flex0.SetInputCapture(panelInput(ps.to))
 
 	ps.meter = meter.New().
 		SetWidth(func() int {
@@ -405,11 +390,20 @@ func (ps *PageSingleMode) displaySingleMode(step int, ticks uint64) {
 
 func (ps *PageSingleMode) pullStats() {
 
+	snap := pktgen.stats.Snapshot()
+
 	for port := 0; port < pktgen.portCnt; port++ {
-		ps.rxPercentRate[port] = float64(rand.Intn(101))
-		ps.txPercentRate[port] = float64(rand.Intn(101))
-	}
+		ps.rxPercentRate[port] = 0.0
+		ps.txPercentRate[port] = 0.0
 
+		sc, err := SingleConfig(port)
+		if err != nil {
+			continue
+		}
+		lineRate := linePPS(sc.PktSize)
+		ps.rxPercentRate[port] = (float64(snap.Ports[port].RxPPS) / lineRate) * 100.0
+		ps.txPercentRate[port] = (float64(snap.Ports[port].TxPPS) / lineRate) * 100.0
+	}
 }
 
 func (ps *PageSingleMode) configTable() {
@@ -419,22 +413,22 @@ func (ps *PageSingleMode) configTable() {
 	col := 0
 
 	titles := []string{
-		cz.Yellow("Port", 5),
-		cz.Yellow("TX Count", 8),
-		cz.Yellow("% Rate", 7),
-		cz.Yellow("Size", 4),
-		cz.Yellow("Burst", 5),
-		cz.Yellow("TTL", 4),
-		cz.Yellow("sport", 5),
-		cz.Yellow("dport", 5),
-		cz.Yellow("PType", 5),
-		cz.Yellow("Proto", 5),
-		cz.Yellow("VLAN", 4),
-		cz.Yellow("IP Dst"),
-		cz.Yellow("IP Src"),
-		cz.Yellow("MAC Dst", 14),
-		cz.Yellow("MAC Src", 14),
-		cz.Yellow(" ", 16), // Extra field to allow scrolling horizontal
+		cz.Header("Port", 5),
+		cz.Header("TX Count", 8),
+		cz.Header("% Rate", 7),
+		cz.Header("Size", 4),
+		cz.Header("Burst", 5),
+		cz.Header("TTL", 4),
+		cz.Header("sport", 5),
+		cz.Header("dport", 5),
+		cz.Header("PType", 5),
+		cz.Header("Proto", 5),
+		cz.Header("VLAN", 4),
+		cz.Header("IP Dst"),
+		cz.Header("IP Src"),
+		cz.Header("MAC Dst", 14),
+		cz.Header("MAC Src", 14),
+		cz.Header(" ", 16), // Extra field to allow scrolling horizontal
 	}
 	row = TableSetHeaders(ps.singleConfig, 0, 0, titles)
 
@@ -456,24 +450,24 @@ func (ps *PageSingleMode) configTable() {
 	}
 
 	for v := 0; v < pktgen.portCnt; v++ {
-		single := pktgen.single[v]
+		single, _ := SingleConfig(v)
 
 		rowData := []string{
 			state(single.PortIndex, single.TxState),
-			cz.CornSilk(txCount(single.TxCount)),
-			cz.DeepPink(strconv.FormatFloat(single.PercentRate, 'f', 2, 64)),
-			cz.LightCoral(single.PktSize),
-			cz.LightCoral(single.BurstCount),
-			cz.LightCoral(single.TimeToLive),
-			cz.LightCoral(single.SrcPort),
-			cz.LightCoral(single.DstPort),
-			cz.LightBlue(single.PType),
-			cz.LightBlue(single.ProtoType),
-			cz.Cyan(single.VlanId),
-			cz.CornSilk(single.DstIP.IP.String()),
-			cz.CornSilk(single.SrcIP.String()),
-			cz.Green(single.DstMAC.String()),
-			cz.Green(single.SrcMAC.String()),
+			cz.Value(txCount(single.TxCount)),
+			cz.Rate(strconv.FormatFloat(single.PercentRate, 'f', 2, 64)),
+			cz.Value(single.PktSize),
+			cz.Value(single.BurstCount),
+			cz.Value(single.TimeToLive),
+			cz.Value(single.SrcPort),
+			cz.Value(single.DstPort),
+			cz.Label(single.PType),
+			cz.Label(single.ProtoType),
+			cz.Value(single.VlanId),
+			cz.Address(single.DstIP.IP.String()),
+			cz.Address(single.SrcIP.String()),
+			cz.Address(single.DstMAC.String()),
+			cz.Address(single.SrcMAC.String()),
 		}
 		for i, d := range rowData {
 			if i == 0 {
@@ -497,19 +491,19 @@ func (ps *PageSingleMode) displayStats() {
 	col := 0
 
 	titles := []string{
-		cz.Yellow("Port", 4),
-		cz.Yellow("Link State", 12),
-		cz.Yellow("Rx pps", 8),
-		cz.Yellow("Tx pps", 8),
-		cz.Yellow("Rx/Tx Mbits", 12),
-		cz.Yellow("Rx Max", 8),
-		cz.Yellow("Tx Max", 8),
-		cz.Yellow("Rx/Tx Errors", 12),
-		cz.Yellow("Tot Rx Pkts", 12),
-		cz.Yellow("Tot Tx Pkts", 12),
-		cz.Yellow("Tot Rx Mbits", 12),
-		cz.Yellow("Tot Tx Mbits", 12),
-		cz.Yellow(" ", 6), // Extra field to allow scrolling horizontal
+		cz.Header("Port", 4),
+		cz.Header("Link State", 12),
+		cz.Header("Rx pps", 8),
+		cz.Header("Tx pps", 8),
+		cz.Header("Rx/Tx Mbits", 12),
+		cz.Header("Rx Max", 8),
+		cz.Header("Tx Max", 8),
+		cz.Header("Rx/Tx Errors", 12),
+		cz.Header("Tot Rx Pkts", 12),
+		cz.Header("Tot Tx Pkts", 12),
+		cz.Header("Tot Rx Mbits", 12),
+		cz.Header("Tot Tx Mbits", 12),
+		cz.Header(" ", 6), // Extra field to allow scrolling horizontal
 	}
 	row = TableSetHeaders(table, row, 0, titles)
 
@@ -519,21 +513,24 @@ func (ps *PageSingleMode) displayStats() {
 		return p.Sprintf("%d", n)
 	}
 
+	snap := pktgen.stats.Snapshot()
+
 	for v := 0; v < pktgen.portCnt; v++ {
+		st := &snap.Ports[v]
 
 		rowData := []string{
-			cz.Yellow(v),
-			cz.LightYellow("UP-40000-FD"),
-			cz.Cyan(comma(0)),
-			cz.Cyan(comma(0)),
-			cz.Wheat(comma(0) + "/" + comma(0)),
-			cz.Cyan(comma(0)),
-			cz.Cyan(comma(0)),
-			cz.Red(comma(0) + "/" + comma(0)),
-			cz.Cyan(comma(0)),
-			cz.Cyan(comma(0)),
-			cz.Cyan(comma(0)),
-			cz.Cyan(comma(0)),
+			cz.Label(v),
+			cz.Label(st.LinkState),
+			cz.Rate(comma(st.RxPPS)),
+			cz.Rate(comma(st.TxPPS)),
+			cz.Rate(comma(uint64(st.RxMbits)) + "/" + comma(uint64(st.TxMbits))),
+			cz.Rate(comma(st.RxMaxPPS)),
+			cz.Rate(comma(st.TxMaxPPS)),
+			cz.Error(comma(st.RxErrors) + "/" + comma(st.TxErrors)),
+			cz.Value(comma(st.RxPkts)),
+			cz.Value(comma(st.TxPkts)),
+			cz.Value(comma(uint64(BitRate(st.RxPkts, st.RxBytes) / float64(Million)))),
+			cz.Value(comma(uint64(BitRate(st.TxPkts, st.TxBytes) / float64(Million)))),
 		}
 		for i, d := range rowData {
 			if i == 0 {
@@ -557,33 +554,38 @@ func (ps *PageSingleMode) displaySizes() {
 	col := 0
 
 	titles := []string{
-		cz.Yellow("Port", 4),
-		cz.Yellow("Broadcast", 12),
-		cz.Yellow("Multicast", 12),
-		cz.Yellow("Sizes 64", 12),
-		cz.Yellow("128-255", 12),
-		cz.Yellow("256-511", 12),
-		cz.Yellow("512-1023", 12),
-		cz.Yellow("1024-1518", 12),
-		cz.Yellow("Runts/Jumbos", 14),
-		cz.Yellow("ARPs/ICMPs", 14),
-		cz.Yellow(" ", 6), // Extra field to allow scrolling horizontal
+		cz.Header("Port", 4),
+		cz.Header("Broadcast", 12),
+		cz.Header("Multicast", 12),
+		cz.Header("Sizes 64", 12),
+		cz.Header("65-127", 12),
+		cz.Header("128-255", 12),
+		cz.Header("256-511", 12),
+		cz.Header("512-1023", 12),
+		cz.Header("1024-1518", 12),
+		cz.Header("Runts/Jumbos", 14),
+		cz.Header("ARPs/ICMPs", 14),
+		cz.Header(" ", 6), // Extra field to allow scrolling horizontal
 	}
 	row = TableSetHeaders(table, row, 0, titles)
 
+	snap := pktgen.stats.Snapshot()
+
 	for v := 0; v < pktgen.portCnt; v++ {
+		sz := &snap.Ports[v].Sizes
 
 		rowData := []string{
-			cz.Yellow(v),
-			cz.Wheat(0),
-			cz.GoldenRod(0),
-			cz.Cyan(0),
-			cz.Cyan(0),
-			cz.Cyan(0),
-			cz.Cyan(0),
-			cz.Cyan(0),
-			cz.DeepPink("0/0"),
-			cz.Wheat("0/0"),
+			cz.Label(v),
+			cz.Value(sz.Broadcast),
+			cz.Value(sz.Multicast),
+			cz.Value(sz.Size64),
+			cz.Value(sz.Size65To127),
+			cz.Value(sz.Size128To255),
+			cz.Value(sz.Size256To511),
+			cz.Value(sz.Size512To1023),
+			cz.Value(sz.Size1024To1518),
+			cz.Value(fmt.Sprintf("%d/%d", sz.Runts, sz.Jumbos)),
+			cz.Value(fmt.Sprintf("%d/%d", sz.ARPs, sz.ICMPs)),
 		}
 		for i, d := range rowData {
 			if i == 0 {
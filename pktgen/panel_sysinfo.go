@@ -7,19 +7,23 @@ import (
 	"fmt"
 	"strings"
 	"sort"
+	"sync"
+	"time"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 
 	"github.com/rivo/tview"
-	"github.com/gdamore/tcell/v2"
 	"github.com/shirou/gopsutil/host"
 	"github.com/shirou/gopsutil/mem"
 	"github.com/shirou/gopsutil/net"
 
 	cz "github.com/KeithWiles/go-pktgen/pkgs/colorize"
 	"github.com/KeithWiles/go-pktgen/pkgs/devbind"
+	"github.com/KeithWiles/go-pktgen/pkgs/ethtool"
+	"github.com/KeithWiles/go-pktgen/pkgs/graphdata"
+	"github.com/KeithWiles/go-pktgen/pkgs/keybind"
 	tab "github.com/KeithWiles/go-pktgen/pkgs/taborder"
 	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
 )
@@ -49,17 +53,41 @@ type tableInfo struct {
 
 // PageSysInfo - Data for main page information
 type PageSysInfo struct {
-	topFlex *tview.Flex
-	host    *tview.TextView
-	mem     *tview.TextView
-	hostNet *tview.Table
-	devbind *devbind.BindInfo
-	tables  []tableData
-	tInfos  map[string]*tableInfo
+	topFlex   *tview.Flex
+	to        *tab.Tab
+	host      *tview.TextView
+	mem       *tview.TextView
+	preflight *tview.TextView
+	hostNet   *tview.Table
+	netChart  *tview.TextView
+	details   *tview.TextView
+	detailed  string // Slot of the device shown in the details
+	devbind   *devbind.BindInfo
+	tables    []tableData
+	tInfos    map[string]*tableInfo
+	lock      sync.Mutex
+	netPrev   map[string]net.IOCountersStat // Counters of the last sample
+	netTime   time.Time                     // Time of the last sample
+	netRates  map[string]netRates           // Rates since the sample before
+	netHist   map[string]portHistory        // Rate history of each interface
+	netRows   []string                      // Interface of each table row
+	netIface  string                        // Interface shown in the chart
+	netMetric int                           // Index into graphMetrics
+	netWindow int                           // Index into graphWindows
+	netGraphs *graphdata.GraphInfo
+}
+
+// netRates is the per second rates of a host network interface
+type netRates struct {
+	rxPPS, txPPS     float64
+	rxMbits, txMbits float64
+	errIn, errOut    float64
+	dropIn, dropOut  float64
 }
 
 const (
 	sysinfoPanelName string = "System"
+	driverFormName   string = "sysDriverForm"
 )
 
 func init() {
@@ -71,13 +99,39 @@ func (ps *PageSysInfo) Printf(format string, a ...interface{}) {
 	tlog.Log("SysInfoLogID", fmt.Sprintf("%T.", ps)+format, a...)
 }
 
+// bindInfo returns the devices of the system, the devices are found once
+// and shared by the panels.
+func bindInfo() *devbind.BindInfo {
+
+	if pktgen.devbind != nil {
+		return pktgen.devbind
+	}
+
+	db, err := devbind.New(options.Devices...)
+	if err != nil {
+		tlog.ErrorPrintf("Device files: %v\n", err)
+		showMessage(fmt.Sprintf("Device files not loaded, using the default devices: %v", err))
+
+		if db, err = devbind.New(); err != nil {
+			tlog.ErrorPrintf("Default devices: %v\n", err)
+			db = &devbind.BindInfo{}
+		}
+	}
+	pktgen.devbind = db
+
+	return db
+}
+
 // setupSysInfo - setup and init the sysInfo page
 func setupSysInfo() *PageSysInfo {
 
-	ps := &PageSysInfo{}
-
-	ps.devbind = devbind.New()
+	ps := &PageSysInfo{
+		netPrev:  make(map[string]net.IOCountersStat),
+		netRates: make(map[string]netRates),
+		netHist:  make(map[string]portHistory),
+	}
 
+	ps.devbind = bindInfo()
 	db := ps.devbind
 
 	ps.tInfos = make(map[string]*tableInfo)
@@ -89,7 +143,7 @@ func setupSysInfo() *PageSysInfo {
 			classes:    db.Groups[devbind.NetworkGroup],
 			align:      tview.AlignLeft,
 			fixedSize:  0,
-			proportion: 1,
+			proportion: 2,
 			focus:      true,
 			key:        'N',
 		}, {
@@ -132,7 +186,8 @@ func SysInfoPanelSetup(pages *tview.Pages, nextSlide func()) (pageName string, c
 
 	ps := setupSysInfo()
 
-	to := tab.New(sysinfoPanelName, pktgen.app)
+	to := newTabOrder(sysinfoPanelName)
+	ps.to = to
 
 	flex0 := tview.NewFlex().SetDirection(tview.FlexRow)
 	flex1 := tview.NewFlex().SetDirection(tview.FlexRow)
@@ -142,29 +197,86 @@ func SysInfoPanelSetup(pages *tview.Pages, nextSlide func()) (pageName string, c
 
 	ps.host = CreateTextView(flex2, "Host (h)", tview.AlignLeft, 0, 1, true)
 	ps.mem = CreateTextView(flex2, "Memory (m)", tview.AlignLeft, 0, 1, false)
+	ps.preflight = CreateTextView(flex2, "Preflight (t) Update-u", tview.AlignLeft, 0, 2, false)
 	flex1.AddItem(flex2, 0, 1, true)
 
-	ps.hostNet = CreateTableView(flex1, "Host Network Stats (n)", tview.AlignLeft, 0, 1, false).
-		SetSelectable(false, false).
+	flex3 := tview.NewFlex().SetDirection(tview.FlexColumn)
+	ps.hostNet = CreateTableView(flex3, "Host Network Rates (n)", tview.AlignLeft, 0, 3, false).
+		SetSelectable(true, false).
 		SetFixed(1, 1).
 		SetSeparator(tview.Borders.Vertical)
+	ps.netChart = CreateTextView(flex3, "Interface (i)", tview.AlignLeft, 0, 2, false)
+	flex1.AddItem(flex3, 0, 2, false)
+
+	// The chart follows the interface selected in the table
+	ps.hostNet.SetSelectionChangedFunc(func(row, col int) {
+		if row > 0 && row <= len(ps.netRows) {
+			ps.netIface = ps.netRows[row-1]
+			ps.displayNetChart()
+		}
+	})
+
+	to.Add("host", ps.host, 'h', "Select the Host window")
+	to.Add("memory", ps.mem, 'm', "Select the Memory window")
+	to.Add("preflight", ps.preflight, 't', "Select the Preflight window")
+	to.Add("hostName", ps.hostNet, 'n', "Select the Host Network Rates window")
+	to.Add("hostChart", ps.netChart, 'i', "Select the Interface chart window")
 
-	to.Add("host", ps.host, 'h')
-	to.Add("memory", ps.mem, 'm')
-	to.Add("hostName", ps.hostNet, 'n')
+	keybind.Add(sysinfoPanelName, 'u', "Run the preflight checks again", ps.updatePreflight)
+	keybind.Add(sysinfoPanelName, 'r', "Change the interface chart metric, packets or Mbits per second", func() {
+		ps.netMetric = (ps.netMetric + 1) % len(graphMetrics)
+		ps.displayNetChart()
+	})
+	keybind.Add(sysinfoPanelName, 'w', "Increase the interface chart time window", func() {
+		ps.netWindow = (ps.netWindow + 1) % len(graphWindows)
+		ps.displayNetChart()
+	})
+	keybind.Add(sysinfoPanelName, 'W', "Decrease the interface chart time window", func() {
+		ps.netWindow = (ps.netWindow - 1 + len(graphWindows)) % len(graphWindows)
+		ps.displayNetChart()
+	})
 
 	ti := ps.tInfos
 
 	// Create each table view for each of the device table entries
 	for _, td := range ps.tables {
-		s := fmt.Sprintf("%s Devices (%c)", td.name, td.key)
+		s := fmt.Sprintf("%s Devices (%c) Driver-b", td.name, td.key)
+
+		// The network devices have the ethtool details of the selected device
+		// next to the table
+		flex := flex1
+		proportion := td.proportion
+		if td.name == "Network" {
+			flex = tview.NewFlex().SetDirection(tview.FlexColumn)
+			flex1.AddItem(flex, td.fixedSize, td.proportion, td.focus)
+			proportion = 2
+		}
 
-		ti[td.name].view = CreateTableView(flex1, s, td.align, td.fixedSize, td.proportion, td.focus).
+		ti[td.name].view = CreateTableView(flex, s, td.align, td.fixedSize, proportion, td.focus).
+			SetSelectable(true, false).
 			SetFixed(1, 0).
 			SetSeparator(tview.Borders.Vertical)
 
 		// Add the single key and define the tab order.
-		to.Add(fmt.Sprintf("Table-%v", td.key), ti[td.name].view, td.key)
+		name := strings.ToLower(td.name) + "Devices"
+		to.Add(name, ti[td.name].view, td.key,
+			fmt.Sprintf("Select the %s Devices window", td.name))
+
+		if td.name == "Network" {
+			ps.details = CreateTextView(flex, "Device Details (e)", tview.AlignLeft, 0, 1, false)
+			ps.details.SetText(cz.Warning("No network device selected"))
+			to.Add("deviceDetails", ps.details, 'e', "Select the Device Details window")
+
+			ti[td.name].view.SetSelectionChangedFunc(func(row, col int) {
+				ps.displayDetails()
+			})
+		}
+
+		tInfo := ti[td.name]
+		keybind.Add(keybind.Scope(sysinfoPanelName, name), 'b',
+			"Bind, unbind or override the device driver", func() {
+				ps.showDriverForm(pages, tInfo)
+			})
 	}
 	flex0.AddItem(flex1, 0, 3, true)
 
@@ -172,31 +284,20 @@ func SysInfoPanelSetup(pages *tview.Pages, nextSlide func()) (pageName string, c
 
 	ps.topFlex = flex0
 
-	modal := tview.NewModal().
-		SetText("This is the Help Box: sysInfoHelp  Thank you for asking for help! Press Esc to close.").
-		AddButtons([]string{"Got it"}).
-		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-			pages.HidePage("sysInfoHelp")
-		})
-	AddModalPage("sysInfoHelp", modal)
-
-	flex0.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		k := event.Rune()
-		switch k {
-        case '?':
-			pages.ShowPage("sysInfoHelp")
-        case '-':
-			pages.ShowPage("sysInfoWindow")
-		}
-		return event
-	})
+	flex0.SetInputCapture(panelInput(to))
 
 	// Setup static pages
 	ps.displayHost(ps.host)
+	ps.updatePreflight()
+	ps.sampleNet()
 	ps.displayHostNet(ps.hostNet)
 	ps.hostNet.ScrollToBeginning()
 
+	// The interface history is kept while the panel is hidden
 	pktgen.timers.Add(sysinfoPanelName, func(step int, ticks uint64) {
+		if step == 2 {
+			ps.sampleNet()
+		}
 		if ps.topFlex.HasFocus() {
 			pktgen.app.QueueUpdateDraw(func() {
 				ps.displaySysInfo(step, ticks)
@@ -218,9 +319,11 @@ func (ps *PageSysInfo) displaySysInfo(step int, ticks uint64) {
 		}
 
 	case 1:
+		ps.updateDetails()
 
 	case 2:
 		ps.displayHostNet(ps.hostNet)
+		ps.displayNetChart()
 		ps.displayPageSysInfo(step)
 	}
 }
@@ -230,22 +333,22 @@ func (ps *PageSysInfo) displayHost(view *tview.TextView) {
 
 	str := ""
 	info, _ := host.Info()
-	str += fmt.Sprintf("Hostname: %s\n", cz.Yellow(info.Hostname))
-	str += fmt.Sprintf("Host ID : %s\n", cz.Green(info.HostID))
+	str += fmt.Sprintf("Hostname: %s\n", cz.Value(info.Hostname))
+	str += fmt.Sprintf("Host ID : %s\n", cz.Value(info.HostID))
 
 	c := cases.Title(language.AmericanEnglish)
 	str += fmt.Sprintf("OS      : %s-%s\n",
-		cz.GoldenRod(c.String(info.OS)), cz.Orange(c.String(info.KernelVersion)))
+		cz.Value(c.String(info.OS)), cz.Value(c.String(info.KernelVersion)))
 	str += fmt.Sprintf("Platform: %s %s\nFamily  : %s\n",
-		cz.MediumSpringGreen(c.String(info.Platform)),
-		cz.LightSkyBlue(c.String(info.PlatformVersion)),
-		cz.Green(c.String(info.PlatformFamily)))
+		cz.Value(c.String(info.Platform)),
+		cz.Value(c.String(info.PlatformVersion)),
+		cz.Value(c.String(info.PlatformFamily)))
 
 	days := info.Uptime / (60 * 60 * 24)
 	hours := (info.Uptime - (days * 60 * 60 * 24)) / (60 * 60)
 	minutes := ((info.Uptime - (days * 60 * 60 * 24)) - (hours * 60 * 60)) / 60
 	s := fmt.Sprintf("%d days, %d hours, %d minutes", days, hours, minutes)
-	str += fmt.Sprintf("Uptime  : %s\n", cz.DeepPink(s))
+	str += fmt.Sprintf("Uptime  : %s\n", cz.Value(s))
 
 	role := info.VirtualizationRole
 	if len(role) == 0 {
@@ -255,7 +358,7 @@ func (ps *PageSysInfo) displayHost(view *tview.TextView) {
 	if len(vsys) == 0 {
 		vsys = "unknown"
 	}
-	str += fmt.Sprintf("Virtual Role: %s, System: %s", cz.Yellow(role), cz.Yellow(vsys))
+	str += fmt.Sprintf("Virtual Role: %s, System: %s", cz.Value(role), cz.Value(vsys))
 
 	view.SetText(str)
 }
@@ -268,19 +371,90 @@ func (ps *PageSysInfo) displayMem(view *tview.TextView) {
 	v, _ := mem.VirtualMemory()
 
 	p := message.NewPrinter(language.English)
-	str += fmt.Sprintf("Memory  Total: %s MiB\n", cz.Green(p.Sprintf("%d", v.Total/MegaBytes), 6))
-	str += fmt.Sprintf("         Free: %s MiB\n", cz.Green(p.Sprintf("%d", v.Free/MegaBytes), 6))
-	str += fmt.Sprintf("         Used: %s Percent\n\n", cz.Orange(v.UsedPercent, 6, 1))
+	str += fmt.Sprintf("Memory  Total: %s MiB\n", cz.Value(p.Sprintf("%d", v.Total/MegaBytes), 6))
+	str += fmt.Sprintf("         Free: %s MiB\n", cz.Value(p.Sprintf("%d", v.Free/MegaBytes), 6))
+	str += fmt.Sprintf("         Used: %s Percent\n\n", cz.Value(v.UsedPercent, 6, 1))
 
-	str += fmt.Sprintf("%s:\n", cz.MediumSpringGreen("Total Hugepage Info"))
-	str += fmt.Sprintf("   Free/Total: %s/%s pages\n", cz.LightBlue(p.Sprintf("%d", v.HugePagesFree), 6),
-		cz.LightBlue(p.Sprintf("%d", v.HugePagesTotal), 6))
-	str += fmt.Sprintf("Hugepage Size: %s Kb", cz.LightBlue(p.Sprintf("%d", v.HugePageSize/KiloBytes), 6))
+	str += fmt.Sprintf("%s:\n", cz.Header("Total Hugepage Info"))
+	str += fmt.Sprintf("   Free/Total: %s/%s pages\n", cz.Value(p.Sprintf("%d", v.HugePagesFree), 6),
+		cz.Value(p.Sprintf("%d", v.HugePagesTotal), 6))
+	str += fmt.Sprintf("Hugepage Size: %s Kb", cz.Value(p.Sprintf("%d", v.HugePageSize/KiloBytes), 6))
 
 	view.SetText(str)
 }
 
-// Display the Host network information
+// perSecond returns the rate of a counter between two samples, a counter
+// that went backwards was reset and has no rate.
+func perSecond(cur, prev uint64, secs float64) float64 {
+
+	if cur < prev || secs <= 0 {
+		return 0
+	}
+	return float64(cur-prev) / secs
+}
+
+// sampleNet computes the rates of the host network interfaces since the last
+// sample and adds them to the history of each interface
+func (ps *PageSysInfo) sampleNet() {
+
+	ioCount, err := net.IOCounters(true)
+	if err != nil {
+		ps.Printf("network IO Count: %s\n", err)
+		return
+	}
+	now := time.Now()
+
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	secs := now.Sub(ps.netTime).Seconds()
+	rates := make(map[string]netRates)
+	counters := make(map[string]net.IOCountersStat)
+
+	for _, k := range ioCount {
+		if k.Name == "lo" {
+			continue
+		}
+		counters[k.Name] = k
+
+		prev, ok := ps.netPrev[k.Name]
+		if !ok || ps.netTime.IsZero() {
+			continue
+		}
+		r := netRates{
+			rxPPS:   perSecond(k.PacketsRecv, prev.PacketsRecv, secs),
+			txPPS:   perSecond(k.PacketsSent, prev.PacketsSent, secs),
+			rxMbits: perSecond(k.BytesRecv, prev.BytesRecv, secs) * 8 / 1e6,
+			txMbits: perSecond(k.BytesSent, prev.BytesSent, secs) * 8 / 1e6,
+			errIn:   perSecond(k.Errin, prev.Errin, secs),
+			errOut:  perSecond(k.Errout, prev.Errout, secs),
+			dropIn:  perSecond(k.Dropin, prev.Dropin, secs),
+			dropOut: perSecond(k.Dropout, prev.Dropout, secs),
+		}
+		rates[k.Name] = r
+
+		h, ok := ps.netHist[k.Name]
+		if !ok {
+			h = portHistory{
+				rxPPS:   graphdata.NewSeries(graphHistory),
+				txPPS:   graphdata.NewSeries(graphHistory),
+				rxMbits: graphdata.NewSeries(graphHistory),
+				txMbits: graphdata.NewSeries(graphHistory),
+			}
+			ps.netHist[k.Name] = h
+		}
+		h.rxPPS.Add(now, r.rxPPS)
+		h.txPPS.Add(now, r.txPPS)
+		h.rxMbits.Add(now, r.rxMbits)
+		h.txMbits.Add(now, r.txMbits)
+	}
+
+	ps.netPrev = counters
+	ps.netRates = rates
+	ps.netTime = now
+}
+
+// Display the rates of the host network interfaces
 func (ps *PageSysInfo) displayHostNet(view *tview.Table) {
 
 	row := 0
@@ -293,18 +467,20 @@ func (ps *PageSysInfo) displayHostNet(view *tview.Table) {
 	}
 
 	titles := []string{
-		cz.Yellow("Name"),
-		cz.Yellow("IP Address"),
-		cz.Yellow("MTU"),
-		cz.Yellow("RX Pkts"),
-		cz.Yellow("TX Pkts"),
-		cz.Yellow("RX Err"),
-		cz.Yellow("TX Err"),
-		cz.Yellow("RX Drop"),
-		cz.Yellow("Tx Drop"),
-		cz.Yellow("Flags"),
-		cz.Yellow("MAC"),
-		cz.Yellow(" ", 20),
+		cz.Header("Name"),
+		cz.Header("IP Address"),
+		cz.Header("MTU"),
+		cz.Header("RX pps"),
+		cz.Header("TX pps"),
+		cz.Header("RX Mbit/s"),
+		cz.Header("TX Mbit/s"),
+		cz.Header("RX Err/s"),
+		cz.Header("TX Err/s"),
+		cz.Header("RX Drop/s"),
+		cz.Header("TX Drop/s"),
+		cz.Header("Flags"),
+		cz.Header("MAC"),
+		cz.Header(" ", 20),
 	}
 	row = TableSetHeaders(view, 0, 0, titles)
 
@@ -315,53 +491,55 @@ func (ps *PageSysInfo) displayHostNet(view *tview.Table) {
 		}
 		tableCell := tview.NewTableCell(value).
 			SetAlign(align).
-			SetSelectable(false)
+			SetSelectable(true)
 		ps.hostNet.SetCell(row, col, tableCell)
 		col++
 
 		return col
 	}
 
-	ioCount, err := net.IOCounters(true)
-	if err != nil {
-		ps.Printf("network IO Count: %s\n", err)
-		return
-	}
-
 	p := message.NewPrinter(language.English)
 
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	ps.netRows = ps.netRows[:0]
 	for _, f := range ifaces {
 		if f.Name == "lo" {
 			continue
 		}
+		ps.netRows = append(ps.netRows, f.Name)
+		if len(ps.netIface) == 0 {
+			ps.netIface = f.Name
+		}
 
-		col = setCell(row, 0, cz.LightBlue(f.Name), true)
+		col = setCell(row, 0, cz.Label(f.Name), true)
 		if len(f.Addrs) > 0 {
-			col = setCell(row, col, cz.Orange(f.Addrs[0].Addr), false)
+			col = setCell(row, col, cz.Address(f.Addrs[0].Addr), false)
 		} else {
 			col = setCell(row, col, " ", false)
 		}
-		col = setCell(row, col, cz.MediumSpringGreen(f.MTU), false)
-
-		for _, k := range ioCount {
-			if k.Name != f.Name {
-				continue
+		col = setCell(row, col, cz.Value(f.MTU), false)
+
+		// The rates are blank until the interface has two samples
+		rowData := []string{" ", " ", " ", " ", " ", " ", " ", " "}
+		if r, ok := ps.netRates[f.Name]; ok {
+			rowData = []string{
+				cz.Rate(p.Sprintf("%.0f", r.rxPPS)),
+				cz.Rate(p.Sprintf("%.0f", r.txPPS)),
+				cz.Rate(p.Sprintf("%.2f", r.rxMbits)),
+				cz.Rate(p.Sprintf("%.2f", r.txMbits)),
+				cz.Rate(p.Sprintf("%.0f", r.errIn)),
+				cz.Rate(p.Sprintf("%.0f", r.errOut)),
+				cz.Rate(p.Sprintf("%.0f", r.dropIn)),
+				cz.Rate(p.Sprintf("%.0f", r.dropOut)),
 			}
-			rowData := []string{
-				cz.Wheat(p.Sprintf("%d", k.PacketsRecv)),
-				cz.Wheat(p.Sprintf("%d", k.PacketsSent)),
-				cz.Red(p.Sprintf("%d", k.Errin)),
-				cz.Red(p.Sprintf("%d", k.Errout)),
-				cz.Red(p.Sprintf("%d", k.Dropin)),
-				cz.Red(p.Sprintf("%d", k.Dropout)),
-			}
-			for _, v := range rowData {
-				col = TableCellSet(ps.hostNet, row, col, v)
-			}
-			break
 		}
-		col = setCell(row, col, cz.LightSkyBlue(f.Flags), false)
-		setCell(row, col, cz.Cyan(f.HardwareAddr), false)
+		for _, v := range rowData {
+			col = setCell(row, col, v, false)
+		}
+		col = setCell(row, col, cz.Value(f.Flags), false)
+		setCell(row, col, cz.Address(f.HardwareAddr), false)
 
 		row++
 	}
@@ -371,6 +549,42 @@ func (ps *PageSysInfo) displayHostNet(view *tview.Table) {
 	}
 }
 
+// displayNetChart draws the RX and TX history of the selected interface
+func (ps *PageSysInfo) displayNetChart() {
+
+	view := ps.netChart
+
+	view.SetTitle(TitleColor(fmt.Sprintf("Interface (i) %s %s, last %v, r-Metric w/W-Window",
+		ps.netIface, graphMetrics[ps.netMetric], graphWindows[ps.netWindow])))
+
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	h, ok := ps.netHist[ps.netIface]
+	if !ok {
+		view.SetText(cz.Warning("No samples of the interface yet"))
+		return
+	}
+
+	if ps.netGraphs == nil {
+		ps.netGraphs = graphdata.NewGraph(2).
+			SetFieldWidth(12).
+			SetCombined(true)
+	}
+
+	series := []*graphdata.Series{h.rxPPS, h.txPPS}
+	if ps.netMetric > 0 {
+		series = []*graphdata.Series{h.rxMbits, h.txMbits}
+	}
+	for i, gd := range ps.netGraphs.Graphs() {
+		gd.SetName([]string{"RX", "TX"}[i]).SetColor(graphColors[i]).
+			SetSeries(series[i]).
+			SetWindow(graphWindows[ps.netWindow])
+	}
+
+	view.SetText(ps.netGraphs.MakeChart(view))
+}
+
 // Display the given devbind data panel for each window
 func (ps *PageSysInfo) displayPageSysInfo(step int) {
 	for _, ti := range ps.tInfos {
@@ -412,64 +626,54 @@ func (ps *PageSysInfo) displayView(ti *tableInfo) {
 	view := ti.view
 
 	titles := []string{
-		cz.CornSilk("Slot"),
-		cz.CornSilk("Vendor ID"),
-		cz.CornSilk("Vendor Name"),
-		cz.CornSilk("Device Description"),
-		cz.CornSilk("Interface"),
-		cz.CornSilk("Driver"),
-		cz.CornSilk("Active"),
-		cz.CornSilk("NUMA"),
+		cz.Header("Slot"),
+		cz.Header("Vendor ID"),
+		cz.Header("Vendor Name"),
+		cz.Header("Device Description"),
+		cz.Header("Interface"),
+		cz.Header("Driver"),
+		cz.Header("Active"),
+		cz.Header("NUMA"),
 	}
 	row := TableSetHeaders(view, 0, 0, titles)
 
 	for _, d := range ti.devlist {
 		col := 0
 
-		SetCell(view, row, col, cz.DeepPink(d.Slot), tview.AlignLeft)
+		SetCell(view, row, col, cz.Address(d.Slot), tview.AlignLeft, true)
 		col++
 
-		s := fmt.Sprintf("%s:%s", cz.SkyBlue(d.Vendor.ID), cz.SkyBlue(d.Device.ID))
-		SetCell(view, row, col, s, tview.AlignLeft)
+		s := fmt.Sprintf("%s:%s", cz.Value(d.Vendor.ID), cz.Value(d.Device.ID))
+		SetCell(view, row, col, s, tview.AlignLeft, true)
 		col++
 
-		str := d.Vendor.Str
-		idx := strings.Index(str, "[")
-		if idx != -1 {
-			str = str[:idx-1]
-		}
-		SetCell(view, row, col, cz.SkyBlue(str), tview.AlignLeft)
+		SetCell(view, row, col, cz.Value(tview.Escape(d.Vendor.Str)), tview.AlignLeft, true)
 		col++
 
-		str = d.SDevice.Str
-		idx = strings.Index(str, "[")
-		if idx != -1 {
-			str = str[:idx-1]
+		// Use the device name if the subsystem is not in the PCI IDs
+		str := d.SDevice.Str
+		if len(str) == 0 {
+			str = d.Device.Str
 		}
-		SetCell(view, row, col, cz.LightGreen(str), tview.AlignLeft)
+		SetCell(view, row, col, cz.Value(tview.Escape(str)), tview.AlignLeft, true)
 		col++
 
 		str = d.Interface
-		SetCell(view, row, col, cz.ColorWithName("Tomato", str), tview.AlignLeft)
+		SetCell(view, row, col, cz.Label(str), tview.AlignLeft, true)
 		col++
 
 		str = d.Driver
-		SetCell(view, row, col, cz.LightYellow(str), tview.AlignLeft)
+		SetCell(view, row, col, cz.Value(str), tview.AlignLeft, true)
 		col++
 
 		str = ""
 		if d.Active {
-			str = cz.Orange("*Active*")
+			str = cz.Warning("*Active*")
 		}
-		SetCell(view, row, col, str, tview.AlignLeft)
+		SetCell(view, row, col, str, tview.AlignLeft, true)
 		col++
 
-		str = d.NumaNode
-		idx = strings.Index(str, "[")
-		if idx != -1 {
-			str = str[:idx-1]
-		}
-		SetCell(view, row, col, cz.MistyRose(str), tview.AlignLeft)
+		SetCell(view, row, col, cz.Value(d.NumaNode), tview.AlignLeft, true)
 		col++
 
 		row++
@@ -477,3 +681,210 @@ func (ps *PageSysInfo) displayView(ti *tableInfo) {
 
 	ti.view.ScrollToBeginning()
 }
+
+// updateDetails displays the details when another network device is
+// selected, the ethtool settings are not read again for the same device.
+func (ps *PageSysInfo) updateDetails() {
+
+	ti, ok := ps.tInfos["Network"]
+	if !ok {
+		return
+	}
+	slot := ""
+	if d := ps.selectedDevice(ti); d != nil {
+		slot = d.Slot
+	}
+	if slot != ps.detailed {
+		ps.displayDetails()
+	}
+}
+
+// displayDetails displays the ethtool settings of the interfaces of the
+// selected network device
+func (ps *PageSysInfo) displayDetails() {
+
+	view := ps.details
+
+	ti, ok := ps.tInfos["Network"]
+	if !ok {
+		return
+	}
+	d := ps.selectedDevice(ti)
+	if d == nil {
+		ps.detailed = ""
+		view.SetText(cz.Warning("No network device selected"))
+		return
+	}
+	ps.detailed = d.Slot
+
+	if len(d.Interface) == 0 {
+		view.SetText(fmt.Sprintf("%s %s\n%s", cz.Label("Slot:"), cz.Address(d.Slot),
+			cz.Warning("No kernel interface, the driver is "+d.Driver)))
+		return
+	}
+
+	p := message.NewPrinter(language.English)
+	field := func(name, value string) string {
+		return fmt.Sprintf("%s %s\n", cz.Label(fmt.Sprintf("%-9s:", name)), value)
+	}
+	maxOf := func(cur, max uint32) string {
+		return fmt.Sprintf("%s/%s", cz.Value(p.Sprintf("%d", cur)), cz.Value(p.Sprintf("%d", max)))
+	}
+
+	str := ""
+	for _, iface := range strings.Split(d.Interface, ",") {
+		str += field("Interface", cz.Label(iface))
+
+		info, err := ethtool.Get(iface)
+		if err != nil {
+			str += cz.Error(err.Error()) + "\n\n"
+			continue
+		}
+
+		if dr := info.Driver; dr != nil {
+			str += field("Driver", cz.Value(dr.Driver)+" "+cz.Value(dr.Version))
+			fw := dr.Firmware
+			if len(fw) == 0 {
+				fw = "-"
+			}
+			str += field("Firmware", cz.Value(tview.Escape(fw)))
+		}
+		if l := info.Link; l != nil {
+			s := cz.Value(l.String())
+			if !l.Up {
+				s = cz.Warning(l.String())
+			}
+			str += field("Link", s)
+		}
+		if r := info.Rings; r != nil {
+			str += field("Rings", fmt.Sprintf("RX %s TX %s", maxOf(r.Rx, r.RxMax), maxOf(r.Tx, r.TxMax)))
+		}
+		if c := info.Channels; c != nil {
+			s := ""
+			for _, ch := range []struct {
+				name     string
+				cur, max uint32
+			}{
+				{"RX", c.Rx, c.RxMax},
+				{"TX", c.Tx, c.TxMax},
+				{"Other", c.Other, c.OtherMax},
+				{"Combined", c.Combined, c.CombinedMax},
+			} {
+				if ch.max > 0 {
+					s += fmt.Sprintf("%s %s ", ch.name, maxOf(ch.cur, ch.max))
+				}
+			}
+			str += field("Channels", s)
+		}
+		if len(info.Features) > 0 {
+			on, off := info.Offloads()
+			str += field("Offloads", cz.Value(strings.Join(on, " ")))
+			if len(off) > 0 {
+				str += field("Off", cz.Label(strings.Join(off, " ")))
+			}
+		}
+		str += "\n"
+	}
+	view.SetText(str)
+}
+
+// selectedDevice returns the device of the selected row of the device table
+func (ps *PageSysInfo) selectedDevice(ti *tableInfo) *devbind.DeviceClass {
+
+	row, _ := ti.view.GetSelection()
+	if row < 1 || row > len(ti.devlist) {
+		return nil
+	}
+	return ti.devlist[row-1]
+}
+
+// driverNames returns the kernel modules of the device and the DPDK modules
+func driverNames(dev *devbind.DeviceClass) []string {
+
+	names := []string{}
+	seen := make(map[string]bool)
+
+	add := func(name string) {
+		if len(name) > 0 && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	add(dev.Driver)
+	for _, m := range strings.FieldsFunc(dev.Module, func(r rune) bool { return r == ',' || r == ' ' }) {
+		add(m)
+	}
+	for _, m := range devbind.UioModules {
+		add(m)
+	}
+	return names
+}
+
+// showDriverForm displays the form to bind the selected device to a driver,
+// to unbind it or to set its driver override.
+func (ps *PageSysInfo) showDriverForm(pages *tview.Pages, ti *tableInfo) {
+
+	dev := ps.selectedDevice(ti)
+	if dev == nil {
+		return
+	}
+	db := ps.devbind
+
+	names := driverNames(dev)
+	driver := names[0]
+
+	status := tview.NewTextView().SetDynamicColors(true)
+
+	hide := func() {
+		pages.RemovePage(driverFormName)
+		ps.to.SetCurrentInputFocus()
+	}
+	result := func(msg string, err error) {
+		if err != nil {
+			status.SetText(cz.Error(err.Error()))
+			return
+		}
+		status.SetText(cz.Value(msg))
+
+		ti.changed = true
+		ps.displayView(ti)
+		ps.displayDetails()
+		ps.updatePreflight()
+	}
+
+	// The fields use the colors of the tview styles set by the theme
+	form := tview.NewForm().
+		SetItemPadding(0).
+		SetCancelFunc(hide)
+
+	current := dev.Driver
+	if len(current) == 0 {
+		current = "none"
+	}
+	status.SetText(fmt.Sprintf("%s %s", cz.Label("Current driver:"), cz.Value(current)))
+	form.AddDropDown("Driver   :", names, 0, func(option string, optionIndex int) {
+		driver = option
+	})
+
+	form.AddButton("Bind", func() {
+		result(fmt.Sprintf("%s bound to %s", dev.Slot, driver), db.Bind(dev.Slot, driver))
+	})
+	form.AddButton("Unbind", func() {
+		result(fmt.Sprintf("%s unbound", dev.Slot), db.Unbind(dev.Slot))
+	})
+	form.AddButton("Override", func() {
+		result(fmt.Sprintf("%s driver override set to %s", dev.Slot, driver),
+			db.SetDriverOverride(dev.Slot, driver))
+	})
+	form.AddButton("Cancel", hide)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true).
+		AddItem(status, 2, 0, false)
+
+	flex.SetTitle(TitleColor(fmt.Sprintf("Driver of %s %s", dev.Slot, dev.Interface))).
+		SetTitleAlign(tview.AlignLeft).
+		SetBorder(true)
+
+	pages.AddPage(driverFormName, CreateModal(flex, 60, 11), true, true)
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2022 Intel Corporation
+
+package main
+
+import (
+	"fmt"
+)
+
+// The single packet configuration is changed from the panels, the timer
+// routines and the API servers, the routines below serialize the access.
+
+// SingleConfig returns a copy of the single packet configuration of a port
+func SingleConfig(port int) (SinglePacketConfig, error) {
+
+	pktgen.singleLock.RLock()
+	defer pktgen.singleLock.RUnlock()
+
+	if port < 0 || port >= pktgen.portCnt {
+		return SinglePacketConfig{}, fmt.Errorf("invalid port %d", port)
+	}
+	return *pktgen.single[port], nil
+}
+
+// SetSingleConfig replaces the single packet configuration of a port, the
+// current transmit state of the port is kept.
+func SetSingleConfig(port int, sc *SinglePacketConfig) error {
+
+	pktgen.singleLock.Lock()
+	defer pktgen.singleLock.Unlock()
+
+	if port < 0 || port >= pktgen.portCnt {
+		return fmt.Errorf("invalid port %d", port)
+	}
+	c := *sc
+	c.PortIndex = port
+	c.TxState = pktgen.single[port].TxState
+	pktgen.single[port] = &c
+
+	return nil
+}
+
+// SetTxState starts or stops the traffic on the given ports, no ports means all ports
+func SetTxState(state bool, ports ...int) error {
+
+	pktgen.singleLock.Lock()
+	defer pktgen.singleLock.Unlock()
+
+	if len(ports) == 0 {
+		for port := 0; port < pktgen.portCnt; port++ {
+			pktgen.single[port].TxState = state
+		}
+		return nil
+	}
+
+	for _, port := range ports {
+		if port < 0 || port >= pktgen.portCnt {
+			return fmt.Errorf("invalid port %d", port)
+		}
+	}
+	for _, port := range ports {
+		pktgen.single[port].TxState = state
+	}
+	return nil
+}
+
+// Validate the single packet configuration, the limits are the same as the Edit form
+func (sc *SinglePacketConfig) Validate() error {
+
+	switch {
+	case sc.PercentRate <= 0 || sc.PercentRate > 100.0:
+		return fmt.Errorf("rate %v not in range (0-100]", sc.PercentRate)
+	case sc.PktSize < 64 || sc.PktSize > 1522:
+		return fmt.Errorf("packet size %d not in range 64-1522", sc.PktSize)
+	case sc.BurstCount < 32 || sc.BurstCount > 256:
+		return fmt.Errorf("burst %d not in range 32-256", sc.BurstCount)
+	case sc.TimeToLive > 255:
+		return fmt.Errorf("ttl %d larger than 255", sc.TimeToLive)
+	case sc.VlanId == 0 || sc.VlanId > 4095:
+		return fmt.Errorf("vlan id %d not in range 1-4095", sc.VlanId)
+	}
+
+	switch sc.PType {
+	case "IPv4", "IPv6", "ICMP":
+	default:
+		return fmt.Errorf("unknown packet type %q", sc.PType)
+	}
+	switch sc.ProtoType {
+	case "UDP", "TCP":
+	default:
+		return fmt.Errorf("unknown protocol %q", sc.ProtoType)
+	}
+
+	if sc.SrcIP.IP == nil || sc.DstIP.IP == nil {
+		return fmt.Errorf("source and destination IP addresses are required")
+	}
+	if len(sc.SrcMAC) == 0 || len(sc.DstMAC) == 0 {
+		return fmt.Errorf("source and destination MAC addresses are required")
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2022 Intel Corporation
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	cz "github.com/KeithWiles/go-pktgen/pkgs/colorize"
+	"github.com/KeithWiles/go-pktgen/pkgs/cpudata"
+	"github.com/KeithWiles/go-pktgen/pkgs/devbind"
+	"github.com/KeithWiles/go-pktgen/pkgs/preflight"
+	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
+)
+
+// The preflight command checks the tuning of the host for DPDK, the same
+// checks are displayed in the System panel. The exit status is exitError if
+// a check failed, a warning does not fail the command.
+
+// PreflightCommand is the preflight subcommand and its options
+type PreflightCommand struct {
+	Lcores string `long:"lcores" description:"Lcores of go-pktgen i.e. 2-5,8, default is the lcores of the configuration"`
+}
+
+var preflightCommand PreflightCommand
+
+func init() {
+	parser.SubcommandsOptional = true
+
+	_, err := parser.AddCommand("preflight", "Check the tuning of the system",
+		"Check the IOMMU, driver modules, CPU isolation, governor, hyperthreads, "+
+			"hugepages and NIC NUMA locality of the system and suggest fixes",
+		&preflightCommand)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// preflightActive returns true if the preflight command was given
+func preflightActive() bool {
+	return parser.Active != nil && parser.Active.Name == "preflight"
+}
+
+// newChecker returns the checks of the system for the lcores and the port
+// devices of the configuration
+func newChecker(db *devbind.BindInfo, lcores string) (*preflight.Checker, error) {
+
+	c := preflight.New(options.SysfsRoot, options.ProcRoot)
+
+	// Prefer vfio-pci, the other modules pass with a warning
+	c.Modules = []string{"vfio-pci"}
+	for _, m := range devbind.UioModules {
+		if m != "vfio-pci" {
+			c.Modules = append(c.Modules, m)
+		}
+	}
+
+	if c := loadedConfig(); len(lcores) == 0 && c != nil {
+		lcores = c.Lcores
+	}
+	if len(lcores) > 0 {
+		l, err := cpudata.ParseList(lcores)
+		if err != nil {
+			return nil, fmt.Errorf("lcores %q: %w", lcores, err)
+		}
+		c.Lcores = l
+	}
+
+	// The core map of the host does not describe a fixture tree
+	if options.SysfsRoot == preflight.DefaultSysfsRoot && pktgen.cpuData != nil {
+		c.Topology = pktgen.cpuData
+	}
+
+	devs := portDevices(db)
+	ports := make([]int, 0, len(devs))
+	for port := range devs {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+	for _, port := range ports {
+		c.Devices = append(c.Devices, devs[port].Slot)
+	}
+
+	return c, nil
+}
+
+// runPreflight prints the results of the checks and returns the exit status
+func runPreflight() int {
+
+	db, err := devbind.New(options.Devices...)
+	if err != nil {
+		fmt.Printf("device files: %s\n", err)
+		return exitError
+	}
+
+	c, err := newChecker(db, preflightCommand.Lcores)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return exitError
+	}
+
+	results := c.Run()
+	for _, r := range results {
+		fmt.Printf("%s %-15s %s\n", r.Status, r.Name, r.Detail)
+		if len(r.Fix) > 0 {
+			fmt.Printf("     %-15s fix: %s\n", "", r.Fix)
+		}
+	}
+
+	if preflight.Worst(results) == preflight.Fail {
+		return exitError
+	}
+	return exitPass
+}
+
+// preflightStatus returns the colored status of a check
+func preflightStatus(s preflight.Status) string {
+
+	switch s {
+	case preflight.Pass:
+		return cz.Rate(s.String())
+	case preflight.Warn:
+		return cz.Warning(s.String())
+	}
+	return cz.Error(s.String())
+}
+
+// updatePreflight runs the checks of the port devices and displays the
+// results, the checks run outside of the draw callback as they read many
+// files of the system.
+func (ps *PageSysInfo) updatePreflight() {
+
+	c, err := newChecker(ps.devbind, "")
+	if err != nil {
+		tlog.ErrorPrintf("Preflight: %v\n", err)
+		ps.preflight.SetText(cz.Error(err.Error()))
+		return
+	}
+
+	go func() {
+		str := ""
+		for _, r := range c.Run() {
+			str += fmt.Sprintf("%s %s: %s\n", preflightStatus(r.Status), cz.Label(r.Name), r.Detail)
+			if len(r.Fix) > 0 {
+				str += fmt.Sprintf("     %s\n", cz.Value("fix: "+r.Fix))
+			}
+		}
+		pktgen.app.QueueUpdateDraw(func() {
+			ps.preflight.SetText(str)
+		})
+	}()
+}
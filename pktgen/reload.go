@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2022 Intel Corporation
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/KeithWiles/go-pktgen/pkgs/cfg"
+	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
+)
+
+const (
+	configWatchName  = "ConfigWatch"
+	configReloadName = "ConfigReload"
+)
+
+// portStartup copies the queue counts, the lcores and the PCI device of the
+// configured port, the settings fixed when the port starts are not part of
+// the single packet configuration.
+func portStartup(c *cfg.Config, p *cfg.PortInfo) {
+
+	if c == nil {
+		return
+	}
+	if q := c.Port(p.Port); q != nil {
+		p.RxQueues = q.RxQueues
+		p.TxQueues = q.TxQueues
+		p.PCI = q.PCI
+		p.RxLcores = q.RxLcores
+		p.TxLcores = q.TxLcores
+	}
+}
+
+// configChanges compares the running port settings with the configuration
+// and returns the changes and the new single packet configurations.
+func configChanges(c *cfg.Config) ([]cfg.Change, map[int]SinglePacketConfig, error) {
+
+	pktgen.cfgLock.Lock()
+	loaded := pktgen.config
+	pktgen.cfgLock.Unlock()
+
+	var changes []cfg.Change
+	updates := make(map[int]SinglePacketConfig)
+
+	for _, p := range c.Ports {
+		sc, err := SingleConfig(p.Port)
+		if err != nil {
+			return nil, nil, err
+		}
+		prev := toPortInfo(&sc)
+		portStartup(loaded, prev)
+
+		if err := mergePortInfo(&sc, p); err != nil {
+			return nil, nil, err
+		}
+		next := toPortInfo(&sc)
+		portStartup(c, next)
+
+		if diff := cfg.DiffPort(prev, next); len(diff) > 0 {
+			changes = append(changes, diff...)
+			updates[p.Port] = sc
+		}
+	}
+	return changes, updates, nil
+}
+
+// reloadConfig parses the configuration file again and applies the changed
+// port settings to the stopped and running ports. A reload with a change
+// needing a restart of the ports is refused as a whole.
+func reloadConfig() ([]cfg.Change, error) {
+
+	pktgen.cfgLock.Lock()
+	path := pktgen.cfgPath
+	pktgen.cfgLock.Unlock()
+
+	if len(path) == 0 {
+		return nil, fmt.Errorf("no configuration file to reload")
+	}
+
+	sys, err := cfg.OpenWithFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, updates, err := configChanges(sys.Config())
+	if err != nil {
+		return nil, err
+	}
+
+	var refused []string
+	for _, c := range changes {
+		if c.Restart {
+			refused = append(refused, c.String())
+		}
+	}
+	if len(refused) > 0 {
+		return changes, fmt.Errorf("reload refused, restart go-pktgen to change:\n%s",
+			strings.Join(refused, "\n"))
+	}
+
+	for port, sc := range updates {
+		if err := SetSingleConfig(port, &sc); err != nil {
+			return nil, err
+		}
+	}
+
+	pktgen.cfgLock.Lock()
+	pktgen.config = sys.Config()
+	if info, err := os.Stat(path); err == nil {
+		pktgen.cfgModTime = info.ModTime()
+	}
+	pktgen.cfgLock.Unlock()
+
+	return changes, nil
+}
+
+// reload the configuration file and show the result of the reload
+func reload(reason string) {
+
+	tlog.Log(mainLog, "Reload configuration: %s\n", reason)
+
+	changes, err := reloadConfig()
+
+	var msg string
+	for _, c := range changes {
+		tlog.Log(mainLog, "  %s\n", c)
+		msg += c.String() + "\n"
+	}
+
+	switch {
+	case err != nil:
+		tlog.Log(mainLog, "Reload failed: %s\n", err)
+		msg = fmt.Sprintf("Reload failed: %s", err)
+	case len(changes) == 0:
+		msg = "No changes"
+	}
+
+	showMessage("Reload Configuration\n\n" + msg)
+}
+
+// watchConfig reloads the configuration file each time its modification
+// time changes, the file is checked once a second.
+func watchConfig() {
+
+	pktgen.timers.AddPeriodic(configWatchName, time.Second, func(count uint64) {
+		pktgen.cfgLock.Lock()
+		path, modTime := pktgen.cfgPath, pktgen.cfgModTime
+		pktgen.cfgLock.Unlock()
+
+		info, err := os.Stat(path)
+		if err != nil || info.ModTime().Equal(modTime) {
+			return
+		}
+
+		pktgen.cfgLock.Lock()
+		pktgen.cfgModTime = info.ModTime()
+		pktgen.cfgLock.Unlock()
+
+		// Let the writer of the file finish before reading it
+		pktgen.timers.AddOneShot(configReloadName, 100*time.Millisecond, func() {
+			reload("file changed")
+		})
+	})
+}
@@ -0,0 +1,296 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2022 Intel Corporation
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/KeithWiles/go-pktgen/pkgs/pktgenrpc"
+	tlog "github.com/KeithWiles/go-pktgen/pkgs/ttylog"
+)
+
+// grpcServer implements the Pktgen gRPC service
+type grpcServer struct {
+	pb.UnimplementedPktgenServer
+}
+
+const (
+	grpcLog = "GRPCLogID"
+)
+
+func init() {
+	tlog.Register(grpcLog)
+}
+
+// startGRPCServer listening on the given address i.e. localhost:50051
+func startGRPCServer(addr string) (*grpc.Server, error) {
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("gRPC listen on %s failed: %w", addr, err)
+	}
+
+	s := grpc.NewServer()
+	pb.RegisterPktgenServer(s, &grpcServer{})
+
+	go func() {
+		tlog.Log(grpcLog, "gRPC server listening on %s\n", lis.Addr())
+		if err := s.Serve(lis); err != nil {
+			tlog.ErrorPrintf("gRPC server failed: %v\n", err)
+		}
+	}()
+
+	return s, nil
+}
+
+// portList converts the requested ports to ints, an empty list is all ports
+func portList(ports []uint32) ([]int, error) {
+
+	list := make([]int, 0, len(ports))
+	for _, p := range ports {
+		if int(p) >= pktgen.portCnt {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid port %d", p)
+		}
+		list = append(list, int(p))
+	}
+	return list, nil
+}
+
+// toPortConfig converts the single packet configuration into a gRPC message
+func toPortConfig(sc *SinglePacketConfig) *pb.PortConfig {
+
+	return &pb.PortConfig{
+		Port:        uint32(sc.PortIndex),
+		TxCount:     sc.TxCount,
+		PercentRate: sc.PercentRate,
+		PktSize:     uint32(sc.PktSize),
+		BurstCount:  uint32(sc.BurstCount),
+		Ttl:         uint32(sc.TimeToLive),
+		SrcPort:     uint32(sc.SrcPort),
+		DstPort:     uint32(sc.DstPort),
+		Ptype:       sc.PType,
+		Proto:       sc.ProtoType,
+		VlanId:      uint32(sc.VlanId),
+		SrcIp:       sc.SrcIP.String(),
+		DstIp:       sc.DstIP.IP.String(),
+		SrcMac:      sc.SrcMAC.String(),
+		DstMac:      sc.DstMAC.String(),
+		TxState:     sc.TxState,
+	}
+}
+
+// fromPortConfig validates the gRPC message and converts it into a single
+// packet configuration.
+func fromPortConfig(pc *pb.PortConfig) (*SinglePacketConfig, error) {
+
+	sc := &SinglePacketConfig{
+		PortIndex:   int(pc.Port),
+		TxCount:     pc.TxCount,
+		PercentRate: pc.PercentRate,
+		PktSize:     uint16(pc.PktSize),
+		BurstCount:  uint16(pc.BurstCount),
+		TimeToLive:  uint16(pc.Ttl),
+		SrcPort:     uint16(pc.SrcPort),
+		DstPort:     uint16(pc.DstPort),
+		PType:       pc.Ptype,
+		ProtoType:   pc.Proto,
+		VlanId:      uint16(pc.VlanId),
+	}
+
+	// The message fields are wider than the configuration fields
+	if pc.PktSize > 0xffff || pc.BurstCount > 0xffff || pc.Ttl > 0xffff ||
+		pc.SrcPort > 0xffff || pc.DstPort > 0xffff || pc.VlanId > 0xffff {
+		return nil, fmt.Errorf("value larger than 65535")
+	}
+
+	ip, ipNet, err := net.ParseCIDR(pc.SrcIp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source IP: %w", err)
+	}
+	sc.SrcIP = net.IPNet{IP: ip, Mask: ipNet.Mask}
+
+	ip = net.ParseIP(pc.DstIp)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid destination IP %q", pc.DstIp)
+	}
+	sc.DstIP = net.IPNet{IP: ip, Mask: ip.DefaultMask()}
+
+	if sc.SrcMAC, err = net.ParseMAC(pc.SrcMac); err != nil {
+		return nil, fmt.Errorf("invalid source MAC: %w", err)
+	}
+	if sc.DstMAC, err = net.ParseMAC(pc.DstMac); err != nil {
+		return nil, fmt.Errorf("invalid destination MAC: %w", err)
+	}
+
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+// toStatsReply converts the snapshot of the given ports into a gRPC message
+func toStatsReply(snap *StatsSnapshot, ports []int) *pb.StatsReply {
+
+	reply := &pb.StatsReply{
+		Timestamp: timestamppb.New(snap.Timestamp),
+		Ticks:     snap.Ticks,
+	}
+
+	if len(ports) == 0 {
+		for port := range snap.Ports {
+			ports = append(ports, port)
+		}
+	}
+
+	for _, port := range ports {
+		ps := &snap.Ports[port]
+
+		reply.Ports = append(reply.Ports, &pb.PortStats{
+			Port:      uint32(ps.PortIndex),
+			LinkState: ps.LinkState,
+			RxPkts:    ps.RxPkts,
+			TxPkts:    ps.TxPkts,
+			RxBytes:   ps.RxBytes,
+			TxBytes:   ps.TxBytes,
+			RxErrors:  ps.RxErrors,
+			TxErrors:  ps.TxErrors,
+			RxPps:     ps.RxPPS,
+			TxPps:     ps.TxPPS,
+			RxMbits:   ps.RxMbits,
+			TxMbits:   ps.TxMbits,
+			RxMaxPps:  ps.RxMaxPPS,
+			TxMaxPps:  ps.TxMaxPPS,
+			Latency: &pb.Latency{
+				MinUsec: ps.Latency.Min,
+				AvgUsec: ps.Latency.Avg,
+				MaxUsec: ps.Latency.Max,
+				Samples: ps.Latency.Samples,
+			},
+		})
+	}
+
+	return reply
+}
+
+// GetVersion of go-pktgen
+func (s *grpcServer) GetVersion(ctx context.Context, req *pb.VersionRequest) (*pb.VersionReply, error) {
+
+	return &pb.VersionReply{Version: Version(), Info: PktgenInfo(false)}, nil
+}
+
+// ListPorts returns the configuration of all ports
+func (s *grpcServer) ListPorts(ctx context.Context, req *pb.ListPortsRequest) (*pb.ListPortsReply, error) {
+
+	reply := &pb.ListPortsReply{}
+
+	for port := 0; port < pktgen.portCnt; port++ {
+		sc, err := SingleConfig(port)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		reply.Ports = append(reply.Ports, toPortConfig(&sc))
+	}
+	return reply, nil
+}
+
+// GetPortConfig returns the configuration of a port
+func (s *grpcServer) GetPortConfig(ctx context.Context, req *pb.PortRequest) (*pb.PortConfig, error) {
+
+	sc, err := SingleConfig(int(req.Port))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return toPortConfig(&sc), nil
+}
+
+// SetPortConfig replaces the configuration of a port and returns the new configuration
+func (s *grpcServer) SetPortConfig(ctx context.Context, req *pb.PortConfig) (*pb.PortConfig, error) {
+
+	sc, err := fromPortConfig(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := SetSingleConfig(int(req.Port), sc); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	tlog.Log(grpcLog, "SetPortConfig: port %d\n", req.Port)
+
+	return s.GetPortConfig(ctx, &pb.PortRequest{Port: req.Port})
+}
+
+// setTraffic starts or stops the traffic on the selected ports
+func (s *grpcServer) setTraffic(req *pb.PortSelect, state bool) (*pb.TrafficReply, error) {
+
+	ports, err := portList(req.Ports)
+	if err != nil {
+		return nil, err
+	}
+	if err := SetTxState(state, ports...); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	reply := &pb.TrafficReply{Ports: req.Ports}
+	if len(req.Ports) == 0 {
+		for port := 0; port < pktgen.portCnt; port++ {
+			reply.Ports = append(reply.Ports, uint32(port))
+		}
+	}
+	return reply, nil
+}
+
+// StartTraffic on the selected ports
+func (s *grpcServer) StartTraffic(ctx context.Context, req *pb.PortSelect) (*pb.TrafficReply, error) {
+
+	return s.setTraffic(req, true)
+}
+
+// StopTraffic on the selected ports
+func (s *grpcServer) StopTraffic(ctx context.Context, req *pb.PortSelect) (*pb.TrafficReply, error) {
+
+	return s.setTraffic(req, false)
+}
+
+// GetStats returns the last collected statistics of the selected ports
+func (s *grpcServer) GetStats(ctx context.Context, req *pb.PortSelect) (*pb.StatsReply, error) {
+
+	ports, err := portList(req.Ports)
+	if err != nil {
+		return nil, err
+	}
+	return toStatsReply(pktgen.stats.Snapshot(), ports), nil
+}
+
+// StreamStats sends the statistics of the selected ports each time they are
+// collected until the client cancels the stream.
+func (s *grpcServer) StreamStats(req *pb.PortSelect, stream pb.Pktgen_StreamStatsServer) error {
+
+	ports, err := portList(req.Ports)
+	if err != nil {
+		return err
+	}
+
+	updates, cancel := pktgen.stats.Subscribe()
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case snap, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(toStatsReply(snap, ports)); err != nil {
+				return err
+			}
+		}
+	}
+}
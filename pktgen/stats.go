@@ -0,0 +1,292 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2022 Intel Corporation
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+)
+
+// The DPDK I/O engine is not attached to the Go frontend yet, the counters are
+// modelled from the single packet configuration of each port. A port sending
+// traffic is assumed to be looped back and receives every packet it sends.
+
+const (
+	statsTimerName = "PortStats"
+
+	// linkSpeedMbits is the speed of the modelled links
+	linkSpeedMbits = uint64(40000)
+)
+
+var broadcastMAC = net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+// LatencyBuckets are the upper bounds in microseconds of the latency histogram
+var LatencyBuckets = []float64{5, 10, 15, 20, 30, 50, 100, 250, 500, 1000}
+
+// LatencyStats - latency of the received packets in microseconds
+type LatencyStats struct {
+	Min, Avg, Max float64
+	Samples       uint64
+	Total         float64
+	Buckets       []uint64 // Samples per LatencyBuckets entry, not cumulative
+}
+
+// SizeStats - counters of the received packets by type and size
+type SizeStats struct {
+	Broadcast, Multicast uint64
+	Size64               uint64
+	Size65To127          uint64
+	Size128To255         uint64
+	Size256To511         uint64
+	Size512To1023        uint64
+	Size1024To1518       uint64
+	Runts, Jumbos        uint64
+	ARPs, ICMPs          uint64
+}
+
+// PortStats - counters and rates of a port
+type PortStats struct {
+	PortIndex          int
+	LinkState          string
+	RxPkts, TxPkts     uint64
+	RxBytes, TxBytes   uint64
+	RxErrors, TxErrors uint64
+	RxPPS, TxPPS       uint64
+	RxMbits, TxMbits   float64
+	RxMaxPPS, TxMaxPPS uint64
+	Latency            LatencyStats
+	Sizes              SizeStats
+	txRun              uint64 // Packets sent since the traffic was started
+	txState            bool   // TxState of the previous collection
+}
+
+// StatsSnapshot is a copy of all port statistics at a point in time
+type StatsSnapshot struct {
+	Timestamp time.Time
+	Ticks     uint64
+	Ports     []PortStats
+}
+
+// StatsInfo holds the port statistics and the subscribers to the updates
+type StatsInfo struct {
+	lock     sync.RWMutex
+	last     time.Time
+	snapshot StatsSnapshot
+	subs     map[chan *StatsSnapshot]struct{}
+}
+
+// newStatsInfo for the given number of ports
+func newStatsInfo(portCnt int) *StatsInfo {
+
+	si := &StatsInfo{subs: make(map[chan *StatsSnapshot]struct{})}
+
+	si.snapshot.Ports = make([]PortStats, portCnt)
+	for port := range si.snapshot.Ports {
+		si.snapshot.Ports[port].PortIndex = port
+		si.snapshot.Ports[port].LinkState = linkState()
+	}
+
+	return si
+}
+
+// linkState string of the modelled links
+func linkState() string {
+	return fmt.Sprintf("UP-%d-FD", linkSpeedMbits)
+}
+
+// linePPS is the number of packets per second of a given size at line rate
+func linePPS(pktSize uint16) float64 {
+	bits := float64((uint64(pktSize) - EtherCRCLen + PktOverheadSize) * 8)
+
+	return float64(linkSpeedMbits*Million) / bits
+}
+
+// Record a latency sample in microseconds
+func (ls *LatencyStats) Record(usec float64) {
+
+	if ls.Samples == 0 || usec < ls.Min {
+		ls.Min = usec
+	}
+	if usec > ls.Max {
+		ls.Max = usec
+	}
+	ls.Samples++
+	ls.Total += usec
+	ls.Avg = ls.Total / float64(ls.Samples)
+
+	if ls.Buckets == nil {
+		ls.Buckets = make([]uint64, len(LatencyBuckets))
+	}
+	for i, bound := range LatencyBuckets {
+		if usec <= bound {
+			ls.Buckets[i]++
+			break
+		}
+	}
+}
+
+// Add a number of received packets of the given configuration to the counters
+func (ss *SizeStats) Add(sc *SinglePacketConfig, pkts uint64) {
+
+	switch {
+	case sc.PktSize < 64:
+		ss.Runts += pkts
+	case sc.PktSize == 64:
+		ss.Size64 += pkts
+	case sc.PktSize < 128:
+		ss.Size65To127 += pkts
+	case sc.PktSize < 256:
+		ss.Size128To255 += pkts
+	case sc.PktSize < 512:
+		ss.Size256To511 += pkts
+	case sc.PktSize < 1024:
+		ss.Size512To1023 += pkts
+	case sc.PktSize <= 1518:
+		ss.Size1024To1518 += pkts
+	default:
+		ss.Jumbos += pkts
+	}
+
+	if len(sc.DstMAC) > 0 {
+		if bytes.Equal(sc.DstMAC, broadcastMAC) {
+			ss.Broadcast += pkts
+		} else if sc.DstMAC[0]&0x01 != 0 {
+			ss.Multicast += pkts
+		}
+	}
+	if sc.PType == "ICMP" {
+		ss.ICMPs += pkts
+	}
+}
+
+// Snapshot returns a copy of the current port statistics
+func (si *StatsInfo) Snapshot() *StatsSnapshot {
+
+	si.lock.RLock()
+	defer si.lock.RUnlock()
+
+	return si.copySnapshot()
+}
+
+func (si *StatsInfo) copySnapshot() *StatsSnapshot {
+
+	snap := si.snapshot
+	snap.Ports = make([]PortStats, len(si.snapshot.Ports))
+	copy(snap.Ports, si.snapshot.Ports)
+
+	for i := range snap.Ports {
+		b := snap.Ports[i].Latency.Buckets
+		snap.Ports[i].Latency.Buckets = append([]uint64(nil), b...)
+	}
+
+	return &snap
+}
+
+// Subscribe to the statistics updates, the returned function must be called
+// to stop the updates. A subscriber too slow to keep up misses updates.
+func (si *StatsInfo) Subscribe() (<-chan *StatsSnapshot, func()) {
+
+	ch := make(chan *StatsSnapshot, 1)
+
+	si.lock.Lock()
+	si.subs[ch] = struct{}{}
+	si.lock.Unlock()
+
+	return ch, func() {
+		si.lock.Lock()
+		defer si.lock.Unlock()
+
+		if _, ok := si.subs[ch]; ok {
+			delete(si.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Collect the port statistics and publish them to the subscribers
+func (si *StatsInfo) Collect(ticks uint64) {
+
+	now := time.Now()
+
+	si.lock.Lock()
+	defer si.lock.Unlock()
+
+	secs := 1.0
+	if !si.last.IsZero() {
+		secs = now.Sub(si.last).Seconds()
+	}
+	si.last = now
+
+	for port := range si.snapshot.Ports {
+		si.collectPort(&si.snapshot.Ports[port], secs)
+	}
+	si.snapshot.Timestamp = now
+	si.snapshot.Ticks = ticks
+
+	for ch := range si.subs {
+		select {
+		case ch <- si.copySnapshot():
+		default:
+		}
+	}
+}
+
+// collectPort models the traffic of a port over the given number of seconds
+func (si *StatsInfo) collectPort(ps *PortStats, secs float64) {
+
+	pktgen.singleLock.Lock()
+	defer pktgen.singleLock.Unlock()
+
+	sc := pktgen.single[ps.PortIndex]
+
+	if sc.TxState && !ps.txState {
+		ps.txRun = 0
+	}
+	ps.txState = sc.TxState
+
+	pps := uint64(0)
+	if sc.TxState {
+		pps = uint64(linePPS(sc.PktSize) * sc.PercentRate / 100.0)
+	}
+	pkts := uint64(float64(pps) * secs)
+
+	// Stop the port when the number of packets to send has been reached
+	if sc.TxState && sc.TxCount > 0 && (ps.txRun+pkts) >= sc.TxCount {
+		pkts = sc.TxCount - ps.txRun
+		sc.TxState = false
+		ps.txState = false
+	}
+	ps.txRun += pkts
+
+	octets := pkts * (uint64(sc.PktSize) - EtherCRCLen)
+
+	ps.TxPkts += pkts
+	ps.RxPkts += pkts
+	ps.TxBytes += octets
+	ps.RxBytes += octets
+	ps.Sizes.Add(sc, pkts)
+
+	ps.TxPPS = uint64(float64(pkts) / secs)
+	ps.RxPPS = ps.TxPPS
+	ps.TxMbits = BitRate(ps.TxPPS, uint64(float64(octets)/secs)) / float64(Million)
+	ps.RxMbits = ps.TxMbits
+
+	if ps.TxPPS > ps.TxMaxPPS {
+		ps.TxMaxPPS = ps.TxPPS
+	}
+	if ps.RxPPS > ps.RxMaxPPS {
+		ps.RxMaxPPS = ps.RxPPS
+	}
+
+	// The modelled latency grows with the load of the link, no jitter is
+	// added so the same load always gives the same latency
+	if pkts > 0 {
+		load := sc.PercentRate / 100.0
+		ps.Latency.Record(5.0 + 20.0*math.Pow(load, 4))
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright(c) 2022 Intel Corporation
+
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/KeithWiles/go-pktgen/pkgs/cfg"
+	cz "github.com/KeithWiles/go-pktgen/pkgs/colorize"
+	tab "github.com/KeithWiles/go-pktgen/pkgs/taborder"
+)
+
+// applyTheme sets the color theme of the configuration, the NO_COLOR
+// environment variable selects the monochrome theme. The theme must be set
+// before the panels are created.
+func applyTheme(ti *cfg.ThemeInfo) error {
+
+	name := cz.DarkTheme
+	if ti != nil && len(ti.Name) > 0 {
+		name = ti.Name
+	}
+	if cz.NoColorEnv() {
+		name = cz.MonochromeTheme
+	}
+
+	theme, err := cz.ThemeByName(name)
+	if err != nil {
+		return err
+	}
+
+	if ti != nil && !theme.Monochrome {
+		for role, color := range ti.Colors {
+			if err := theme.SetRole(cz.Role(role), color); err != nil {
+				return err
+			}
+		}
+	}
+
+	cz.SetTheme(theme)
+
+	// The borders, forms and modals of tview use its own styles
+	switch {
+	case theme.Monochrome:
+		tview.Styles = tview.Theme{
+			PrimitiveBackgroundColor:    tcell.ColorDefault,
+			ContrastBackgroundColor:     tcell.ColorDefault,
+			MoreContrastBackgroundColor: tcell.ColorDefault,
+			BorderColor:                 tcell.ColorDefault,
+			TitleColor:                  tcell.ColorDefault,
+			GraphicsColor:               tcell.ColorDefault,
+			PrimaryTextColor:            tcell.ColorDefault,
+			SecondaryTextColor:          tcell.ColorDefault,
+			TertiaryTextColor:           tcell.ColorDefault,
+			InverseTextColor:            tcell.ColorDefault,
+			ContrastSecondaryTextColor:  tcell.ColorDefault,
+		}
+		// The selected window keeps its double line border
+		tab.SetBorderColors(tcell.ColorDefault, tcell.ColorDefault)
+	case theme.Name == cz.LightTheme:
+		tview.Styles = tview.Theme{
+			PrimitiveBackgroundColor:    tcell.ColorDefault,
+			ContrastBackgroundColor:     tcell.ColorLightGray,
+			MoreContrastBackgroundColor: tcell.ColorSilver,
+			BorderColor:                 tcell.ColorBlack,
+			TitleColor:                  tcell.ColorBlack,
+			GraphicsColor:               tcell.ColorBlack,
+			PrimaryTextColor:            tcell.ColorBlack,
+			SecondaryTextColor:          tcell.ColorNavy,
+			TertiaryTextColor:           tcell.ColorGreen,
+			InverseTextColor:            tcell.ColorBlue,
+			ContrastSecondaryTextColor:  tcell.ColorDarkCyan,
+		}
+	}
+
+	return nil
+}